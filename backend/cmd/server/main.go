@@ -27,7 +27,7 @@ func main() {
 	}
 
 	// Run migrations
-	if err := db.Migrate(database); err != nil {
+	if err := db.Migrate(database, cfg); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
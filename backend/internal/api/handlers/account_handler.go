@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// AccountHandler handles personal data export and account deletion requests
+type AccountHandler struct {
+	accountService *services.AccountService
+}
+
+// NewAccountHandler creates a new AccountHandler
+func NewAccountHandler(accountService *services.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// RequestExport handles queuing a personal data export
+func (h *AccountHandler) RequestExport(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	export, err := h.accountService.RequestExport(userID.(uint))
+	if err != nil {
+		log.Printf("Failed to queue data export: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, export)
+}
+
+// ListExports handles listing the current user's data exports
+func (h *AccountHandler) ListExports(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	exports, err := h.accountService.ListExports(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, exports)
+}
+
+// GetExport handles checking the status of a data export
+func (h *AccountHandler) GetExport(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	export, err := h.accountService.GetExport(userID.(uint), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// DownloadExport handles downloading a finished data export
+func (h *AccountHandler) DownloadExport(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	path, err := h.accountService.GetExportFile(userID.(uint), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.FileAttachment(path, filepath.Base(path))
+}
+
+// RequestDeletion handles scheduling the current account for deletion.
+// It must be called with a login token; API keys cannot delete an account.
+func (h *AccountHandler) RequestDeletion(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	if method, _ := c.Get("authMethod"); method == "api_key" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account deletion requires a login token"})
+		return
+	}
+
+	user, err := h.accountService.RequestDeletion(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":               "Account scheduled for deletion",
+		"deletion_scheduled_at": user.DeletionScheduledAt,
+	})
+}
+
+// CancelDeletion handles keeping an account that is scheduled for deletion
+func (h *AccountHandler) CancelDeletion(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.accountService.CancelDeletion(userID.(uint)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deletion cancelled"})
+}
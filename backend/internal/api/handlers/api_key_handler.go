@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// APIKeyHandler handles API key and service account requests
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKeyRequest represents the request body for creating an API key
+type CreateAPIKeyRequest struct {
+	Name      string               `json:"name" binding:"required"`
+	Scopes    []models.APIKeyScope `json:"scopes"`
+	ExpiresAt *time.Time           `json:"expires_at"`
+}
+
+// List handles listing the current user's API keys
+func (h *APIKeyHandler) List(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	keys, err := h.apiKeyService.ListKeys(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// Create handles creating an API key for the current user
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	h.createKey(c, userID.(uint))
+}
+
+// Revoke handles revoking one of the current user's API keys
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.apiKeyService.RevokeKey(userID.(uint), uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
+
+// CreateServiceAccount handles creating a service account
+func (h *APIKeyHandler) CreateServiceAccount(c *gin.Context) {
+	var input struct {
+		Username string      `json:"username" binding:"required"`
+		Role     models.Role `json:"role"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Role == "" {
+		input.Role = models.RoleUser
+	}
+
+	user, err := h.apiKeyService.CreateServiceAccount(input.Username, input.Role)
+	if err != nil {
+		log.Printf("Failed to create service account: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// ListServiceAccountKeys handles listing the API keys of a service account
+func (h *APIKeyHandler) ListServiceAccountKeys(c *gin.Context) {
+	account, ok := h.getServiceAccount(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.apiKeyService.ListKeys(account.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// CreateServiceAccountKey handles issuing an API key for a service account
+func (h *APIKeyHandler) CreateServiceAccountKey(c *gin.Context) {
+	account, ok := h.getServiceAccount(c)
+	if !ok {
+		return
+	}
+	h.createKey(c, account.ID)
+}
+
+// RevokeServiceAccountKey handles revoking an API key of a service account
+func (h *APIKeyHandler) RevokeServiceAccountKey(c *gin.Context) {
+	account, ok := h.getServiceAccount(c)
+	if !ok {
+		return
+	}
+
+	keyID, err := strconv.ParseUint(c.Param("keyId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid key ID"})
+		return
+	}
+
+	if err := h.apiKeyService.RevokeKey(account.ID, uint(keyID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
+
+func (h *APIKeyHandler) createKey(c *gin.Context, userID uint) {
+	var input CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, plaintext, err := h.apiKeyService.CreateKey(userID, input.Name, input.Scopes, input.ExpiresAt)
+	if err != nil {
+		log.Printf("Failed to create API key: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"api_key": key,
+		"key":     plaintext, // Only returned once
+	})
+}
+
+func (h *APIKeyHandler) getServiceAccount(c *gin.Context) (*models.User, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service account ID"})
+		return nil, false
+	}
+
+	account, err := h.apiKeyService.GetServiceAccount(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	return account, true
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// CategoryHandler handles category related requests
+type CategoryHandler struct {
+	categoryService *services.CategoryService
+}
+
+// NewCategoryHandler creates a new CategoryHandler
+func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// List handles listing all categories
+func (h *CategoryHandler) List(c *gin.Context) {
+	categories, err := h.categoryService.ListCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// Get handles getting a single category with its subcategories
+func (h *CategoryHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	category, err := h.categoryService.GetCategory(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	SetETag(c, category.Version)
+	c.JSON(http.StatusOK, category)
+}
+
+// Create handles category creation
+func (h *CategoryHandler) Create(c *gin.Context) {
+	roleInterface, _ := c.Get("userRole")
+	userID, _ := c.Get("userID")
+	log.Printf("User ID: %v, Role: %v attempting to create category", userID, roleInterface)
+
+	var category models.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.categoryService.CreateCategory(&category); err != nil {
+		if errors.Is(err, services.ErrInvalidCategory) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// Update handles category updates
+func (h *CategoryHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	current, err := h.categoryService.GetCategory(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+	if !CheckIfMatch(c, current.Version) {
+		return
+	}
+
+	var category models.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category.ID = uint(id)
+	category.Version = current.Version
+	if err := h.categoryService.UpdateCategory(&category); err != nil {
+		if VersionConflict(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrInvalidCategory) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	SetETag(c, category.Version)
+	c.JSON(http.StatusOK, category)
+}
+
+// Delete handles category deletion; categories titles still belong to are kept
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	current, err := h.categoryService.GetCategory(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+	if !CheckIfMatch(c, current.Version) {
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(uint(id)); err != nil {
+		var inUse *services.CategoryInUseError
+		if errors.As(err, &inUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// CollectionHandler handles curated collections and the homepage
+type CollectionHandler struct {
+	collectionService *services.CollectionService
+}
+
+// NewCollectionHandler creates a new CollectionHandler
+func NewCollectionHandler(collectionService *services.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// CollectionItemsRequest represents the ordered titles of a collection
+type CollectionItemsRequest struct {
+	ContentIDs []uint `json:"content_ids"`
+}
+
+// HomeLayoutRequest represents the ordered rails of the homepage
+type HomeLayoutRequest struct {
+	Rails []models.HomeRail `json:"rails"`
+}
+
+// Home handles resolving the whole homepage layout for the viewer in one call
+func (h *CollectionHandler) Home(c *gin.Context) {
+	layout, err := h.collectionService.ResolveHome(viewerFromContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	languages := languagesFromRequest(c)
+	for i := range layout.Rails {
+		rail := &layout.Rails[i]
+		for j := range rail.Contents {
+			rail.Contents[j].Localize(languages)
+		}
+		for j := range rail.Episodes {
+			if rail.Episodes[j].Content != nil {
+				rail.Episodes[j].Content.Localize(languages)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, layout)
+}
+
+// GetPublic handles getting a collection with the titles the viewer may see
+func (h *CollectionHandler) GetPublic(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	collection, contents, err := h.collectionService.GetVisibleCollection(viewerFromContext(c), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	localizeContents(c, contents)
+	c.JSON(http.StatusOK, gin.H{
+		"collection": collection,
+		"contents":   contents,
+	})
+}
+
+// List handles listing all collections
+func (h *CollectionHandler) List(c *gin.Context) {
+	collections, err := h.collectionService.ListCollections()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collections)
+}
+
+// Get handles getting a collection with all of its items
+func (h *CollectionHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	collection, err := h.collectionService.GetCollection(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// Create handles collection creation
+func (h *CollectionHandler) Create(c *gin.Context) {
+	var collection models.Collection
+	if err := c.ShouldBindJSON(&collection); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection.ID = 0
+	collection.Items = nil
+	if err := h.collectionService.CreateCollection(&collection); err != nil {
+		log.Printf("Failed to create collection: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
+
+// Update handles updating the title, description and visibility window of a collection
+func (h *CollectionHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	collection, err := h.collectionService.GetCollection(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return
+	}
+	if err := c.ShouldBindJSON(collection); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection.ID = uint(id)
+	if err := h.collectionService.UpdateCollection(collection); err != nil {
+		log.Printf("Failed to update collection: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// Delete handles collection deletion
+func (h *CollectionHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	if err := h.collectionService.DeleteCollection(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted successfully"})
+}
+
+// SetItems handles replacing the ordered titles of a collection
+func (h *CollectionHandler) SetItems(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	var input CollectionItemsRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection, err := h.collectionService.SetItems(uint(id), input.ContentIDs)
+	if err != nil {
+		log.Printf("Failed to update items of collection %d: %v", id, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// GetLayout handles getting the configured homepage rails
+func (h *CollectionHandler) GetLayout(c *gin.Context) {
+	rails, err := h.collectionService.GetLayout()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rails": rails})
+}
+
+// SetLayout handles replacing the homepage rails
+func (h *CollectionHandler) SetLayout(c *gin.Context) {
+	var input HomeLayoutRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rails, err := h.collectionService.SetLayout(input.Rails)
+	if err != nil {
+		log.Printf("Failed to update home layout: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rails": rails})
+}
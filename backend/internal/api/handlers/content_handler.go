@@ -32,8 +32,10 @@ func NewContentHandler(contentService *services.ContentService, mediaService *se
 // CreateContentRequest represents the request body for creating content
 type CreateContentRequest struct {
 	Title         string             `form:"title" binding:"required"`
+	Slug          string             `form:"slug"`
 	Description   string             `form:"description"`
-	Type          models.ContentType `form:"type" binding:"required"`
+	Type          models.ContentType `form:"type"` // category name, for older clients
+	CategoryID    *uint              `form:"category_id"`
 	ReleaseDate   *time.Time         `form:"releaseDate"`
 	GenreIds      []uint             `form:"genreIds[]"` // Ubah ini untuk menerima array
 	StreamLinks   string             `form:"streamLinks"`
@@ -41,6 +43,20 @@ type CreateContentRequest struct {
 	Episodes      string             `form:"episodes"`
 	Rating        float32            `form:"rating"`
 	SeasonID      *uint              `form:"season_id"`
+	Maturity      string             `form:"maturity_rating"`
+	Titles        string             `form:"titles"`       // JSON array of alternative titles
+	Descriptions  string             `form:"descriptions"` // JSON array of localized descriptions
+
+	// Broadcast information
+	AiringStatus      models.AiringStatus `form:"airing_status"`
+	BroadcastDay      string              `form:"broadcast_day"`
+	BroadcastTime     string              `form:"broadcast_time"`
+	BroadcastTimezone string              `form:"broadcast_timezone"`
+	EpisodeCount      *int                `form:"episode_count"`
+
+	// Publishing
+	Status    models.PublishStatus `form:"status"`
+	PublishAt *time.Time           `form:"publish_at"`
 }
 
 type StreamLinkRequest struct {
@@ -59,6 +75,37 @@ type DownloadLinkRequest struct {
 	URL     string `json:"url"`
 }
 
+// FormEpisode is an episode sent inline with the content form, together with
+// all of its links
+type FormEpisode struct {
+	Title         string                `json:"title"`
+	Description   string                `json:"description"`
+	Type          models.EpisodeKind    `json:"type"`
+	EpisodeNumber int                   `json:"episodeNumber"`
+	SeasonNumber  int                   `json:"seasonNumber"`
+	StreamLinks   []StreamLinkRequest   `json:"streamLinks"`
+	DownloadLinks []DownloadLinkRequest `json:"downloadLinks"`
+	VideoField    string                `json:"videoField"`
+	Status        models.PublishStatus  `json:"status"`
+	PublishAt     *time.Time            `json:"publishAt"`
+}
+
+// formEpisodeKey identifies an episode of a title in the content form
+type formEpisodeKey struct {
+	kind          models.EpisodeKind
+	seasonNumber  int
+	episodeNumber int
+}
+
+// linkEpisodeID returns the episode the links of an inline episode belong to;
+// a movie's links belong to the title itself
+func linkEpisodeID(content *models.Content, episode *models.Episode) *uint {
+	if models.NewContentTypeHelper().IsMovie(content) {
+		return nil
+	}
+	return &episode.ID
+}
+
 // Create creates or updates content
 func (h *ContentHandler) Create(c *gin.Context) {
 	var input CreateContentRequest
@@ -67,31 +114,47 @@ func (h *ContentHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	userID, _ := c.Get("userID")
+	editor := userID.(uint)
 
 	// Check if content already exists by title
 	existingContent, err := h.contentService.GetContentByTitle(input.Title)
 	isUpdate := err == nil && existingContent != nil
 
-	content := &models.Content{
-		Title:       input.Title,
-		Description: input.Description,
-		Type:        string(input.Type),
-		ReleaseDate: input.ReleaseDate,
-		Rating:      input.Rating,
-		SeasonID:    input.SeasonID,
+	content := &models.Content{}
+	if isUpdate {
+		// Overwriting a title that already exists must be asked for; the conflict
+		// carries its ID and ETag so the client can retry with If-Match
+		if c.GetHeader("If-Match") == "" {
+			SetETag(c, existingContent.Version)
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "A title with this name already exists; send If-Match to update it",
+				"id":    existingContent.ID,
+			})
+			return
+		}
+		if !CheckIfMatch(c, existingContent.Version) {
+			return
+		}
+		content = existingContent
 	}
+	applyContentForm(c, content, &input)
 
 	if isUpdate {
-		content.ID = existingContent.ID
+		// The upsert may have matched an alternative title; keep the main one
+		content.Title = existingContent.Title
 		// Update existing content
-		if err := h.contentService.UpdateContent(content); err != nil {
+		if err := h.contentService.UpdateContent(editor, content); err != nil {
+			if VersionConflict(c, err) {
+				return
+			}
 			log.Printf("Failed to update content: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update content: %v", err)})
 			return
 		}
 	} else {
 		// Create new content
-		if err := h.contentService.CreateContent(content); err != nil {
+		if err := h.contentService.CreateContent(editor, content); err != nil {
 			log.Printf("Failed to create content: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create content: %v", err)})
 			return
@@ -101,7 +164,7 @@ func (h *ContentHandler) Create(c *gin.Context) {
 	// Handle cover image upload if provided
 	if file, err := c.FormFile("coverImage"); err == nil {
 		log.Printf("Cover image found, uploading for content ID: %d, filename: %s", content.ID, file.Filename)
-		if err := h.mediaService.UploadContentCover(content.ID, file); err != nil {
+		if err := h.mediaService.UploadContentCover(editor, content.ID, file); err != nil {
 			log.Printf("Failed to upload cover image: %v", err)
 			// Don't return error here, continue with other operations
 		} else {
@@ -122,22 +185,7 @@ func (h *ContentHandler) Create(c *gin.Context) {
 	if len(input.GenreIds) > 0 {
 		log.Printf("Processing genres for content %d: %v", content.ID, input.GenreIds)
 
-		// Clear existing genres first
-		if err := h.contentService.GetDB().Model(content).Association("Genres").Clear(); err != nil {
-			log.Printf("Failed to clear existing genres: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to clear genres: %v", err)})
-			return
-		}
-
-		// Add new genres
-		var genres []models.Genre
-		if err := h.contentService.GetDB().Where("id IN ?", input.GenreIds).Find(&genres).Error; err != nil {
-			log.Printf("Failed to fetch genres: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to fetch genres: %v", err)})
-			return
-		}
-
-		if err := h.contentService.GetDB().Model(content).Association("Genres").Replace(genres); err != nil {
+		if err := h.contentService.SetGenres(editor, content.ID, input.GenreIds); err != nil {
 			log.Printf("Failed to update genres: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update genres: %v", err)})
 			return
@@ -146,152 +194,236 @@ func (h *ContentHandler) Create(c *gin.Context) {
 		log.Printf("Successfully updated genres for content %d", content.ID)
 	}
 
+	// Process alternative titles and localized descriptions
+	if err := h.saveLocalizations(content.ID, &input); err != nil {
+		log.Printf("Failed to save localizations: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to save localizations: %v", err)})
+		return
+	}
+
 	// Process episodes if provided
-	if input.Episodes != "" {
-		var episodes []struct {
-			Title         string                `json:"title"`
-			Description   string                `json:"description"`
-			EpisodeNumber int                   `json:"episodeNumber"`
-			SeasonNumber  int                   `json:"seasonNumber"`
-			StreamLinks   []StreamLinkRequest   `json:"streamLinks"`
-			DownloadLinks []DownloadLinkRequest `json:"downloadLinks"`
-			VideoField    string                `json:"videoField"`
+	if input.Episodes != "" && !h.saveEpisodes(c, editor, content, input.Episodes) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Content %s successfully", map[bool]string{true: "updated", false: "created"}[isUpdate]),
+		"content": content,
+	})
+}
+
+// applyContentForm copies a submitted content form onto a content. Existing
+// titles keep the publishing state, maturity rating and broadcast details the
+// form leaves out: an empty status would publish a draft or scheduled title, an
+// empty rating would turn into the unrated one, which limited viewers may be
+// allowed to see, and empty broadcast details would drop the title from the schedule.
+func applyContentForm(c *gin.Context, content *models.Content, input *CreateContentRequest) {
+	sent := func(field string) bool {
+		if content.ID == 0 {
+			return true
 		}
+		_, ok := c.GetPostForm(field)
+		return ok
+	}
 
-		if err := json.Unmarshal([]byte(input.Episodes), &episodes); err != nil {
-			log.Printf("Failed to parse episodes: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to parse episodes: %v", err)})
-			return
+	content.Title = input.Title
+	content.Description = input.Description
+	content.Type = string(input.Type)
+	content.CategoryID = input.CategoryID
+	content.ReleaseDate = input.ReleaseDate
+	content.Rating = input.Rating
+	content.SeasonID = input.SeasonID
+	content.Slug = input.Slug
+
+	if sent("maturity_rating") {
+		content.MaturityRating = models.MaturityRating(input.Maturity)
+	}
+	if sent("airing_status") {
+		content.AiringStatus = input.AiringStatus
+	}
+	if sent("broadcast_day") {
+		content.BroadcastDay = input.BroadcastDay
+	}
+	if sent("broadcast_time") {
+		content.BroadcastTime = input.BroadcastTime
+	}
+	if sent("broadcast_timezone") {
+		content.BroadcastTimezone = input.BroadcastTimezone
+	}
+	if sent("episode_count") {
+		content.EpisodeCount = input.EpisodeCount
+	}
+	if sent("status") {
+		content.Status = input.Status
+	}
+	if sent("publish_at") {
+		content.PublishAt = input.PublishAt
+	}
+}
+
+// saveEpisodes applies the episodes sent with the content form. Episodes are
+// matched to the existing ones by kind, season and episode number and updated
+// in place, so they keep their ID and every field the form does not carry;
+// episodes missing from the form are moved to the trash. It writes the error
+// response and returns false if the episodes could not be saved.
+func (h *ContentHandler) saveEpisodes(c *gin.Context, editor uint, content *models.Content, raw string) bool {
+	var episodes []FormEpisode
+	if err := json.Unmarshal([]byte(raw), &episodes); err != nil {
+		log.Printf("Failed to parse episodes: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to parse episodes: %v", err)})
+		return false
+	}
+
+	existing, err := h.contentService.ListEpisodes(content.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to load episodes: %v", err)})
+		return false
+	}
+	byKey := make(map[formEpisodeKey]*models.Episode, len(existing))
+	for i := range existing {
+		episode := &existing[i]
+		key := formEpisodeKey{episode.Type, episode.SeasonNumber, episode.EpisodeNumber}
+		if _, ok := byKey[key]; !ok {
+			byKey[key] = episode
 		}
+	}
 
-		// Delete existing episodes and their links if updating
-		if isUpdate {
-			if err := h.contentService.DeleteContentEpisodes(content.ID); err != nil {
-				log.Printf("Failed to delete existing episodes: %v", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete existing episodes: %v", err)})
-				return
+	// A movie's links belong to the title, so they are collected over all of its episodes
+	type episodeLinks struct {
+		episodeID *uint
+		streams   []models.StreamLink
+		downloads []models.DownloadLink
+	}
+	links := []*episodeLinks{}
+	var titleLinks *episodeLinks
+
+	kept := make(map[uint]bool, len(episodes))
+	for _, ep := range episodes {
+		if ep.Type == "" {
+			ep.Type = models.EpisodeKindRegular
+		}
+		key := formEpisodeKey{ep.Type, ep.SeasonNumber, ep.EpisodeNumber}
+		episode, ok := byKey[key]
+		if !ok || kept[episode.ID] {
+			episode = &models.Episode{ContentID: content.ID, Type: ep.Type}
+		}
+		episode.Title = ep.Title
+		episode.Description = ep.Description
+		episode.EpisodeNumber = ep.EpisodeNumber
+		episode.SeasonNumber = ep.SeasonNumber
+
+		// Episodes sent without publishing fields keep their editorial state
+		if episode.ID == 0 || ep.Status != "" || ep.PublishAt != nil {
+			if episode.Status, episode.PublishAt, err = models.ResolvePublishState(ep.Status, ep.PublishAt, time.Now()); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return false
 			}
 		}
 
-		// Create new episodes
-		for _, ep := range episodes {
-			episode := &models.Episode{
-				ContentID:     content.ID,
-				Title:         ep.Title,
-				Description:   ep.Description,
-				Type:          "episode",
-				EpisodeNumber: ep.EpisodeNumber,
-				SeasonNumber:  ep.SeasonNumber,
+		if err := h.contentService.SaveEpisode(editor, episode); err != nil {
+			if VersionConflict(c, err) {
+				return false
 			}
+			log.Printf("Failed to save episode: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to save episode: %v", err)})
+			return false
+		}
+		kept[episode.ID] = true
 
-			// Create episode
-			if err := h.contentService.GetDB().Create(episode).Error; err != nil {
-				log.Printf("Failed to create episode: %v", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create episode: %v", err)})
-				return
+		target := &episodeLinks{episodeID: linkEpisodeID(content, episode)}
+		if target.episodeID == nil {
+			if titleLinks == nil {
+				titleLinks = target
+				links = append(links, target)
 			}
+			target = titleLinks
+		} else {
+			links = append(links, target)
+		}
 
-			// Process stream links
-			for _, sl := range ep.StreamLinks {
-				// Handle video upload for self-hosted streams
-				if sl.Type == "self-hosted" && sl.VideoField != "" {
-					file, err := c.FormFile(sl.VideoField)
-					if err != nil {
-						log.Printf("No video file found for field %s: %v", sl.VideoField, err)
-						continue
-					}
-
-					log.Printf("Processing video upload for episode %d", ep.EpisodeNumber)
-					videoPath, err := h.mediaService.UploadVideo(content.ID, &episode.ID, file)
-					if err != nil {
-						log.Printf("Failed to upload video: %v", err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to upload video: %v", err)})
-						return
-					}
-
-					// Update episode with video path
-					episode.VideoPath = videoPath
-					if err := h.contentService.GetDB().Save(episode).Error; err != nil {
-						log.Printf("Failed to update episode with video path: %v", err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update episode: %v", err)})
-						return
-					}
-
-					// Create stream link with video path
-					streamLink := &models.StreamLink{
-						ContentID:     content.ID,
-						Name:          sl.Name,
-						Quality:       sl.Quality,
-						Type:          sl.Type,
-						Server:        "local",
-						URL:           videoPath,
-						EpisodeNumber: ep.EpisodeNumber,
-						SeasonNumber:  ep.SeasonNumber,
-					}
-
-					if err := h.contentService.AddStreamLink(content.ID, streamLink); err != nil {
-						log.Printf("Failed to add stream link: %v", err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to add stream link: %v", err)})
-						return
-					}
-				} else {
-					// For external streams
-					streamLink := &models.StreamLink{
-						ContentID:     content.ID,
-						Name:          sl.Name,
-						Quality:       sl.Quality,
-						Type:          sl.Type,
-						Server:        "external",
-						URL:           sl.URL,
-						EpisodeNumber: ep.EpisodeNumber,
-						SeasonNumber:  ep.SeasonNumber,
-					}
-
-					// Handle embed type differently
-					if sl.Type == "embed" {
-						// For embed type, keep the full IFRAME tag
-						streamLink.Type = "embed"
-						// If URL doesn't contain IFRAME tag but is from known embed providers, wrap it in IFRAME
-						if !strings.Contains(strings.ToUpper(sl.URL), "IFRAME") {
-							// Add more providers as needed
-							if strings.Contains(sl.URL, "mp4upload.com") {
-								streamLink.URL = fmt.Sprintf(`<IFRAME SRC="%s" FRAMEBORDER=0 MARGINWIDTH=0 MARGINHEIGHT=0 SCROLLING=NO WIDTH=1280 HEIGHT=720 allowfullscreen></IFRAME>`, sl.URL)
-							} else {
-								streamLink.URL = sl.URL
-							}
-						}
-					}
-
-					if err := h.contentService.AddStreamLink(content.ID, streamLink); err != nil {
-						log.Printf("Failed to add stream link: %v", err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to add stream link: %v", err)})
-						return
-					}
-				}
+		// Process stream links
+		for i, sl := range ep.StreamLinks {
+			streamLink := models.StreamLink{
+				Name:     sl.Name,
+				Quality:  sl.Quality,
+				Type:     sl.Type,
+				Server:   "external",
+				URL:      sl.URL,
+				Priority: i,
 			}
 
-			// Process download links
-			for _, dl := range ep.DownloadLinks {
-				downloadLink := &models.DownloadLink{
-					ContentID:     content.ID,
-					Name:          dl.Name,
-					Quality:       dl.Quality,
-					URL:           dl.URL,
-					Server:        dl.Name,
-					EpisodeNumber: ep.EpisodeNumber,
-					SeasonNumber:  ep.SeasonNumber,
+			// Handle video upload for self-hosted streams
+			if sl.Type == "self-hosted" && sl.VideoField != "" {
+				file, err := c.FormFile(sl.VideoField)
+				if err != nil {
+					log.Printf("No video file found for field %s: %v", sl.VideoField, err)
+					continue
 				}
-				if err := h.contentService.AddDownloadLink(content.ID, downloadLink); err != nil {
-					log.Printf("Failed to add download link: %v", err)
+
+				log.Printf("Processing video upload for episode %d", ep.EpisodeNumber)
+				videoPath, err := h.mediaService.UploadVideo(content.ID, &episode.ID, file)
+				if err != nil {
+					log.Printf("Failed to upload video: %v", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to upload video: %v", err)})
+					return false
 				}
+
+				// Update episode with video path
+				episode.VideoPath = videoPath
+				if err := h.contentService.SaveEpisode(editor, episode); err != nil {
+					log.Printf("Failed to update episode with video path: %v", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update episode: %v", err)})
+					return false
+				}
+
+				streamLink.Server = "local"
+				streamLink.URL = videoPath
+			} else if sl.Type == "embed" && !strings.Contains(strings.ToUpper(sl.URL), "IFRAME") && strings.Contains(sl.URL, "mp4upload.com") {
+				// Known embed providers are wrapped in an IFRAME tag; add more providers as needed
+				streamLink.URL = fmt.Sprintf(`<IFRAME SRC="%s" FRAMEBORDER=0 MARGINWIDTH=0 MARGINHEIGHT=0 SCROLLING=NO WIDTH=1280 HEIGHT=720 allowfullscreen></IFRAME>`, sl.URL)
 			}
+			target.streams = append(target.streams, streamLink)
+		}
+
+		// Process download links
+		for i, dl := range ep.DownloadLinks {
+			target.downloads = append(target.downloads, models.DownloadLink{
+				Name:     dl.Name,
+				Quality:  dl.Quality,
+				URL:      dl.URL,
+				Server:   "external",
+				Priority: i,
+			})
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("Content %s successfully", map[bool]string{true: "updated", false: "created"}[isUpdate]),
-		"content": content,
-	})
+	for _, l := range links {
+		if err := h.contentService.ReplaceLinks(content.ID, l.episodeID, l.streams, l.downloads); err != nil {
+			log.Printf("Failed to save links: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to save links: %v", err)})
+			return false
+		}
+	}
+
+	// Episodes left out of the form were removed from it
+	removed := []uint{}
+	for _, episode := range existing {
+		if !kept[episode.ID] {
+			removed = append(removed, episode.ID)
+		}
+	}
+	if len(removed) > 0 {
+		if err := h.contentService.DeleteEpisodes(editor, removed); err != nil {
+			log.Printf("Failed to delete removed episodes: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete removed episodes: %v", err)})
+			return false
+		}
+	}
+
+	if err := h.contentService.NumberEpisodes(content.ID); err != nil {
+		log.Printf("Failed to number episodes: %v", err)
+	}
+	return true
 }
 
 // Get handles getting a single content
@@ -314,13 +446,16 @@ func (h *ContentHandler) Get(c *gin.Context) {
 	}
 
 	// Get content with preloaded relationships including genres
-	content, err := h.contentService.GetContentByID(uint(id))
+	content, err := h.contentService.GetVisibleContent(viewerFromContext(c), uint(id))
 	if err != nil {
 		log.Printf("Content not found: %v", err)
 		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
 		return
 	}
 
+	content.Localize(languagesFromRequest(c))
+	SetETag(c, content.Version)
+
 	// Log response untuk debugging
 	log.Printf("Content retrieved successfully: ID=%d, Title=%s, Genres=%v",
 		content.ID,
@@ -351,6 +486,34 @@ func (h *ContentHandler) List(c *gin.Context) {
 		filters["type"] = contentType
 	}
 
+	// Handle airing status filter
+	if status := c.Query("airing_status"); status != "" {
+		filters["airing_status"] = status
+	}
+
+	// Handle studio and person filters; a bad ID must not silently widen the list
+	if studioID := c.Query("studio"); studioID != "" {
+		id, err := strconv.ParseUint(studioID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid studio ID"})
+			return
+		}
+		filters["studio_id"] = uint(id)
+	}
+	if personID := c.Query("person"); personID != "" {
+		id, err := strconv.ParseUint(personID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+			return
+		}
+		filters["person_id"] = uint(id)
+	}
+
+	// Handle tag filters
+	if tags := tagFilterFromQuery(c); !tags.IsEmpty() {
+		filters["tags"] = tags
+	}
+
 	// Check for categoryId parameter
 	if categoryIDStr := c.Query("categoryId"); categoryIDStr != "" {
 		categoryID, err := strconv.ParseUint(categoryIDStr, 10, 32)
@@ -358,11 +521,12 @@ func (h *ContentHandler) List(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
 			return
 		}
-		contents, total, err := h.contentService.GetContentByCategory(uint(categoryID), page, pageSize)
+		contents, total, err := h.contentService.GetContentByCategory(viewerFromContext(c), uint(categoryID), page, pageSize)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		localizeContents(c, contents)
 		c.JSON(http.StatusOK, gin.H{
 			"contents": contents,
 			"total":    total,
@@ -372,12 +536,19 @@ func (h *ContentHandler) List(c *gin.Context) {
 		return
 	}
 
-	contents, total, err := h.contentService.ListContent(page, pageSize, filters)
+	sort := models.ContentSort(c.Query("sort"))
+	if !sort.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort, expected popularity, rating or newest"})
+		return
+	}
+
+	contents, total, err := h.contentService.ListContent(viewerFromContext(c), page, pageSize, filters, sort)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	localizeContents(c, contents)
 	c.JSON(http.StatusOK, gin.H{
 		"contents": contents,
 		"total":    total,
@@ -388,7 +559,7 @@ func (h *ContentHandler) List(c *gin.Context) {
 
 // Update handles content updates
 func (h *ContentHandler) Update(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	id, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
@@ -401,24 +572,25 @@ func (h *ContentHandler) Update(c *gin.Context) {
 		return
 	}
 
+	if !CheckIfMatch(c, existingContent.Version) {
+		return
+	}
+
 	var input CreateContentRequest
 	if err := c.ShouldBind(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	userID, _ := c.Get("userID")
+	editor := userID.(uint)
 
 	// Update content fields
-	existingContent.Title = input.Title
-	existingContent.Description = input.Description
-	existingContent.Type = string(input.Type)
-	existingContent.ReleaseDate = input.ReleaseDate
-	existingContent.Rating = input.Rating
-	existingContent.SeasonID = input.SeasonID
+	applyContentForm(c, existingContent, &input)
 
 	// Handle cover image update if provided
 	if file, err := c.FormFile("coverImage"); err == nil {
 		log.Printf("Updating cover image for contentID: %d with file: %s", existingContent.ID, file.Filename)
-		if err := h.mediaService.UploadContentCover(existingContent.ID, file); err != nil {
+		if err := h.mediaService.UploadContentCover(editor, existingContent.ID, file); err != nil {
 			log.Printf("Failed to update cover image: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update cover image: %v", err)})
 			return
@@ -429,6 +601,7 @@ func (h *ContentHandler) Update(c *gin.Context) {
 		updatedContent, err := h.contentService.GetContentByID(existingContent.ID)
 		if err == nil {
 			existingContent.CoverImage = updatedContent.CoverImage
+			existingContent.Version = updatedContent.Version
 			log.Printf("Updated content with cover image path: %s", existingContent.CoverImage)
 		}
 	} else {
@@ -436,154 +609,26 @@ func (h *ContentHandler) Update(c *gin.Context) {
 	}
 
 	// Update content in database
-	if err := h.contentService.UpdateContent(existingContent); err != nil {
+	if err := h.contentService.UpdateContent(editor, existingContent); err != nil {
+		if VersionConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Process episodes if any
-	if input.Episodes != "" {
-		var episodes []struct {
-			Title         string                `json:"title"`
-			Description   string                `json:"description"`
-			EpisodeNumber int                   `json:"episodeNumber"`
-			SeasonNumber  int                   `json:"seasonNumber"`
-			StreamLinks   []StreamLinkRequest   `json:"streamLinks"`
-			DownloadLinks []DownloadLinkRequest `json:"downloadLinks"`
-			VideoField    string                `json:"videoField"`
-		}
-
-		if err := json.Unmarshal([]byte(input.Episodes), &episodes); err != nil {
-			log.Printf("Failed to parse episodes: %v", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to parse episodes: %v", err)})
-			return
-		}
-
-		// Delete existing episodes and their links
-		if err := h.contentService.DeleteContentEpisodes(existingContent.ID); err != nil {
-			log.Printf("Failed to delete existing episodes: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete existing episodes: %v", err)})
-			return
-		}
-
-		// Create new episodes
-		for _, ep := range episodes {
-			episode := &models.Episode{
-				ContentID:     existingContent.ID,
-				Title:         ep.Title,
-				Description:   ep.Description,
-				Type:          "episode",
-				EpisodeNumber: ep.EpisodeNumber,
-				SeasonNumber:  ep.SeasonNumber,
-			}
-
-			// Create episode
-			if err := h.contentService.GetDB().Create(episode).Error; err != nil {
-				log.Printf("Failed to create episode: %v", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create episode: %v", err)})
-				return
-			}
-
-			// Process stream links
-			for _, sl := range ep.StreamLinks {
-				// Handle video upload for self-hosted streams
-				if sl.Type == "self-hosted" && sl.VideoField != "" {
-					file, err := c.FormFile(sl.VideoField)
-					if err != nil {
-						log.Printf("No video file found for field %s: %v", sl.VideoField, err)
-						continue
-					}
-
-					log.Printf("Processing video upload for episode %d", ep.EpisodeNumber)
-					videoPath, err := h.mediaService.UploadVideo(existingContent.ID, &episode.ID, file)
-					if err != nil {
-						log.Printf("Failed to upload video: %v", err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to upload video: %v", err)})
-						return
-					}
-
-					// Update episode with video path
-					episode.VideoPath = videoPath
-					if err := h.contentService.GetDB().Save(episode).Error; err != nil {
-						log.Printf("Failed to update episode with video path: %v", err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update episode: %v", err)})
-						return
-					}
-
-					// Create stream link with video path
-					streamLink := &models.StreamLink{
-						ContentID:     existingContent.ID,
-						Name:          sl.Name,
-						Quality:       sl.Quality,
-						Type:          sl.Type,
-						Server:        "local",
-						URL:           videoPath,
-						EpisodeNumber: ep.EpisodeNumber,
-						SeasonNumber:  ep.SeasonNumber,
-					}
-
-					if err := h.contentService.AddStreamLink(existingContent.ID, streamLink); err != nil {
-						log.Printf("Failed to add stream link: %v", err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to add stream link: %v", err)})
-						return
-					}
-				} else {
-					// For external streams
-					streamLink := &models.StreamLink{
-						ContentID:     existingContent.ID,
-						Name:          sl.Name,
-						Quality:       sl.Quality,
-						Type:          sl.Type,
-						Server:        "external",
-						URL:           sl.URL,
-						EpisodeNumber: ep.EpisodeNumber,
-						SeasonNumber:  ep.SeasonNumber,
-					}
-
-					// Handle embed type differently
-					if sl.Type == "embed" {
-						// For embed type, keep the full IFRAME tag
-						streamLink.Type = "embed"
-						// If URL doesn't contain IFRAME tag but is from known embed providers, wrap it in IFRAME
-						if !strings.Contains(strings.ToUpper(sl.URL), "IFRAME") {
-							// Add more providers as needed
-							if strings.Contains(sl.URL, "mp4upload.com") {
-								streamLink.URL = fmt.Sprintf(`<IFRAME SRC="%s" FRAMEBORDER=0 MARGINWIDTH=0 MARGINHEIGHT=0 SCROLLING=NO WIDTH=1280 HEIGHT=720 allowfullscreen></IFRAME>`, sl.URL)
-							} else {
-								streamLink.URL = sl.URL
-							}
-						}
-					}
-
-					if err := h.contentService.AddStreamLink(existingContent.ID, streamLink); err != nil {
-						log.Printf("Failed to add stream link: %v", err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to add stream link: %v", err)})
-						return
-					}
-				}
-			}
-
-			// Process download links
-			for _, dl := range ep.DownloadLinks {
-				downloadLink := &models.DownloadLink{
-					ContentID:     existingContent.ID,
-					Name:          dl.Name,
-					Quality:       dl.Quality,
-					URL:           dl.URL,
-					Server:        "external",
-					EpisodeNumber: ep.EpisodeNumber,
-					SeasonNumber:  ep.SeasonNumber,
-				}
+	// Process alternative titles and localized descriptions
+	if err := h.saveLocalizations(existingContent.ID, &input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to save localizations: %v", err)})
+		return
+	}
 
-				if err := h.contentService.AddDownloadLink(existingContent.ID, downloadLink); err != nil {
-					log.Printf("Failed to add download link: %v", err)
-					c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to add download link: %v", err)})
-					return
-				}
-			}
-		}
+	// Process episodes if any
+	if input.Episodes != "" && !h.saveEpisodes(c, editor, existingContent, input.Episodes) {
+		return
 	}
 
+	SetETag(c, existingContent.Version)
 	c.JSON(http.StatusOK, existingContent)
 }
 
@@ -613,9 +658,13 @@ func (h *ContentHandler) Delete(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
 		return
 	}
+	if !CheckIfMatch(c, content.Version) {
+		return
+	}
+	userID, _ := c.Get("userID")
 
 	// Delete content
-	if err := h.contentService.DeleteContent(content.ID); err != nil {
+	if err := h.contentService.DeleteContent(userID.(uint), content.ID); err != nil {
 		log.Printf("Failed to delete content: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete content"})
 		return
@@ -634,12 +683,13 @@ func (h *ContentHandler) Search(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "12"))
 
-	contents, total, err := h.contentService.SearchContent(term, page, pageSize)
+	contents, total, err := h.contentService.SearchContent(viewerFromContext(c), term, tagFilterFromQuery(c), page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	localizeContents(c, contents)
 	c.JSON(http.StatusOK, gin.H{
 		"contents": contents,
 		"total":    total,
@@ -659,12 +709,13 @@ func (h *ContentHandler) GetByGenre(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "12"))
 
-	contents, total, err := h.contentService.GetContentByGenre(uint(genreID), page, pageSize)
+	contents, total, err := h.contentService.GetContentByGenre(viewerFromContext(c), uint(genreID), page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	localizeContents(c, contents)
 	c.JSON(http.StatusOK, gin.H{
 		"contents": contents,
 		"total":    total,
@@ -676,7 +727,7 @@ func (h *ContentHandler) GetByGenre(c *gin.Context) {
 // GetByCategory handles getting content by category
 func (h *ContentHandler) GetByCategory(c *gin.Context) {
 	categoryID, err := strconv.ParseUint(c.Param("categoryId"), 10, 32)
-	log.Printf("Checking Log GetByCategory: %s", categoryID)
+	log.Printf("Checking Log GetByCategory: %d", categoryID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
 		return
@@ -685,12 +736,13 @@ func (h *ContentHandler) GetByCategory(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "12"))
 
-	contents, total, err := h.contentService.GetContentByCategory(uint(categoryID), page, pageSize)
+	contents, total, err := h.contentService.GetContentByCategory(viewerFromContext(c), uint(categoryID), page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	localizeContents(c, contents)
 	c.JSON(http.StatusOK, gin.H{
 		"contents": contents,
 		"total":    total,
@@ -699,6 +751,32 @@ func (h *ContentHandler) GetByCategory(c *gin.Context) {
 	})
 }
 
+// saveLocalizations stores the alternative titles and localized descriptions sent with a
+// create or update request. Fields that were not sent are left untouched.
+func (h *ContentHandler) saveLocalizations(contentID uint, input *CreateContentRequest) error {
+	if input.Titles != "" {
+		var titles []models.ContentTitle
+		if err := json.Unmarshal([]byte(input.Titles), &titles); err != nil {
+			return fmt.Errorf("invalid titles: %v", err)
+		}
+		if err := h.contentService.SetTitles(contentID, titles); err != nil {
+			return err
+		}
+	}
+
+	if input.Descriptions != "" {
+		var descriptions []models.ContentDescription
+		if err := json.Unmarshal([]byte(input.Descriptions), &descriptions); err != nil {
+			return fmt.Errorf("invalid descriptions: %v", err)
+		}
+		if err := h.contentService.SetDescriptions(contentID, descriptions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func init() {
 	// Buat direktori media jika belum ada
 	mediaDirs := []string{
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// ContentRelationHandler handles franchise relation requests
+type ContentRelationHandler struct {
+	relationService *services.ContentRelationService
+}
+
+// NewContentRelationHandler creates a new ContentRelationHandler
+func NewContentRelationHandler(relationService *services.ContentRelationService) *ContentRelationHandler {
+	return &ContentRelationHandler{
+		relationService: relationService,
+	}
+}
+
+// CreateRelationRequest represents the request body for linking two titles
+type CreateRelationRequest struct {
+	RelatedContentID uint                `json:"related_content_id" binding:"required"`
+	Type             models.RelationType `json:"type" binding:"required"`
+}
+
+// List handles listing the titles related to a title
+func (h *ContentRelationHandler) List(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	relations, err := h.relationService.ListRelations(viewerFromContext(c), uint(contentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, relations)
+}
+
+// WatchOrder handles getting the franchise of a title in release order
+func (h *ContentRelationHandler) WatchOrder(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	contents, err := h.relationService.GetWatchOrder(viewerFromContext(c), uint(contentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  contents,
+		"total": len(contents),
+	})
+}
+
+// Create handles linking a title to another one
+func (h *ContentRelationHandler) Create(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	var input CreateRelationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	relation, err := h.relationService.AddRelation(uint(contentID), input.RelatedContentID, input.Type)
+	if err != nil {
+		log.Printf("Failed to create relation: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, relation)
+}
+
+// Delete handles removing a relation and its inverse
+func (h *ContentRelationHandler) Delete(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+	relationID, err := strconv.ParseUint(c.Param("relationId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid relation ID"})
+		return
+	}
+
+	if err := h.relationService.RemoveRelation(uint(contentID), uint(relationID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Relation deleted successfully"})
+}
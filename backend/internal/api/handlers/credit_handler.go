@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// CreditHandler handles studio, person, character and content credit requests
+type CreditHandler struct {
+	creditService *services.CreditService
+}
+
+// NewCreditHandler creates a new CreditHandler
+func NewCreditHandler(creditService *services.CreditService) *CreditHandler {
+	return &CreditHandler{
+		creditService: creditService,
+	}
+}
+
+// ListStudios handles listing studios, optionally filtered with ?q=
+func (h *CreditHandler) ListStudios(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	studios, total, err := h.creditService.ListStudios(c.Query("q"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     studios,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// GetStudio handles getting a single studio
+func (h *CreditHandler) GetStudio(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid studio ID"})
+		return
+	}
+
+	studio, err := h.creditService.GetStudio(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Studio not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, studio)
+}
+
+// CreateStudio handles studio creation
+func (h *CreditHandler) CreateStudio(c *gin.Context) {
+	var studio models.Studio
+	if err := c.ShouldBindJSON(&studio); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	studio.ID = 0
+	if err := h.creditService.CreateStudio(&studio); err != nil {
+		log.Printf("Failed to create studio: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, studio)
+}
+
+// UpdateStudio handles studio updates
+func (h *CreditHandler) UpdateStudio(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid studio ID"})
+		return
+	}
+
+	var studio models.Studio
+	if err := c.ShouldBindJSON(&studio); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	studio.ID = uint(id)
+	if err := h.creditService.UpdateStudio(&studio); err != nil {
+		log.Printf("Failed to update studio: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, studio)
+}
+
+// DeleteStudio handles studio deletion
+func (h *CreditHandler) DeleteStudio(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid studio ID"})
+		return
+	}
+
+	if err := h.creditService.DeleteStudio(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Studio deleted successfully"})
+}
+
+// ListPeople handles listing people, optionally filtered with ?q=
+func (h *CreditHandler) ListPeople(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	people, total, err := h.creditService.ListPeople(c.Query("q"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     people,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// GetPerson handles getting a single person
+func (h *CreditHandler) GetPerson(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	person, err := h.creditService.GetPerson(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+}
+
+// CreatePerson handles person creation
+func (h *CreditHandler) CreatePerson(c *gin.Context) {
+	var person models.Person
+	if err := c.ShouldBindJSON(&person); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	person.ID = 0
+	if err := h.creditService.CreatePerson(&person); err != nil {
+		log.Printf("Failed to create person: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, person)
+}
+
+// UpdatePerson handles person updates
+func (h *CreditHandler) UpdatePerson(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	var person models.Person
+	if err := c.ShouldBindJSON(&person); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	person.ID = uint(id)
+	if err := h.creditService.UpdatePerson(&person); err != nil {
+		log.Printf("Failed to update person: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, person)
+}
+
+// DeletePerson handles person deletion
+func (h *CreditHandler) DeletePerson(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	if err := h.creditService.DeletePerson(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Person deleted successfully"})
+}
+
+// ListCharacters handles listing characters, optionally filtered with ?q=
+func (h *CreditHandler) ListCharacters(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	characters, total, err := h.creditService.ListCharacters(c.Query("q"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     characters,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// GetCharacter handles getting a single character
+func (h *CreditHandler) GetCharacter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid character ID"})
+		return
+	}
+
+	character, err := h.creditService.GetCharacter(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Character not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, character)
+}
+
+// CreateCharacter handles character creation
+func (h *CreditHandler) CreateCharacter(c *gin.Context) {
+	var character models.Character
+	if err := c.ShouldBindJSON(&character); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	character.ID = 0
+	if err := h.creditService.CreateCharacter(&character); err != nil {
+		log.Printf("Failed to create character: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, character)
+}
+
+// UpdateCharacter handles character updates
+func (h *CreditHandler) UpdateCharacter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid character ID"})
+		return
+	}
+
+	var character models.Character
+	if err := c.ShouldBindJSON(&character); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	character.ID = uint(id)
+	if err := h.creditService.UpdateCharacter(&character); err != nil {
+		log.Printf("Failed to update character: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, character)
+}
+
+// DeleteCharacter handles character deletion
+func (h *CreditHandler) DeleteCharacter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid character ID"})
+		return
+	}
+
+	if err := h.creditService.DeleteCharacter(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Character deleted successfully"})
+}
+
+// GetContentCredits handles getting the studios, staff and cast of a content
+func (h *CreditHandler) GetContentCredits(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	credits, err := h.creditService.GetContentCredits(viewerFromContext(c), uint(contentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, credits)
+}
+
+// SetContentCredits handles replacing the studios, staff and cast of a content
+func (h *CreditHandler) SetContentCredits(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	var credits models.ContentCredits
+	if err := c.ShouldBindJSON(&credits); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.creditService.SetContentCredits(uint(contentID), &credits); err != nil {
+		log.Printf("Failed to set credits for content %d: %v", contentID, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, credits)
+}
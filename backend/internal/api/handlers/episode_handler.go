@@ -22,6 +22,21 @@ func NewEpisodeHandler(episodeService *services.EpisodeService) *EpisodeHandler
 	}
 }
 
+// EpisodeCoursRequest represents the split-cour mapping of a content
+type EpisodeCoursRequest struct {
+	Cours []models.EpisodeCour `json:"cours"`
+}
+
+// StoryArcsRequest represents the story arcs of a content
+type StoryArcsRequest struct {
+	Arcs []models.StoryArc `json:"arcs"`
+}
+
+// ChaptersRequest represents the chapters of an episode
+type ChaptersRequest struct {
+	Chapters []models.Chapter `json:"chapters"`
+}
+
 // Create handles episode creation
 func (h *EpisodeHandler) Create(c *gin.Context) {
 	var episode models.Episode
@@ -29,29 +44,32 @@ func (h *EpisodeHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	userID, _ := c.Get("userID")
 
-	if err := h.episodeService.CreateEpisode(&episode); err != nil {
+	if err := h.episodeService.CreateEpisode(userID.(uint), &episode); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	SetETag(c, episode.Version)
 	c.JSON(http.StatusCreated, episode)
 }
 
 // Get handles getting a single episode
 func (h *EpisodeHandler) Get(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	id, err := strconv.ParseUint(c.Param("episodeId"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
 
-	episode, err := h.episodeService.GetEpisodeByID(uint(id))
+	episode, err := h.episodeService.GetVisibleEpisode(viewerFromContext(c), uint(id))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Episode not found"})
 		return
 	}
 
+	SetETag(c, episode.Version)
 	c.JSON(http.StatusOK, episode)
 }
 
@@ -68,14 +86,18 @@ func (h *EpisodeHandler) List(c *gin.Context) {
 	}
 
 	// Verify content exists first
-	content, err := h.episodeService.GetContent(uint(contentID))
+	content, err := h.episodeService.GetContent(viewerFromContext(c), uint(contentID))
 	if err != nil {
 		log.Printf("Content not found: %v", err)
 		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
 		return
 	}
 
-	var season *int
+	// Specials are listed unless ?specials=false; filler is dropped with ?skip_filler=true
+	filter := models.EpisodeFilter{
+		IncludeSpecials: c.DefaultQuery("specials", "true") != "false",
+		SkipFiller:      c.Query("skip_filler") == "true",
+	}
 	if seasonStr := c.Query("season"); seasonStr != "" {
 		seasonInt, err := strconv.Atoi(seasonStr)
 		if err != nil {
@@ -83,13 +105,21 @@ func (h *EpisodeHandler) List(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season number"})
 			return
 		}
-		season = &seasonInt
+		filter.Season = &seasonInt
+	}
+	if courStr := c.Query("cour"); courStr != "" {
+		courInt, err := strconv.Atoi(courStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cour number"})
+			return
+		}
+		filter.Cour = &courInt
 	}
 
-	episodes, err := h.episodeService.ListEpisodes(uint(contentID), season)
+	episodes, err := h.episodeService.ListEpisodes(viewerFromContext(c), uint(contentID), filter)
 	if err != nil {
 		log.Printf("Failed to list episodes: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -102,7 +132,7 @@ func (h *EpisodeHandler) List(c *gin.Context) {
 
 // Update handles episode updates
 func (h *EpisodeHandler) Update(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	id, err := strconv.ParseUint(c.Param("episodeId"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
@@ -114,28 +144,49 @@ func (h *EpisodeHandler) Update(c *gin.Context) {
 		return
 	}
 
+	if !CheckIfMatch(c, episode.Version) {
+		return
+	}
+	version := episode.Version
+
 	if err := c.ShouldBindJSON(episode); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	episode.Version = version
+	userID, _ := c.Get("userID")
 
-	if err := h.episodeService.UpdateEpisode(episode); err != nil {
+	if err := h.episodeService.UpdateEpisode(userID.(uint), episode); err != nil {
+		if VersionConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	SetETag(c, episode.Version)
 	c.JSON(http.StatusOK, episode)
 }
 
 // Delete handles episode deletion
 func (h *EpisodeHandler) Delete(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	id, err := strconv.ParseUint(c.Param("episodeId"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
 
-	if err := h.episodeService.DeleteEpisode(uint(id)); err != nil {
+	episode, err := h.episodeService.GetEpisodeByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Episode not found"})
+		return
+	}
+	if !CheckIfMatch(c, episode.Version) {
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	if err := h.episodeService.DeleteEpisode(userID.(uint), uint(id)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -143,8 +194,20 @@ func (h *EpisodeHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Episode deleted successfully"})
 }
 
-// GetNext handles getting the next episode
+// GetNext handles getting the next episode. Specials are skipped unless
+// ?specials=true; filler is skipped with ?skip_filler=true.
 func (h *EpisodeHandler) GetNext(c *gin.Context) {
+	h.neighbour(c, h.episodeService.GetNextEpisode, "No next episode found")
+}
+
+// GetPrevious handles getting the previous episode. Specials are skipped unless
+// ?specials=true; filler is skipped with ?skip_filler=true.
+func (h *EpisodeHandler) GetPrevious(c *gin.Context) {
+	h.neighbour(c, h.episodeService.GetPreviousEpisode, "No previous episode found")
+}
+
+// neighbour looks up the episode next to the one given by ?season and ?episode
+func (h *EpisodeHandler) neighbour(c *gin.Context, find func(*models.Viewer, uint, int, int, models.EpisodeFilter) (*models.Episode, error), notFound string) {
 	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
@@ -163,15 +226,160 @@ func (h *EpisodeHandler) GetNext(c *gin.Context) {
 		return
 	}
 
-	episode, err := h.episodeService.GetNextEpisode(uint(contentID), currentSeason, currentEpisode)
+	filter := models.EpisodeFilter{
+		IncludeSpecials: c.Query("specials") == "true",
+		SkipFiller:      c.Query("skip_filler") == "true",
+	}
+	episode, err := find(viewerFromContext(c), uint(contentID), currentSeason, currentEpisode, filter)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No next episode found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
 		return
 	}
 
 	c.JSON(http.StatusOK, episode)
 }
 
+// GetByAbsoluteNumber handles getting a regular episode by its number counted across seasons
+func (h *EpisodeHandler) GetByAbsoluteNumber(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid episode number"})
+		return
+	}
+
+	episode, err := h.episodeService.GetEpisodeByAbsoluteNumber(viewerFromContext(c), uint(contentID), number)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Episode not found"})
+		return
+	}
+
+	SetETag(c, episode.Version)
+	c.JSON(http.StatusOK, episode)
+}
+
+// ListCours handles listing how the split-cour seasons of a content are divided
+func (h *EpisodeHandler) ListCours(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	if _, err := h.episodeService.GetContent(viewerFromContext(c), uint(contentID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
+
+	cours, err := h.episodeService.ListCours(uint(contentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cours": cours})
+}
+
+// SetCours handles replacing the split-cour mapping of a content
+func (h *EpisodeHandler) SetCours(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	var input EpisodeCoursRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	cours, err := h.episodeService.SetCours(userID.(uint), uint(contentID), input.Cours)
+	if err != nil {
+		log.Printf("Failed to update cours of content %d: %v", contentID, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cours": cours})
+}
+
+// Guide handles getting the episode guide of a content, grouped by story arc
+func (h *EpisodeHandler) Guide(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	viewer := viewerFromContext(c)
+	if _, err := h.episodeService.GetContent(viewer, uint(contentID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
+
+	guide, err := h.episodeService.GetGuide(viewer, uint(contentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, guide)
+}
+
+// ListArcs handles listing the story arcs of a content
+func (h *EpisodeHandler) ListArcs(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	if _, err := h.episodeService.GetContent(viewerFromContext(c), uint(contentID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
+
+	arcs, err := h.episodeService.ListArcs(uint(contentID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"arcs": arcs})
+}
+
+// SetArcs handles replacing all story arcs of a content at once
+func (h *EpisodeHandler) SetArcs(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	var input StoryArcsRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	arcs, err := h.episodeService.SetArcs(userID.(uint), uint(contentID), input.Arcs)
+	if err != nil {
+		log.Printf("Failed to update arcs of content %d: %v", contentID, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"arcs": arcs})
+}
+
 // GetLatest handles getting the latest episode
 func (h *EpisodeHandler) GetLatest(c *gin.Context) {
 	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
@@ -180,7 +388,7 @@ func (h *EpisodeHandler) GetLatest(c *gin.Context) {
 		return
 	}
 
-	episode, err := h.episodeService.GetLatestEpisode(uint(contentID))
+	episode, err := h.episodeService.GetLatestEpisode(viewerFromContext(c), uint(contentID))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "No episodes found"})
 		return
@@ -188,3 +396,68 @@ func (h *EpisodeHandler) GetLatest(c *gin.Context) {
 
 	c.JSON(http.StatusOK, episode)
 }
+
+// ListChapters handles listing the chapters of an episode
+func (h *EpisodeHandler) ListChapters(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("episodeId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	episode, err := h.episodeService.GetVisibleEpisode(viewerFromContext(c), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Episode not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"chapters": episode.Chapters})
+}
+
+// ChaptersTrack handles serving the chapters of an episode as a WebVTT chapters track
+func (h *EpisodeHandler) ChaptersTrack(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("episodeId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	episode, err := h.episodeService.GetVisibleEpisode(viewerFromContext(c), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Episode not found"})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/vtt; charset=utf-8", []byte(services.ChaptersWebVTT(episode.Chapters)))
+}
+
+// SetChapters handles replacing the chapters of an episode
+func (h *EpisodeHandler) SetChapters(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("episodeId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	episode, err := h.episodeService.GetEpisodeByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Episode not found"})
+		return
+	}
+
+	var input ChaptersRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	chapters, err := h.episodeService.SetChapters(userID.(uint), episode, input.Chapters)
+	if err != nil {
+		log.Printf("Failed to update chapters of episode %d: %v", id, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"chapters": chapters})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+// SetETag exposes the version of a record as its ETag
+func SetETag(c *gin.Context, version uint) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// CheckIfMatch verifies that a write is based on the current version of a
+// record. It answers 428 when If-Match is missing and 412 when it is stale.
+func CheckIfMatch(c *gin.Context, current uint) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header is required"})
+		return false
+	}
+	if !etagMatches(header, current) {
+		SetETag(c, current)
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": models.ErrVersionConflict.Error()})
+		return false
+	}
+	return true
+}
+
+// etagMatches reports whether an If-Match value names the given version;
+// weak validators are accepted since the representation varies by language
+func etagMatches(header string, version uint) bool {
+	want := fmt.Sprintf(`"%d"`, version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// VersionConflict answers 412 if a write lost the race against another one
+func VersionConflict(c *gin.Context, err error) bool {
+	if !errors.Is(err, models.ErrVersionConflict) {
+		return false
+	}
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	return true
+}
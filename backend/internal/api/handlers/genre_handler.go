@@ -70,6 +70,7 @@ func (h *GenreHandler) Get(c *gin.Context) {
 		return
 	}
 
+	SetETag(c, genre.Version)
 	log.Printf("Retrieved genre: %s", genre.Name)
 	c.JSON(http.StatusOK, genre)
 }
@@ -83,6 +84,15 @@ func (h *GenreHandler) Update(c *gin.Context) {
 		return
 	}
 
+	current, err := h.genreService.GetGenreByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Genre not found"})
+		return
+	}
+	if !CheckIfMatch(c, current.Version) {
+		return
+	}
+
 	var genre models.Genre
 	if err := c.ShouldBindJSON(&genre); err != nil {
 		log.Printf("Failed to bind genre data: %v", err)
@@ -91,13 +101,18 @@ func (h *GenreHandler) Update(c *gin.Context) {
 	}
 
 	genre.ID = uint(id)
+	genre.Version = current.Version
 	if err := h.genreService.UpdateGenre(&genre); err != nil {
+		if VersionConflict(c, err) {
+			return
+		}
 		log.Printf("Failed to update genre: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	log.Printf("Genre updated successfully: %s", genre.Name)
+	SetETag(c, genre.Version)
 	c.JSON(http.StatusOK, genre)
 }
 
@@ -110,6 +125,15 @@ func (h *GenreHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	current, err := h.genreService.GetGenreByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Genre not found"})
+		return
+	}
+	if !CheckIfMatch(c, current.Version) {
+		return
+	}
+
 	if err := h.genreService.DeleteGenre(uint(id)); err != nil {
 		log.Printf("Failed to delete genre: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
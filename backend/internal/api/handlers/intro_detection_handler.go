@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// IntroDetectionHandler handles intro analysis and chapter suggestion requests
+type IntroDetectionHandler struct {
+	introDetectionService *services.IntroDetectionService
+}
+
+// NewIntroDetectionHandler creates a new IntroDetectionHandler
+func NewIntroDetectionHandler(introDetectionService *services.IntroDetectionService) *IntroDetectionHandler {
+	return &IntroDetectionHandler{
+		introDetectionService: introDetectionService,
+	}
+}
+
+// IntroAnalysisRequest represents the request body for analyzing a season
+type IntroAnalysisRequest struct {
+	ContentID    uint `json:"content_id" binding:"required"`
+	SeasonNumber int  `json:"season_number"`
+}
+
+// optionalContentID reads the content_id query parameter, if any
+func optionalContentID(c *gin.Context) (*uint, bool) {
+	raw := c.Query("content_id")
+	if raw == "" {
+		return nil, true
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return nil, false
+	}
+	contentID := uint(id)
+	return &contentID, true
+}
+
+// Create handles queueing an intro analysis of a season
+func (h *IntroDetectionHandler) Create(c *gin.Context) {
+	var input IntroAnalysisRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.SeasonNumber == 0 {
+		input.SeasonNumber = 1
+	}
+
+	analysis, err := h.introDetectionService.RequestAnalysis(input.ContentID, input.SeasonNumber)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, analysis)
+}
+
+// List handles listing intro analyses
+func (h *IntroDetectionHandler) List(c *gin.Context) {
+	contentID, ok := optionalContentID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	analyses, err := h.introDetectionService.ListAnalyses(contentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, analyses)
+}
+
+// Get handles getting an intro analysis with its suggestions
+func (h *IntroDetectionHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid analysis ID"})
+		return
+	}
+
+	analysis, err := h.introDetectionService.GetAnalysis(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Analysis not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// ListSuggestions handles listing detected chapters for review
+func (h *IntroDetectionHandler) ListSuggestions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	contentID, ok := optionalContentID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	status := models.ChapterSuggestionStatus(c.DefaultQuery("status", string(models.ChapterSuggestionPending)))
+	if status == "all" {
+		status = ""
+	}
+
+	suggestions, total, err := h.introDetectionService.ListSuggestions(contentID, status, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     suggestions,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// AcceptSuggestion handles an admin turning a suggestion into a chapter
+func (h *IntroDetectionHandler) AcceptSuggestion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suggestion ID"})
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	chapters, err := h.introDetectionService.AcceptSuggestion(userID.(uint), uint(id))
+	if err != nil {
+		log.Printf("Failed to accept chapter suggestion %d: %v", id, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, chapters)
+}
+
+// RejectSuggestion handles an admin dismissing a suggestion
+func (h *IntroDetectionHandler) RejectSuggestion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suggestion ID"})
+		return
+	}
+
+	if err := h.introDetectionService.RejectSuggestion(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Suggestion rejected"})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+// languagesFromRequest returns the Accept-Language tags ordered by preference
+func languagesFromRequest(c *gin.Context) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	languages := make([]string, len(tags))
+	for i, tag := range tags {
+		languages[i] = tag.tag
+	}
+	return languages
+}
+
+// localizeContents sets the display title and description of each content for the request
+func localizeContents(c *gin.Context, contents []models.Content) {
+	languages := languagesFromRequest(c)
+	for i := range contents {
+		contents[i].Localize(languages)
+	}
+}
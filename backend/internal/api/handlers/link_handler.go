@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// LinkHandler handles the stream and download links of episodes and their health
+type LinkHandler struct {
+	linkService       *services.LinkService
+	linkHealthService *services.LinkHealthService
+}
+
+// NewLinkHandler creates a new LinkHandler
+func NewLinkHandler(linkService *services.LinkService, linkHealthService *services.LinkHealthService) *LinkHandler {
+	return &LinkHandler{
+		linkService:       linkService,
+		linkHealthService: linkHealthService,
+	}
+}
+
+// EpisodeLinkRequest represents the request body for adding or changing a link
+type EpisodeLinkRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Quality  string `json:"quality"`
+	URL      string `json:"url" binding:"required"`
+	Type     string `json:"type"` // stream links only: 'embed' or 'self-hosted'
+	Server   string `json:"server"`
+	Priority int    `json:"priority"`
+	Enabled  *bool  `json:"enabled"` // new links are enabled unless told otherwise
+}
+
+// applyStream copies the request onto a stream link; a new URL has not been checked yet
+func (r *EpisodeLinkRequest) applyStream(link *models.StreamLink) {
+	if link.URL != r.URL {
+		link.LinkHealth.Reset()
+	}
+	link.Name = r.Name
+	link.Quality = r.Quality
+	link.URL = r.URL
+	link.Type = r.Type
+	link.Server = r.Server
+	link.Priority = r.Priority
+	if r.Enabled != nil {
+		link.Enabled = *r.Enabled
+	}
+}
+
+// applyDownload copies the request onto a download link; a new URL has not been checked yet
+func (r *EpisodeLinkRequest) applyDownload(link *models.DownloadLink) {
+	if link.URL != r.URL {
+		link.LinkHealth.Reset()
+	}
+	link.Name = r.Name
+	link.Quality = r.Quality
+	link.URL = r.URL
+	link.Server = r.Server
+	link.Priority = r.Priority
+	if r.Enabled != nil {
+		link.Enabled = *r.Enabled
+	}
+}
+
+// episodeParams reads the content and episode IDs of a link route
+func episodeParams(c *gin.Context) (uint, uint, bool) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return 0, 0, false
+	}
+	episodeID, err := strconv.ParseUint(c.Param("episodeId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid episode ID"})
+		return 0, 0, false
+	}
+	return uint(contentID), uint(episodeID), true
+}
+
+// linkKind reads the link kind of a link route
+func linkKind(c *gin.Context) (models.LinkKind, bool) {
+	kind := models.LinkKind(c.Param("kind"))
+	if !kind.IsValid() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown link kind"})
+		return "", false
+	}
+	return kind, true
+}
+
+// editedEpisode finds the episode whose links are being edited
+func (h *LinkHandler) editedEpisode(c *gin.Context) (*models.Episode, bool) {
+	contentID, episodeID, ok := episodeParams(c)
+	if !ok {
+		return nil, false
+	}
+	episode, err := h.linkService.GetEpisode(contentID, episodeID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Episode not found"})
+		return nil, false
+	}
+	return episode, true
+}
+
+// List handles listing the links of an episode in priority order
+func (h *LinkHandler) List(c *gin.Context) {
+	contentID, episodeID, ok := episodeParams(c)
+	if !ok {
+		return
+	}
+
+	links, err := h.linkService.GetVisibleLinks(viewerFromContext(c), contentID, episodeID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Episode not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// Create handles adding a stream or download link to an episode
+func (h *LinkHandler) Create(c *gin.Context) {
+	kind, ok := linkKind(c)
+	if !ok {
+		return
+	}
+	episode, ok := h.editedEpisode(c)
+	if !ok {
+		return
+	}
+
+	var input EpisodeLinkRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch kind {
+	case models.LinkKindStream:
+		link := &models.StreamLink{Enabled: true}
+		input.applyStream(link)
+		if err := h.linkService.AddStreamLink(episode, link); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, link)
+	case models.LinkKindDownload:
+		link := &models.DownloadLink{Enabled: true}
+		input.applyDownload(link)
+		if err := h.linkService.AddDownloadLink(episode, link); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, link)
+	}
+}
+
+// Update handles changing a link of an episode; enabled is kept when left out
+func (h *LinkHandler) Update(c *gin.Context) {
+	kind, ok := linkKind(c)
+	if !ok {
+		return
+	}
+	episode, ok := h.editedEpisode(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("linkId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link ID"})
+		return
+	}
+
+	var input EpisodeLinkRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch kind {
+	case models.LinkKindStream:
+		link, err := h.linkService.GetStreamLink(episode, uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		input.applyStream(link)
+		if err := h.linkService.UpdateStreamLink(link); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, link)
+	case models.LinkKindDownload:
+		link, err := h.linkService.GetDownloadLink(episode, uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		input.applyDownload(link)
+		if err := h.linkService.UpdateDownloadLink(link); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, link)
+	}
+}
+
+// Delete handles removing a link from an episode
+func (h *LinkHandler) Delete(c *gin.Context) {
+	kind, ok := linkKind(c)
+	if !ok {
+		return
+	}
+	episode, ok := h.editedEpisode(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("linkId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link ID"})
+		return
+	}
+
+	if kind == models.LinkKindStream {
+		err = h.linkService.DeleteStreamLink(episode, uint(id))
+	} else {
+		err = h.linkService.DeleteDownloadLink(episode, uint(id))
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Link deleted successfully"})
+}
+
+// HealthReport handles the link health report for admins
+func (h *LinkHandler) HealthReport(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	kind := models.LinkKind(c.DefaultQuery("kind", string(models.LinkKindStream)))
+	status := models.LinkHealthStatus(c.Query("status"))
+
+	report, err := h.linkHealthService.GetReport(kind, status, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// CheckHealth handles checking a link right away
+func (h *LinkHandler) CheckHealth(c *gin.Context) {
+	kind, ok := linkKind(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link ID"})
+		return
+	}
+
+	health, err := h.linkHealthService.CheckLink(kind, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, health)
+}
@@ -11,18 +11,21 @@ import (
 	"log"
 
 	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
 	"github.com/username/anime-streaming/internal/services"
 )
 
 // MediaHandler handles media related requests
 type MediaHandler struct {
 	mediaService *services.MediaService
+	viewService  *services.ViewService
 }
 
 // NewMediaHandler creates a new MediaHandler
-func NewMediaHandler(mediaService *services.MediaService) *MediaHandler {
+func NewMediaHandler(mediaService *services.MediaService, viewService *services.ViewService) *MediaHandler {
 	return &MediaHandler{
 		mediaService: mediaService,
+		viewService:  viewService,
 	}
 }
 
@@ -39,8 +42,9 @@ func (h *MediaHandler) UploadContentCover(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
 		return
 	}
+	userID, _ := c.Get("userID")
 
-	if err := h.mediaService.UploadContentCover(uint(contentID), file); err != nil {
+	if err := h.mediaService.UploadContentCover(userID.(uint), uint(contentID), file); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -61,8 +65,9 @@ func (h *MediaHandler) UploadEpisodeThumbnail(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
 		return
 	}
+	userID, _ := c.Get("userID")
 
-	if err := h.mediaService.UploadEpisodeThumbnail(uint(episodeID), file); err != nil {
+	if err := h.mediaService.UploadEpisodeThumbnail(userID.(uint), uint(episodeID), file); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -136,7 +141,7 @@ func (h *MediaHandler) StreamVideo(c *gin.Context) {
 	log.Printf("Streaming request - Content: %d, Episode: %v, Quality: %s", contentID, episodeID, quality)
 
 	// Get video path
-	videoPath, err := h.mediaService.GetVideoPath(uint(contentID), episodeID, quality)
+	videoPath, err := h.mediaService.GetVideoPath(viewerFromContext(c), uint(contentID), episodeID, quality)
 	log.Printf("Video path: %s", videoPath)
 	if err != nil {
 		log.Printf("Failed to get video path: %v", err)
@@ -153,6 +158,9 @@ func (h *MediaHandler) StreamVideo(c *gin.Context) {
 	}
 	defer file.Close()
 
+	// Players fetch the video in many range requests; the view is deduplicated
+	recordView(c, h.viewService, uint(contentID), episodeID, models.ViewSourceStream)
+
 	// Get file info
 	fileInfo, err := file.Stat()
 	if err != nil {
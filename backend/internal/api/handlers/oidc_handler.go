@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// OIDCHandler handles social login requests
+type OIDCHandler struct {
+	oidcService *services.OIDCService
+}
+
+// NewOIDCHandler creates a new OIDCHandler
+func NewOIDCHandler(oidcService *services.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+	}
+}
+
+// ListProviders handles listing the configured social login providers
+func (h *OIDCHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.oidcService.ListProviders()})
+}
+
+// Login starts a social login and returns the provider authorization URL
+func (h *OIDCHandler) Login(c *gin.Context) {
+	provider := c.Param("provider")
+
+	authURL, err := h.oidcService.BeginLogin(provider)
+	if err != nil {
+		log.Printf("Failed to start %s login: %v", provider, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"auth_url": authURL})
+}
+
+// Link starts linking a social login provider to the signed-in user's account
+// and returns the provider authorization URL. The provider redirects back to the
+// usual callback, which then signs in as this account.
+func (h *OIDCHandler) Link(c *gin.Context) {
+	provider := c.Param("provider")
+	userID, _ := c.Get("userID")
+
+	authURL, err := h.oidcService.BeginLink(provider, userID.(uint))
+	if err != nil {
+		log.Printf("Failed to start %s link: %v", provider, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"auth_url": authURL})
+}
+
+// Callback completes a social login with the code and state returned by the provider
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	provider := c.Param("provider")
+	var input struct {
+		Code  string `json:"code" binding:"required"`
+		State string `json:"state" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, user, err := h.oidcService.CompleteLogin(provider, input.Code, input.State)
+	if err != nil {
+		log.Printf("Failed to complete %s login: %v", provider, err)
+		if errors.Is(err, services.ErrOIDCEmailTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token": token,
+		"user":  user,
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// ParentalControlHandler handles account-wide maturity settings
+type ParentalControlHandler struct {
+	parentalService *services.ParentalControlService
+}
+
+// NewParentalControlHandler creates a new ParentalControlHandler
+func NewParentalControlHandler(parentalService *services.ParentalControlService) *ParentalControlHandler {
+	return &ParentalControlHandler{
+		parentalService: parentalService,
+	}
+}
+
+// ParentalControlRequest represents the request body for updating parental controls.
+// A nil field is left unchanged; an empty PIN removes the PIN.
+type ParentalControlRequest struct {
+	MaxMaturity *models.MaturityRating `json:"max_maturity"`
+	PIN         *string                `json:"pin"`
+	CurrentPIN  string                 `json:"current_pin"`
+}
+
+// Get returns the rating scheme and the account's current settings
+func (h *ParentalControlHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"ratings": h.parentalService.Scheme(),
+		"viewer":  viewerFromContext(c),
+	})
+}
+
+// Update handles changing the account maturity limit and parental PIN
+func (h *ParentalControlHandler) Update(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var input ParentalControlRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.parentalService.UpdateSettings(userID.(uint), input.MaxMaturity, input.PIN, input.CurrentPIN)
+	if err != nil {
+		c.JSON(parentalErrorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"max_maturity": user.MaxMaturity,
+		"has_pin":      user.ParentalPIN != "",
+	})
+}
+
+// parentalErrorStatus maps a missing or wrong parental PIN to 403, a locked
+// PIN to 429 and anything else to fallback
+func parentalErrorStatus(err error, fallback int) int {
+	if errors.Is(err, services.ErrParentalPINRequired) {
+		return http.StatusForbidden
+	}
+	if errors.Is(err, services.ErrParentalPINLocked) {
+		return http.StatusTooManyRequests
+	}
+	return fallback
+}
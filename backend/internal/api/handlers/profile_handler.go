@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// ProfileHandler handles viewer profile requests
+type ProfileHandler struct {
+	profileService *services.ProfileService
+}
+
+// NewProfileHandler creates a new ProfileHandler
+func NewProfileHandler(profileService *services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+	}
+}
+
+// ProfileRequest represents the request body for creating or updating a profile
+type ProfileRequest struct {
+	Name          string                `json:"name" binding:"required"`
+	AvatarURL     string                `json:"avatar_url"`
+	IsKids        bool                  `json:"is_kids"`
+	MaturityLimit models.MaturityRating `json:"maturity_limit"`
+}
+
+// List handles listing the current user's profiles
+func (h *ProfileHandler) List(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	profiles, err := h.profileService.ListProfiles(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profiles)
+}
+
+// Create handles creating a profile
+func (h *ProfileHandler) Create(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	var input ProfileRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile := &models.Profile{
+		Name:          input.Name,
+		AvatarURL:     input.AvatarURL,
+		IsKids:        input.IsKids,
+		MaturityLimit: input.MaturityLimit,
+	}
+	if err := h.profileService.CreateProfile(userID.(uint), profile, c.GetHeader("X-Parental-PIN")); err != nil {
+		log.Printf("Failed to create profile: %v", err)
+		c.JSON(parentalErrorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// Update handles updating a profile
+func (h *ProfileHandler) Update(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid profile ID"})
+		return
+	}
+
+	profile, err := h.profileService.GetProfile(userID.(uint), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var input ProfileRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile.Name = input.Name
+	profile.AvatarURL = input.AvatarURL
+	profile.IsKids = input.IsKids
+	profile.MaturityLimit = input.MaturityLimit
+
+	if err := h.profileService.UpdateProfile(userID.(uint), profile, c.GetHeader("X-Parental-PIN")); err != nil {
+		c.JSON(parentalErrorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// Delete handles deleting a profile
+func (h *ProfileHandler) Delete(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid profile ID"})
+		return
+	}
+
+	if err := h.profileService.DeleteProfile(userID.(uint), uint(id), c.GetHeader("X-Parental-PIN")); err != nil {
+		c.JSON(parentalErrorStatus(err, http.StatusNotFound), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Profile deleted successfully"})
+}
+
+// Select handles choosing a profile and returns a token scoped to it
+func (h *ProfileHandler) Select(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid profile ID"})
+		return
+	}
+
+	token, profile, err := h.profileService.SelectProfile(userID.(uint), uint(id), profileIDFromContext(c), c.GetHeader("X-Parental-PIN"))
+	if err != nil {
+		c.JSON(parentalErrorStatus(err, http.StatusNotFound), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":   token,
+		"profile": profile,
+	})
+}
+
+// profileIDFromContext returns the profile selected by the request token, if any
+func profileIDFromContext(c *gin.Context) *uint {
+	value, exists := c.Get("profileID")
+	if !exists {
+		return nil
+	}
+	profileID := value.(uint)
+	return &profileID
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// RevisionHandler handles content revision history requests
+type RevisionHandler struct {
+	revisionService *services.RevisionService
+}
+
+// NewRevisionHandler creates a new RevisionHandler
+func NewRevisionHandler(revisionService *services.RevisionService) *RevisionHandler {
+	return &RevisionHandler{
+		revisionService: revisionService,
+	}
+}
+
+// List handles listing the revisions of a content and its episodes. With
+// ?from=&to= it returns the difference between two revisions instead.
+func (h *RevisionHandler) List(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	if c.Query("from") != "" || c.Query("to") != "" {
+		fromID, err := strconv.ParseUint(c.Query("from"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from revision ID"})
+			return
+		}
+		toID, err := strconv.ParseUint(c.Query("to"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to revision ID"})
+			return
+		}
+
+		diff, err := h.revisionService.DiffRevisions(uint(contentID), uint(fromID), uint(toID))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, diff)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	revisions, total, err := h.revisionService.ListRevisions(uint(contentID), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     revisions,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// Restore handles rolling a content or episode back to a revision
+func (h *RevisionHandler) Restore(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+	revisionID, err := strconv.ParseUint(c.Param("revisionId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision ID"})
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	version, err := h.revisionService.RestoreVersion(uint(contentID), uint(revisionID))
+	if errors.Is(err, services.ErrRestoreTrashed) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if !CheckIfMatch(c, version) {
+		return
+	}
+
+	revision, err := h.revisionService.Restore(userID.(uint), uint(contentID), uint(revisionID), version)
+	if err != nil {
+		if VersionConflict(c, err) {
+			return
+		}
+		if errors.Is(err, services.ErrRestoreTrashed) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to restore revision %d of content %d: %v", revisionID, contentID, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	SetETag(c, version+1)
+	c.JSON(http.StatusOK, revision)
+}
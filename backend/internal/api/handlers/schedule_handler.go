@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// ScheduleHandler handles airing schedule and calendar feed requests
+type ScheduleHandler struct {
+	scheduleService *services.ScheduleService
+}
+
+// NewScheduleHandler creates a new ScheduleHandler
+func NewScheduleHandler(scheduleService *services.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// GetWeek handles getting the weekly release schedule.
+// The timezone comes from ?tz= or the X-Timezone header and defaults to UTC.
+func (h *ScheduleHandler) GetWeek(c *gin.Context) {
+	tz := c.Query("tz")
+	if tz == "" {
+		tz = c.GetHeader("X-Timezone")
+	}
+	loc := time.UTC
+	if tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+			return
+		}
+	}
+
+	schedule, err := h.scheduleService.GetWeek(viewerFromContext(c), c.Query("week"), loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for i := range schedule.Days {
+		for j := range schedule.Days[i].Entries {
+			if content := schedule.Days[i].Entries[j].Content; content != nil {
+				content.Localize(languagesFromRequest(c))
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// ListForContent handles listing the schedule of a content
+func (h *ScheduleHandler) ListForContent(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	schedules, err := h.scheduleService.ListContentSchedule(viewerFromContext(c), uint(contentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// AddToContent handles adding upcoming broadcasts to a content
+func (h *ScheduleHandler) AddToContent(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	var entries []models.EpisodeSchedule
+	if err := c.ShouldBindJSON(&entries); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.scheduleService.AddEntries(uint(contentID), entries)
+	if err != nil {
+		log.Printf("Failed to add schedule entries: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// DeleteFromContent handles removing a broadcast from a content's schedule
+func (h *ScheduleHandler) DeleteFromContent(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+	scheduleID, err := strconv.ParseUint(c.Param("scheduleId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+
+	if err := h.scheduleService.DeleteEntry(uint(contentID), uint(scheduleID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule entry deleted successfully"})
+}
+
+// GetCalendarURL handles getting the private calendar feed URL of the current user
+func (h *ScheduleHandler) GetCalendarURL(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	token, err := h.scheduleService.CalendarToken(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"path": "/api/schedule/calendar.ics?token=" + token})
+}
+
+// ResetCalendarURL handles invalidating the current calendar feed URL
+func (h *ScheduleHandler) ResetCalendarURL(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	token, err := h.scheduleService.ResetCalendarToken(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"path": "/api/schedule/calendar.ics?token=" + token})
+}
+
+// Calendar handles serving the iCalendar feed; calendar apps authenticate with the URL token
+func (h *ScheduleHandler) Calendar(c *gin.Context) {
+	calendar, err := h.scheduleService.BuildCalendar(c.Query("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar)
+}
@@ -70,6 +70,7 @@ func (h *SeasonHandler) Get(c *gin.Context) {
 		return
 	}
 
+	SetETag(c, season.Version)
 	log.Printf("Retrieved season: %s %d", season.Name, season.Year)
 	c.JSON(http.StatusOK, season)
 }
@@ -83,6 +84,15 @@ func (h *SeasonHandler) Update(c *gin.Context) {
 		return
 	}
 
+	current, err := h.seasonService.GetSeasonByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+	if !CheckIfMatch(c, current.Version) {
+		return
+	}
+
 	var season models.Season
 	if err := c.ShouldBindJSON(&season); err != nil {
 		log.Printf("Failed to bind season data: %v", err)
@@ -91,13 +101,18 @@ func (h *SeasonHandler) Update(c *gin.Context) {
 	}
 
 	season.ID = uint(id)
+	season.Version = current.Version
 	if err := h.seasonService.UpdateSeason(&season); err != nil {
+		if VersionConflict(c, err) {
+			return
+		}
 		log.Printf("Failed to update season: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	log.Printf("Season updated successfully: %s %d", season.Name, season.Year)
+	SetETag(c, season.Version)
 	c.JSON(http.StatusOK, season)
 }
 
@@ -110,6 +125,15 @@ func (h *SeasonHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	current, err := h.seasonService.GetSeasonByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+	if !CheckIfMatch(c, current.Version) {
+		return
+	}
+
 	if err := h.seasonService.DeleteSeason(uint(id)); err != nil {
 		log.Printf("Failed to delete season: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -120,6 +144,27 @@ func (h *SeasonHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Season deleted successfully"})
 }
 
+// Chart handles getting the titles of a season grouped by airing weekday
+func (h *SeasonHandler) Chart(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	chart, err := h.seasonService.GetChart(viewerFromContext(c), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	for i := range chart.Days {
+		localizeContents(c, chart.Days[i].Contents)
+	}
+	localizeContents(c, chart.Unscheduled)
+	c.JSON(http.StatusOK, chart)
+}
+
 // GetCurrent handles getting the current active season
 func (h *SeasonHandler) GetCurrent(c *gin.Context) {
 	season, err := h.seasonService.GetCurrentSeason()
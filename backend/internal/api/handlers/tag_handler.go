@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// TagHandler handles tag, content tag and tag suggestion requests
+type TagHandler struct {
+	tagService *services.TagService
+}
+
+// NewTagHandler creates a new TagHandler
+func NewTagHandler(tagService *services.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// ContentTagRequest represents the request body for tagging a title
+type ContentTagRequest struct {
+	Weight    int  `json:"weight"`
+	IsSpoiler bool `json:"is_spoiler"`
+}
+
+// TagSuggestionRequest represents the request body for suggesting a tag
+type TagSuggestionRequest struct {
+	Name      string             `json:"name" binding:"required"`
+	Category  models.TagCategory `json:"category"`
+	IsSpoiler bool               `json:"is_spoiler"`
+	Reason    string             `json:"reason"`
+}
+
+// ApproveTagSuggestionRequest represents the optional overrides when approving a suggestion
+type ApproveTagSuggestionRequest struct {
+	Weight    int   `json:"weight"`
+	IsSpoiler *bool `json:"is_spoiler"`
+}
+
+// tagFilterFromQuery reads the comma-separated tags and exclude_tags query parameters
+func tagFilterFromQuery(c *gin.Context) models.TagFilter {
+	split := func(value string) []string {
+		var names []string
+		for _, name := range strings.Split(value, ",") {
+			if name = models.NormalizeTagName(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return models.TagFilter{
+		Include: split(c.Query("tags")),
+		Exclude: split(c.Query("exclude_tags")),
+	}
+}
+
+// List handles listing tags, optionally within a category
+func (h *TagHandler) List(c *gin.Context) {
+	tags, err := h.tagService.ListTags(models.TagCategory(c.Query("category")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// Get handles getting a single tag
+func (h *TagHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	tag, err := h.tagService.GetTag(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// Create handles tag creation
+func (h *TagHandler) Create(c *gin.Context) {
+	var tag models.Tag
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag.ID = 0
+	if err := h.tagService.CreateTag(&tag); err != nil {
+		log.Printf("Failed to create tag: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// Update handles tag updates
+func (h *TagHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	tag, err := h.tagService.GetTag(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+	if err := c.ShouldBindJSON(tag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag.ID = uint(id)
+	if err := h.tagService.UpdateTag(tag); err != nil {
+		log.Printf("Failed to update tag: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// Delete handles tag deletion
+func (h *TagHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	if err := h.tagService.DeleteTag(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+// ListForContent handles listing the tags of a title
+func (h *TagHandler) ListForContent(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	tags, err := h.tagService.ListContentTags(viewerFromContext(c), uint(contentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// SetForContent handles tagging a title or changing the weight of one of its tags
+func (h *TagHandler) SetForContent(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+	tagID, err := strconv.ParseUint(c.Param("tagId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	var input ContentTagRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := h.tagService.SetContentTag(uint(contentID), uint(tagID), input.Weight, input.IsSpoiler)
+	if err != nil {
+		log.Printf("Failed to tag content: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// RemoveFromContent handles removing a tag from a title
+func (h *TagHandler) RemoveFromContent(c *gin.Context) {
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+	tagID, err := strconv.ParseUint(c.Param("tagId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	if err := h.tagService.RemoveContentTag(uint(contentID), uint(tagID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag removed successfully"})
+}
+
+// Suggest handles a user suggesting a tag for a title
+func (h *TagHandler) Suggest(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	contentID, err := strconv.ParseUint(c.Param("contentId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content ID"})
+		return
+	}
+
+	var input TagSuggestionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suggestion := &models.TagSuggestion{
+		ContentID: uint(contentID),
+		Name:      input.Name,
+		Category:  input.Category,
+		IsSpoiler: input.IsSpoiler,
+		Reason:    input.Reason,
+	}
+	if err := h.tagService.SuggestTag(viewerFromContext(c), userID.(uint), suggestion); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, suggestion)
+}
+
+// ListSuggestions handles listing tag suggestions for moderation; pending ones by default
+func (h *TagHandler) ListSuggestions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	status := models.TagSuggestionStatus(c.DefaultQuery("status", string(models.TagSuggestionPending)))
+	if status == "all" {
+		status = ""
+	}
+
+	suggestions, total, err := h.tagService.ListSuggestions(status, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     suggestions,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// ApproveSuggestion handles a moderator applying a tag suggestion
+func (h *TagHandler) ApproveSuggestion(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suggestion ID"})
+		return
+	}
+
+	// The body is optional
+	var input ApproveTagSuggestionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	suggestion, err := h.tagService.ApproveSuggestion(uint(id), userID.(uint), input.Weight, input.IsSpoiler)
+	if err != nil {
+		log.Printf("Failed to approve tag suggestion %d: %v", id, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// RejectSuggestion handles a moderator turning down a tag suggestion
+func (h *TagHandler) RejectSuggestion(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suggestion ID"})
+		return
+	}
+
+	suggestion, err := h.tagService.RejectSuggestion(uint(id), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
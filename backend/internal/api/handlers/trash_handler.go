@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// TrashHandler handles the admin trash bin
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+// NewTrashHandler creates a new TrashHandler
+func NewTrashHandler(trashService *services.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// List handles listing soft-deleted records of one type (?type=contents|episodes|genres|seasons)
+func (h *TrashHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	items, total, err := h.trashService.List(c.DefaultQuery("type", services.TrashContents), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     items,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// Restore handles bringing a record back from the trash
+func (h *TrashHandler) Restore(c *gin.Context) {
+	kind := c.Param("type")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	if err := h.trashService.Restore(userID.(uint), kind, uint(id)); err != nil {
+		log.Printf("Failed to restore %s %d: %v", kind, id, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Restored successfully"})
+}
+
+// Purge handles permanently deleting a record from the trash
+func (h *TrashHandler) Purge(c *gin.Context) {
+	kind := c.Param("type")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.trashService.Purge(kind, uint(id)); err != nil {
+		log.Printf("Failed to purge %s %d: %v", kind, id, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Purged successfully"})
+}
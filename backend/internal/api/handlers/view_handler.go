@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// ViewHandler handles trending rankings built from playback views
+type ViewHandler struct {
+	viewService *services.ViewService
+}
+
+// NewViewHandler creates a new ViewHandler
+func NewViewHandler(viewService *services.ViewService) *ViewHandler {
+	return &ViewHandler{
+		viewService: viewService,
+	}
+}
+
+// Trending handles ranking titles by their views in the last 24h, 7d or 30d
+func (h *ViewHandler) Trending(c *gin.Context) {
+	window := models.TrendingWindow(c.DefaultQuery("window", string(models.TrendingWeek)))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	trending, err := h.viewService.Trending(viewerFromContext(c), window, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window, expected 24h, 7d or 30d"})
+		return
+	}
+
+	languages := languagesFromRequest(c)
+	for i := range trending {
+		trending[i].Content.Localize(languages)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"window": window,
+		"data":   trending,
+	})
+}
+
+// recordView stores a view in the background so playback is never slowed down.
+// Signed-in viewers are counted by user; guests by a hash of their IP and user agent.
+func recordView(c *gin.Context, viewService *services.ViewService, contentID uint, episodeID *uint, source models.ViewSource) {
+	var key string
+	if userID, exists := c.Get("userID"); exists {
+		key = fmt.Sprintf("user:%d", userID.(uint))
+	} else {
+		sum := sha256.Sum256([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+		key = "guest:" + hex.EncodeToString(sum[:16])
+	}
+
+	go viewService.RecordView(key, contentID, episodeID, source)
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+// guestViewer is used when a route is missing the viewer middleware: an anonymous
+// visitor without a maturity limit, as guests are by default. Public catalog
+// routes use the middleware so a configured guest limit applies.
+var guestViewer = &models.Viewer{}
+
+// viewerFromContext returns the viewer resolved by middleware.ViewerMiddleware
+func viewerFromContext(c *gin.Context) *models.Viewer {
+	value, exists := c.Get("viewer")
+	if !exists {
+		return guestViewer
+	}
+	return value.(*models.Viewer)
+}
@@ -5,18 +5,21 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
 	"github.com/username/anime-streaming/internal/services"
 )
 
 // WatchHistoryHandler handles watch history related requests
 type WatchHistoryHandler struct {
 	watchHistoryService *services.WatchHistoryService
+	viewService         *services.ViewService
 }
 
 // NewWatchHistoryHandler creates a new WatchHistoryHandler
-func NewWatchHistoryHandler(watchHistoryService *services.WatchHistoryService) *WatchHistoryHandler {
+func NewWatchHistoryHandler(watchHistoryService *services.WatchHistoryService, viewService *services.ViewService) *WatchHistoryHandler {
 	return &WatchHistoryHandler{
 		watchHistoryService: watchHistoryService,
+		viewService:         viewService,
 	}
 }
 
@@ -34,11 +37,14 @@ func (h *WatchHistoryHandler) UpdateProgress(c *gin.Context) {
 		return
 	}
 
-	if err := h.watchHistoryService.UpdateProgress(userID.(uint), input.ContentID, input.EpisodeID, input.Progress); err != nil {
+	if err := h.watchHistoryService.UpdateProgress(userID.(uint), profileIDFromContext(c), input.ContentID, input.EpisodeID, input.Progress); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Players report progress every few seconds; the view is deduplicated
+	recordView(c, h.viewService, input.ContentID, input.EpisodeID, models.ViewSourceProgress)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Progress updated successfully"})
 }
 
@@ -62,7 +68,7 @@ func (h *WatchHistoryHandler) GetProgress(c *gin.Context) {
 		episodeID = &episodeIDUint32
 	}
 
-	progress, err := h.watchHistoryService.GetProgress(userID.(uint), uint(contentID), episodeID)
+	progress, err := h.watchHistoryService.GetProgress(userID.(uint), profileIDFromContext(c), uint(contentID), episodeID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Progress not found"})
 		return
@@ -77,7 +83,7 @@ func (h *WatchHistoryHandler) GetUserHistory(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "12"))
 
-	history, total, err := h.watchHistoryService.GetUserHistory(userID.(uint), page, pageSize)
+	history, total, err := h.watchHistoryService.GetUserHistory(userID.(uint), profileIDFromContext(c), page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -96,7 +102,7 @@ func (h *WatchHistoryHandler) GetContinueWatching(c *gin.Context) {
 	userID, _ := c.Get("userID")
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	history, err := h.watchHistoryService.GetContinueWatching(userID.(uint), limit)
+	history, err := h.watchHistoryService.GetContinueWatching(userID.(uint), profileIDFromContext(c), limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -11,37 +12,113 @@ import (
 	"github.com/username/anime-streaming/internal/services"
 )
 
-// AuthMiddleware creates a middleware for authentication
-func AuthMiddleware(userService *services.UserService) gin.HandlerFunc {
+// AuthMiddleware creates a middleware for authentication.
+// Requests may authenticate with a bearer JWT or with an X-API-Key header.
+func AuthMiddleware(userService *services.UserService, apiKeyService *services.APIKeyService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			c.Abort()
+		// Already authenticated by an earlier middleware in the chain
+		if _, exists := c.Get("userID"); exists {
+			c.Next()
 			return
 		}
 
-		// Check if the header starts with "Bearer "
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+		if err := authenticate(c, userService, apiKeyService); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			c.Abort()
 			return
 		}
 
-		token := parts[1]
-		userID, role, err := userService.ValidateToken(token)
+		c.Next()
+	}
+}
+
+// ViewerMiddleware resolves who is browsing the catalog for public routes.
+// Credentials are optional, but when present they must be valid. The resulting
+// viewer carries the maturity limit applied by the repository layer.
+func ViewerMiddleware(
+	userService *services.UserService,
+	apiKeyService *services.APIKeyService,
+	parentalService *services.ParentalControlService,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("userID"); !exists && hasCredentials(c) {
+			if err := authenticate(c, userService, apiKeyService); err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				c.Abort()
+				return
+			}
+		}
+
+		var userID uint
+		var role models.Role
+		var profileID *uint
+		if value, exists := c.Get("userID"); exists {
+			userID = value.(uint)
+		}
+		if value, exists := c.Get("userRole"); exists {
+			role = value.(models.Role)
+		}
+		if value, exists := c.Get("profileID"); exists {
+			id := value.(uint)
+			profileID = &id
+		}
+
+		viewer, err := parentalService.ResolveViewer(userID, role, profileID, c.GetHeader("X-Parental-PIN"))
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			c.Abort()
 			return
 		}
 
-		// Set user info in context
+		c.Set("viewer", viewer)
+		c.Next()
+	}
+}
+
+// hasCredentials checks if the request carries a JWT or an API key
+func hasCredentials(c *gin.Context) bool {
+	return c.GetHeader("X-API-Key") != "" || c.GetHeader("Authorization") != ""
+}
+
+// authenticate validates the request credentials and stores the user info in the context
+func authenticate(c *gin.Context, userService *services.UserService, apiKeyService *services.APIKeyService) error {
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		userID, role, err := apiKeyService.ValidateKey(apiKey, c.Request.Method)
+		if err != nil {
+			return err
+		}
+
 		c.Set("userID", userID)
 		c.Set("userRole", role)
-		c.Next()
+		c.Set("authMethod", "api_key")
+		return nil
 	}
+
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return errors.New("Authorization header is required")
+	}
+
+	// Check if the header starts with "Bearer "
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return errors.New("Invalid authorization header format")
+	}
+
+	token := parts[1]
+	claims, err := userService.ValidateToken(token)
+	if err != nil {
+		return errors.New("Invalid token")
+	}
+
+	// Set user info in context
+	c.Set("userID", claims.UserID)
+	c.Set("userRole", claims.Role)
+	if claims.ProfileID != nil {
+		c.Set("profileID", *claims.ProfileID)
+	}
+	c.Set("authMethod", "jwt")
+	return nil
 }
 
 // AdminMiddleware creates a middleware for admin-only routes
@@ -83,3 +160,33 @@ func AdminMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// SessionOnly rejects requests authenticated with an API key. Routes that
+// manage API keys use it so a key can never mint a key with more scopes than
+// it was given.
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("authMethod") == "api_key" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API keys cannot be managed with an API key; sign in instead"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AccountOnly rejects requests made with a profile-scoped token. API keys carry
+// no profile, so a key minted from a kids profile would shed the profile's
+// limits without the parental PIN needed to leave it.
+func AccountOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("profileID"); exists {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API keys belong to the account; switch back from the profile to manage them"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
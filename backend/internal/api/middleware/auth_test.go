@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+// authenticatedAs stands in for AuthMiddleware and stores what it would have set
+func authenticatedAs(method string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Set("userRole", models.RoleAdmin)
+		c.Set("authMethod", method)
+		c.Next()
+	}
+}
+
+func TestSessionOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		authMethod     string
+		expectedStatus int
+	}{
+		{name: "password or social login", authMethod: "jwt", expectedStatus: http.StatusCreated},
+		{name: "API key asking for a key with more scopes", authMethod: "api_key", expectedStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			router := gin.New()
+			router.POST("/api/users/me/api-keys", authenticatedAs(tt.authMethod), SessionOnly(), func(c *gin.Context) {
+				created = true
+				c.JSON(http.StatusCreated, gin.H{})
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/users/me/api-keys", strings.NewReader(`{"name":"ci","scopes":["admin"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusCreated, created)
+		})
+	}
+}
+
+func TestAccountOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		profileID      *uint
+		expectedStatus int
+	}{
+		{name: "account token", expectedStatus: http.StatusCreated},
+		{name: "kids profile token", profileID: new(uint), expectedStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/api/users/me/api-keys", authenticatedAs("jwt"), func(c *gin.Context) {
+				if tt.profileID != nil {
+					c.Set("profileID", *tt.profileID)
+				}
+			}, AccountOnly(), func(c *gin.Context) {
+				c.JSON(http.StatusCreated, gin.H{})
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/users/me/api-keys", strings.NewReader(`{"name":"tablet"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/services"
+)
+
+// SlugMiddleware lets a route parameter hold either a numeric ID or a slug.
+// Slugs are swapped for the ID before the handler runs, so handlers keep
+// parsing IDs. GET requests for a former slug are redirected to the current one.
+func SlugMiddleware(slugService *services.SlugService, param string, entity models.SlugEntity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.Param(param)
+		if value == "" {
+			c.Next()
+			return
+		}
+
+		id, current, err := slugService.Resolve(entity, value)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+			c.Abort()
+			return
+		}
+
+		if current != "" && c.Request.Method == http.MethodGet {
+			target, ok := replaceRouteParam(c.Request.URL.Path, c.FullPath(), param, current)
+			if !ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+				c.Abort()
+				return
+			}
+			if c.Request.URL.RawQuery != "" {
+				target += "?" + c.Request.URL.RawQuery
+			}
+			c.Redirect(http.StatusMovedPermanently, target)
+			c.Abort()
+			return
+		}
+
+		for i := range c.Params {
+			if c.Params[i].Key == param {
+				c.Params[i].Value = strconv.FormatUint(uint64(id), 10)
+			}
+		}
+		c.Next()
+	}
+}
+
+// replaceRouteParam replaces the path segment that holds a route parameter.
+// The segment is found by its position in the route, since a slug may well
+// equal a fixed part of the path such as "contents".
+func replaceRouteParam(path, route, param, value string) (string, bool) {
+	segments := strings.Split(path, "/")
+	for i, segment := range strings.Split(route, "/") {
+		if segment == ":"+param && i < len(segments) {
+			segments[i] = value
+			return strings.Join(segments, "/"), true
+		}
+	}
+	return path, false
+}
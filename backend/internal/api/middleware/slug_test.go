@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceRouteParam(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		route string
+		param string
+		value string
+		want  string
+		ok    bool
+	}{
+		{
+			name: "content slug", path: "/api/contents/old-title/episodes", route: "/api/contents/:id/episodes",
+			param: "id", value: "new-title", want: "/api/contents/new-title/episodes", ok: true,
+		},
+		{
+			name: "slug equal to the route prefix", path: "/api/contents/contents/episodes", route: "/api/contents/:id/episodes",
+			param: "id", value: "new-title", want: "/api/contents/new-title/episodes", ok: true,
+		},
+		{
+			name: "slug equal to a later segment", path: "/api/contents/episodes/episodes", route: "/api/contents/:id/episodes",
+			param: "id", value: "new-title", want: "/api/contents/new-title/episodes", ok: true,
+		},
+		{
+			name: "second parameter", path: "/api/contents/1/seasons/season-one", route: "/api/contents/:id/seasons/:season",
+			param: "season", value: "first-season", want: "/api/contents/1/seasons/first-season", ok: true,
+		},
+		{
+			name: "parameter not in the route", path: "/api/contents/old-title", route: "/api/contents/:id",
+			param: "slug", value: "new-title", want: "/api/contents/old-title",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := replaceRouteParam(tt.path, tt.route, tt.param, tt.value)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
@@ -28,8 +28,8 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     strings.Split(cfg.CorsAllowedOrigins, ","),
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "X-Parental-PIN", "X-Timezone", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -92,26 +92,118 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 	categoryRepo := repository.NewCategoryRepository(db)
 	watchHistoryRepo := repository.NewWatchHistoryRepository(db)
 	seasonRepo := repository.NewSeasonRepository(db)
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	profileRepo := repository.NewProfileRepository(db)
+	dataExportRepo := repository.NewDataExportRepository(db)
+	contentRelationRepo := repository.NewContentRelationRepository(db)
+	studioRepo := repository.NewStudioRepository(db)
+	personRepo := repository.NewPersonRepository(db)
+	characterRepo := repository.NewCharacterRepository(db)
+	contentCreditRepo := repository.NewContentCreditRepository(db)
+	scheduleRepo := repository.NewScheduleRepository(db)
+	revisionRepo := repository.NewRevisionRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
+	slugRepo := repository.NewSlugRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	collectionRepo := repository.NewCollectionRepository(db)
+	viewRepo := repository.NewViewRepository(db)
+	introAnalysisRepo := repository.NewIntroAnalysisRepository(db)
+	linkRepo := repository.NewLinkRepository(db)
 
 	// Initialize services
 	userService := services.NewUserService(userRepo, cfg.JWTSecret)
-	contentService := services.NewContentService(contentRepo, genreRepo, categoryRepo, cfg.MediaPath)
-	episodeService := services.NewEpisodeService(episodeRepo, contentRepo, cfg.MediaPath)
+	parentalService := services.NewParentalControlService(
+		userRepo,
+		profileRepo,
+		models.NewMaturityScheme(cfg.MaturityRatings),
+		models.MaturityRating(cfg.MaturityGuestLimit),
+	)
+	slugService := services.NewSlugService(slugRepo)
+	revisionService := services.NewRevisionService(revisionRepo, contentRepo, episodeRepo)
+	contentService := services.NewContentService(contentRepo, genreRepo, categoryRepo, slugService, revisionService, cfg.MediaPath, parentalService.Scheme(), models.MaturityRating(cfg.MaturityUnrated))
+	episodeService := services.NewEpisodeService(episodeRepo, contentRepo, revisionService, cfg.MediaPath)
 	watchHistoryService := services.NewWatchHistoryService(watchHistoryRepo, contentRepo, episodeRepo)
-	mediaService := services.NewMediaService(contentRepo, episodeRepo, cfg.MediaPath)
-	seasonService := services.NewSeasonService(seasonRepo)
+	mediaService := services.NewMediaService(contentRepo, episodeRepo, revisionService, cfg.MediaPath)
+	seasonService := services.NewSeasonService(seasonRepo, contentRepo, slugService)
+	oidcService := services.NewOIDCService(cfg.OIDCProviders, userRepo, userIdentityRepo, userService)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, userRepo)
+	profileService := services.NewProfileService(profileRepo, userService, parentalService)
+	contentRelationService := services.NewContentRelationService(contentRelationRepo, contentRepo)
+	creditService := services.NewCreditService(studioRepo, personRepo, characterRepo, contentCreditRepo, contentRepo)
+	scheduleService := services.NewScheduleService(scheduleRepo, contentRepo, watchHistoryRepo, userRepo, parentalService)
+	accountService := services.NewAccountService(
+		userRepo,
+		watchHistoryRepo,
+		profileRepo,
+		userIdentityRepo,
+		apiKeyRepo,
+		tagRepo,
+		dataExportRepo,
+		cfg.ExportPath,
+		cfg.DeletionGrace,
+	)
+
+	publishingService := services.NewPublishingService(revisionService)
+	trashService := services.NewTrashService(trashRepo, revisionService, cfg.MediaPath, cfg.TrashRetention)
+	tagService := services.NewTagService(tagRepo, contentRepo)
+	categoryService := services.NewCategoryService(categoryRepo, slugService)
+	collectionService := services.NewCollectionService(collectionRepo, contentRepo, episodeRepo, genreRepo, seasonRepo)
+	viewService := services.NewViewService(viewRepo, cfg.ViewDedupWindow)
+	linkService := services.NewLinkService(linkRepo, episodeRepo)
+	linkHealthService := services.NewLinkHealthService(linkRepo, nil, cfg.LinkDeadAfter, cfg.LinkCheckInterval, cfg.LinkCheckHostDelay)
+	introDetectionService := services.NewIntroDetectionService(introAnalysisRepo, episodeRepo, contentRepo, episodeService, cfg.MediaPath)
+
+	// Give records created before slugs existed a slug
+	if err := slugService.Backfill(); err != nil {
+		log.Printf("Slug backfill failed: %v", err)
+	}
+	// Analyses do not survive a restart; let their seasons be analyzed again
+	introDetectionService.FailInterrupted()
+
+	// Purge accounts past their deletion grace period and expired exports
+	accountService.StartScheduler(time.Hour)
+	// Publish scheduled content and episodes once their time has come
+	publishingService.StartScheduler(time.Minute)
+	// Purge records that have been in the trash longer than the retention period
+	trashService.StartScheduler(time.Hour)
+	// Create upcoming seasons and move seasons through their lifecycle
+	seasonService.StartScheduler(time.Hour)
+	// Roll up view events into the counters behind trending and popularity
+	viewService.StartScheduler(5 * time.Minute)
+	// Probe external stream and download links and hide the dead ones
+	linkHealthService.StartScheduler(10 * time.Minute)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(userService)
 	contentHandler := handlers.NewContentHandler(contentService, mediaService)
 	episodeHandler := handlers.NewEpisodeHandler(episodeService)
-	watchHistoryHandler := handlers.NewWatchHistoryHandler(watchHistoryService)
-	mediaHandler := handlers.NewMediaHandler(mediaService)
+	watchHistoryHandler := handlers.NewWatchHistoryHandler(watchHistoryService, viewService)
+	mediaHandler := handlers.NewMediaHandler(mediaService, viewService)
 	seasonHandler := handlers.NewSeasonHandler(seasonService)
+	oidcHandler := handlers.NewOIDCHandler(oidcService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	profileHandler := handlers.NewProfileHandler(profileService)
+	parentalHandler := handlers.NewParentalControlHandler(parentalService)
+	accountHandler := handlers.NewAccountHandler(accountService)
+	contentRelationHandler := handlers.NewContentRelationHandler(contentRelationService)
+	creditHandler := handlers.NewCreditHandler(creditService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	revisionHandler := handlers.NewRevisionHandler(revisionService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	viewHandler := handlers.NewViewHandler(viewService)
+	introDetectionHandler := handlers.NewIntroDetectionHandler(introDetectionService)
+	linkHandler := handlers.NewLinkHandler(linkService, linkHealthService)
 
 	// Auth middleware
-	authMiddleware := middleware.AuthMiddleware(userService)
+	authMiddleware := middleware.AuthMiddleware(userService, apiKeyService)
 	adminMiddleware := middleware.AdminMiddleware()
+	viewerMiddleware := middleware.ViewerMiddleware(userService, apiKeyService, parentalService)
+	sessionOnly := middleware.SessionOnly()
+	accountOnly := middleware.AccountOnly()
 
 	api := router.Group("/api")
 	{
@@ -120,35 +212,71 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
+
+			// Social login (OpenID Connect)
+			auth.GET("/oidc/providers", oidcHandler.ListProviders)
+			auth.GET("/oidc/:provider/login", oidcHandler.Login)
+			auth.POST("/oidc/:provider/callback", oidcHandler.Callback)
 		}
 
 		// Content routes
-		contents := api.Group("/contents")
+		contents := api.Group("/contents", viewerMiddleware)
 		{
 			// Public content routes (no parameters)
 			contents.GET("", contentHandler.List)
 			contents.GET("/search", contentHandler.Search)
-			contents.GET("/genre/:genreId", contentHandler.GetByGenre)
-			contents.GET("/category/:categoryId", contentHandler.GetByCategory)
+			contents.GET("/trending", viewHandler.Trending)
+			contents.GET("/genre/:genreId", middleware.SlugMiddleware(slugService, "genreId", models.SlugGenre), contentHandler.GetByGenre)
+			contents.GET("/category/:categoryId", middleware.SlugMiddleware(slugService, "categoryId", models.SlugCategory), contentHandler.GetByCategory)
 
 			// Protected content routes (no parameters)
 			protectedContents := contents.Use(authMiddleware)
 			{
-				protectedContents.POST("/create", middleware.AuthMiddleware(userService), middleware.AdminMiddleware(), contentHandler.Create)
+				protectedContents.POST("/create", authMiddleware, adminMiddleware, contentHandler.Create)
 			}
 
 			// Content detail routes (with contentId)
-			contentDetail := contents.Group("/:contentId")
+			contentDetail := contents.Group("/:contentId", middleware.SlugMiddleware(slugService, "contentId", models.SlugContent))
 			{
 				// Get single content
 				contentDetail.GET("", contentHandler.Get)
 
+				// Franchise relations
+				contentDetail.GET("/relations", contentRelationHandler.List)
+				contentDetail.GET("/franchise", contentRelationHandler.WatchOrder)
+
+				// Studios, staff and cast
+				contentDetail.GET("/credits", creditHandler.GetContentCredits)
+
+				// Airing schedule
+				contentDetail.GET("/schedule", scheduleHandler.ListForContent)
+
+				// Tags
+				contentDetail.GET("/tags", tagHandler.ListForContent)
+
+				// Split-cour mapping of the seasons
+				contentDetail.GET("/cours", episodeHandler.ListCours)
+
+				// Story arcs and the filler guide
+				contentDetail.GET("/arcs", episodeHandler.ListArcs)
+				contentDetail.GET("/guide", episodeHandler.Guide)
+
 				// Protected content detail routes
 				protectedDetail := contentDetail.Use(authMiddleware)
 				{
 					protectedDetail.PUT("", adminMiddleware, contentHandler.Update)
 					protectedDetail.DELETE("", adminMiddleware, contentHandler.Delete)
 					protectedDetail.POST("/upload-video", adminMiddleware, mediaHandler.UploadVideo)
+					protectedDetail.POST("/relations", adminMiddleware, contentRelationHandler.Create)
+					protectedDetail.DELETE("/relations/:relationId", adminMiddleware, contentRelationHandler.Delete)
+					protectedDetail.PUT("/credits", adminMiddleware, creditHandler.SetContentCredits)
+					protectedDetail.POST("/schedule", adminMiddleware, scheduleHandler.AddToContent)
+					protectedDetail.DELETE("/schedule/:scheduleId", adminMiddleware, scheduleHandler.DeleteFromContent)
+					protectedDetail.POST("/tags/suggestions", tagHandler.Suggest)
+					protectedDetail.PUT("/tags/:tagId", adminMiddleware, tagHandler.SetForContent)
+					protectedDetail.DELETE("/tags/:tagId", adminMiddleware, tagHandler.RemoveFromContent)
+					protectedDetail.PUT("/cours", adminMiddleware, episodeHandler.SetCours)
+					protectedDetail.PUT("/arcs", adminMiddleware, episodeHandler.SetArcs)
 				}
 
 				// Episodes routes
@@ -156,8 +284,13 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 				{
 					episodes.GET("", episodeHandler.List)
 					episodes.GET("/next", episodeHandler.GetNext)
+					episodes.GET("/previous", episodeHandler.GetPrevious)
 					episodes.GET("/latest", episodeHandler.GetLatest)
+					episodes.GET("/absolute/:number", episodeHandler.GetByAbsoluteNumber)
 					episodes.GET("/:episodeId", episodeHandler.Get)
+					episodes.GET("/:episodeId/chapters", episodeHandler.ListChapters)
+					episodes.GET("/:episodeId/chapters.vtt", episodeHandler.ChaptersTrack)
+					episodes.GET("/:episodeId/links", linkHandler.List)
 
 					// Protected episode routes
 					protectedEpisodes := episodes.Use(authMiddleware)
@@ -165,11 +298,28 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 						protectedEpisodes.POST("", adminMiddleware, episodeHandler.Create)
 						protectedEpisodes.PUT("/:episodeId", adminMiddleware, episodeHandler.Update)
 						protectedEpisodes.DELETE("/:episodeId", adminMiddleware, episodeHandler.Delete)
+						protectedEpisodes.PUT("/:episodeId/chapters", adminMiddleware, episodeHandler.SetChapters)
+						protectedEpisodes.POST("/:episodeId/links/:kind", adminMiddleware, linkHandler.Create)
+						protectedEpisodes.PUT("/:episodeId/links/:kind/:linkId", adminMiddleware, linkHandler.Update)
+						protectedEpisodes.DELETE("/:episodeId/links/:kind/:linkId", adminMiddleware, linkHandler.Delete)
 					}
 				}
 			}
 		}
 
+		// Homepage, resolved in one call
+		api.GET("/home", viewerMiddleware, collectionHandler.Home)
+
+		// Curated collections
+		api.GET("/collections/:id", viewerMiddleware, collectionHandler.GetPublic)
+
+		// Release schedule routes
+		schedule := api.Group("/schedule")
+		{
+			schedule.GET("", viewerMiddleware, scheduleHandler.GetWeek)
+			schedule.GET("/calendar.ics", scheduleHandler.Calendar)
+		}
+
 		// Watch history routes
 		history := api.Group("/watch-history", authMiddleware)
 		{
@@ -181,7 +331,7 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 		}
 
 		// Media routes
-		media := api.Group("/media")
+		media := api.Group("/media", viewerMiddleware)
 		{
 			// Public media routes
 			media.GET("/stream/:contentId", mediaHandler.StreamVideo)
@@ -202,101 +352,112 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 		{
 			users.PUT("/profile", authHandler.UpdateProfile)
 			users.POST("/change-password", authHandler.ChangePassword)
+
+			// Personal API keys
+			users.GET("/me/api-keys", sessionOnly, accountOnly, apiKeyHandler.List)
+			users.POST("/me/api-keys", sessionOnly, accountOnly, apiKeyHandler.Create)
+			users.DELETE("/me/api-keys/:id", sessionOnly, accountOnly, apiKeyHandler.Revoke)
+
+			// Social login providers linked to the account
+			users.POST("/me/identities/:provider", sessionOnly, accountOnly, oidcHandler.Link)
+
+			// Parental controls
+			users.GET("/parental-controls", viewerMiddleware, parentalHandler.Get)
+			users.PUT("/parental-controls", parentalHandler.Update)
+
+			// Personal data export and account deletion
+			users.POST("/me/export", accountHandler.RequestExport)
+			users.GET("/me/exports", accountHandler.ListExports)
+			users.GET("/me/exports/:id", accountHandler.GetExport)
+			users.GET("/me/exports/:id/download", accountHandler.DownloadExport)
+			users.DELETE("/me", accountHandler.RequestDeletion)
+			users.POST("/me/cancel-deletion", accountHandler.CancelDeletion)
+
+			// Private calendar feed
+			users.GET("/me/calendar", scheduleHandler.GetCalendarURL)
+			users.POST("/me/calendar/reset", scheduleHandler.ResetCalendarURL)
+		}
+
+		// Viewer profile routes
+		profiles := api.Group("/profiles", authMiddleware)
+		{
+			profiles.GET("", profileHandler.List)
+			profiles.POST("", profileHandler.Create)
+			profiles.PUT("/:id", profileHandler.Update)
+			profiles.DELETE("/:id", profileHandler.Delete)
+			profiles.POST("/:id/select", profileHandler.Select)
 		}
 
 		// Genre routes
-		genreService := services.NewGenreService(db)
+		genreService := services.NewGenreService(db, slugService)
 		genreHandler := handlers.NewGenreHandler(genreService)
 
-		genreRoutes := api.Group("/genres")
+		genreRoutes := api.Group("/genres", middleware.SlugMiddleware(slugService, "id", models.SlugGenre))
 		{
 			genreRoutes.GET("", genreHandler.List)
 			genreRoutes.GET("/:id", genreHandler.Get)
-			genreRoutes.POST("", middleware.AuthMiddleware(userService), genreHandler.Create)
-			genreRoutes.PUT("/:id", middleware.AuthMiddleware(userService), genreHandler.Update)
-			genreRoutes.DELETE("/:id", middleware.AuthMiddleware(userService), genreHandler.Delete)
+			genreRoutes.POST("", authMiddleware, genreHandler.Create)
+			genreRoutes.PUT("/:id", authMiddleware, genreHandler.Update)
+			genreRoutes.DELETE("/:id", authMiddleware, genreHandler.Delete)
 		}
 
 		// Category routes
-		categories := api.Group("/categories")
+		categories := api.Group("/categories", middleware.SlugMiddleware(slugService, "id", models.SlugCategory))
 		{
-			categories.GET("", func(c *gin.Context) {
-				categories, err := categoryRepo.List()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-					return
-				}
-				c.JSON(http.StatusOK, categories)
-			})
-
-			// Protected category routes
-			protectedCategories := categories.Use(authMiddleware, adminMiddleware)
-			{
-				protectedCategories.POST("", func(c *gin.Context) {
-					// Add debug logging
-					roleInterface, _ := c.Get("userRole")
-					userID, _ := c.Get("userID")
-					log.Printf("User ID: %v, Role: %v attempting to create category", userID, roleInterface)
-
-					var category models.Category
-					if err := c.ShouldBindJSON(&category); err != nil {
-						c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-						return
-					}
-					if err := categoryRepo.Create(&category); err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-						return
-					}
-					c.JSON(http.StatusCreated, category)
-				})
-
-				protectedCategories.PUT("/:id", func(c *gin.Context) {
-					// Konversi ID dari string ke uint
-					id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-					if err != nil {
-						c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-						return
-					}
-
-					var category models.Category
-					if err := c.ShouldBindJSON(&category); err != nil {
-						c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-						return
-					}
+			categories.GET("", categoryHandler.List)
+			categories.GET("/:id", categoryHandler.Get)
+			categories.POST("", authMiddleware, adminMiddleware, categoryHandler.Create)
+			categories.PUT("/:id", authMiddleware, adminMiddleware, categoryHandler.Update)
+			categories.DELETE("/:id", authMiddleware, adminMiddleware, categoryHandler.Delete)
+		}
 
-					// Set ID dari parameter ke model
-					category.ID = uint(id)
+		// Tag routes
+		tags := api.Group("/tags")
+		{
+			tags.GET("", tagHandler.List)
+			tags.GET("/:id", tagHandler.Get)
+			tags.POST("", authMiddleware, adminMiddleware, tagHandler.Create)
+			tags.PUT("/:id", authMiddleware, adminMiddleware, tagHandler.Update)
+			tags.DELETE("/:id", authMiddleware, adminMiddleware, tagHandler.Delete)
+		}
 
-					if err := categoryRepo.Update(&category); err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-						return
-					}
-					c.JSON(http.StatusOK, category)
-				})
+		// Studio routes
+		studios := api.Group("/studios")
+		{
+			studios.GET("", creditHandler.ListStudios)
+			studios.GET("/:id", creditHandler.GetStudio)
+			studios.POST("", authMiddleware, adminMiddleware, creditHandler.CreateStudio)
+			studios.PUT("/:id", authMiddleware, adminMiddleware, creditHandler.UpdateStudio)
+			studios.DELETE("/:id", authMiddleware, adminMiddleware, creditHandler.DeleteStudio)
+		}
 
-				protectedCategories.DELETE("/:id", func(c *gin.Context) {
-					// Konversi ID dari string ke uint
-					id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-					if err != nil {
-						c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-						return
-					}
+		// People (staff and voice actors) routes
+		people := api.Group("/people")
+		{
+			people.GET("", creditHandler.ListPeople)
+			people.GET("/:id", creditHandler.GetPerson)
+			people.POST("", authMiddleware, adminMiddleware, creditHandler.CreatePerson)
+			people.PUT("/:id", authMiddleware, adminMiddleware, creditHandler.UpdatePerson)
+			people.DELETE("/:id", authMiddleware, adminMiddleware, creditHandler.DeletePerson)
+		}
 
-					if err := categoryRepo.Delete(uint(id)); err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-						return
-					}
-					c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
-				})
-			}
+		// Character routes
+		characters := api.Group("/characters")
+		{
+			characters.GET("", creditHandler.ListCharacters)
+			characters.GET("/:id", creditHandler.GetCharacter)
+			characters.POST("", authMiddleware, adminMiddleware, creditHandler.CreateCharacter)
+			characters.PUT("/:id", authMiddleware, adminMiddleware, creditHandler.UpdateCharacter)
+			characters.DELETE("/:id", authMiddleware, adminMiddleware, creditHandler.DeleteCharacter)
 		}
 
 		// Season routes
-		seasons := api.Group("/seasons")
+		seasons := api.Group("/seasons", middleware.SlugMiddleware(slugService, "id", models.SlugSeason))
 		{
 			seasons.GET("", seasonHandler.List)
 			seasons.GET("/current", seasonHandler.GetCurrent)
 			seasons.GET("/:id", seasonHandler.Get)
+			seasons.GET("/:id/chart", viewerMiddleware, seasonHandler.Chart)
 
 			// Protected season routes
 			protectedSeasons := seasons.Use(authMiddleware, adminMiddleware)
@@ -364,6 +525,48 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 
 				c.JSON(http.StatusOK, user)
 			})
+
+			// Content revision history
+			admin.GET("/contents/:id/revisions", revisionHandler.List)
+			admin.POST("/contents/:id/revisions/:revisionId/restore", revisionHandler.Restore)
+
+			// Trash bin
+			admin.GET("/trash", trashHandler.List)
+			admin.POST("/trash/:type/:id/restore", trashHandler.Restore)
+			admin.DELETE("/trash/:type/:id", trashHandler.Purge)
+
+			// Curated collections and homepage layout
+			admin.GET("/collections", collectionHandler.List)
+			admin.POST("/collections", collectionHandler.Create)
+			admin.GET("/collections/:id", collectionHandler.Get)
+			admin.PUT("/collections/:id", collectionHandler.Update)
+			admin.DELETE("/collections/:id", collectionHandler.Delete)
+			admin.PUT("/collections/:id/items", collectionHandler.SetItems)
+			admin.GET("/home-layout", collectionHandler.GetLayout)
+			admin.PUT("/home-layout", collectionHandler.SetLayout)
+
+			// Tag suggestion moderation
+			admin.GET("/tag-suggestions", tagHandler.ListSuggestions)
+			admin.POST("/tag-suggestions/:id/approve", tagHandler.ApproveSuggestion)
+			admin.POST("/tag-suggestions/:id/reject", tagHandler.RejectSuggestion)
+
+			// Intro and credits detection
+			admin.POST("/intro-analyses", introDetectionHandler.Create)
+			admin.GET("/intro-analyses", introDetectionHandler.List)
+			admin.GET("/intro-analyses/:id", introDetectionHandler.Get)
+			admin.GET("/chapter-suggestions", introDetectionHandler.ListSuggestions)
+			admin.POST("/chapter-suggestions/:id/accept", introDetectionHandler.AcceptSuggestion)
+			admin.POST("/chapter-suggestions/:id/reject", introDetectionHandler.RejectSuggestion)
+
+			// Stream and download link health
+			admin.GET("/link-health", linkHandler.HealthReport)
+			admin.POST("/link-health/:kind/:id/check", linkHandler.CheckHealth)
+
+			// Service accounts
+			admin.POST("/service-accounts", sessionOnly, apiKeyHandler.CreateServiceAccount)
+			admin.GET("/service-accounts/:id/api-keys", sessionOnly, apiKeyHandler.ListServiceAccountKeys)
+			admin.POST("/service-accounts/:id/api-keys", sessionOnly, apiKeyHandler.CreateServiceAccountKey)
+			admin.DELETE("/service-accounts/:id/api-keys/:keyId", sessionOnly, apiKeyHandler.RevokeServiceAccountKey)
 		}
 	}
 
@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all configuration for the application
@@ -10,6 +13,19 @@ type Config struct {
 	JWTSecret          string
 	MediaPath          string
 	CorsAllowedOrigins string
+	OIDCProviders      []OIDCProviderConfig
+	MaturityRatings    []string
+	MaturityGuestLimit string // empty means guests are unrestricted
+	MaturityUnrated    string // rating given to titles without one; the strictest by default
+	ExportPath         string
+	DeletionGrace      time.Duration
+	TrashRetention     time.Duration
+	ViewDedupWindow    time.Duration
+
+	// Link health checks
+	LinkCheckInterval  time.Duration // how long a link's last check stays fresh
+	LinkDeadAfter      int           // checks in a row that find a link missing before it counts as dead
+	LinkCheckHostDelay time.Duration // minimum time between two requests to the same host
 }
 
 // DBConfig holds database configuration
@@ -22,8 +38,20 @@ type DBConfig struct {
 	SSLMode  string
 }
 
+// OIDCProviderConfig holds configuration for a single OpenID Connect provider
+type OIDCProviderConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
 // NewConfig creates a new Config
 func NewConfig() *Config {
+	maturityRatings := strings.Split(getEnv("MATURITY_RATINGS", "G,PG,PG-13,R,R+"), ",")
+
 	return &Config{
 		DB: DBConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -36,9 +64,53 @@ func NewConfig() *Config {
 		JWTSecret:          getEnv("JWT_SECRET", "yoursecretkey"),
 		MediaPath:          getEnv("MEDIA_PATH", "./media"),
 		CorsAllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000"),
+		OIDCProviders:      loadOIDCProviders(),
+		MaturityRatings:    maturityRatings,
+		MaturityGuestLimit: getEnv("MATURITY_GUEST_LIMIT", ""),
+		MaturityUnrated:    getEnv("MATURITY_UNRATED", maturityRatings[len(maturityRatings)-1]),
+		ExportPath:         getEnv("EXPORT_PATH", "./exports"),
+		DeletionGrace:      time.Duration(getEnvInt("ACCOUNT_DELETION_GRACE_DAYS", 30)) * 24 * time.Hour,
+		TrashRetention:     time.Duration(getEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		ViewDedupWindow:    time.Duration(getEnvInt("VIEW_DEDUP_WINDOW_MINUTES", 30)) * time.Minute,
+		LinkCheckInterval:  time.Duration(getEnvInt("LINK_CHECK_INTERVAL_HOURS", 6)) * time.Hour,
+		LinkDeadAfter:      getEnvInt("LINK_DEAD_AFTER_FAILURES", 3),
+		LinkCheckHostDelay: time.Duration(getEnvInt("LINK_CHECK_HOST_DELAY_MS", 1000)) * time.Millisecond,
 	}
 }
 
+// loadOIDCProviders reads the providers listed in OIDC_PROVIDERS (e.g. "google,discord").
+// Each provider is configured with OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID,
+// OIDC_<NAME>_CLIENT_SECRET, OIDC_<NAME>_REDIRECT_URL and optionally OIDC_<NAME>_SCOPES.
+func loadOIDCProviders() []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(getEnv("OIDC_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			IssuerURL:    getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", ""),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		})
+	}
+	return providers
+}
+
+// getEnvInt gets an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 // getEnv gets an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
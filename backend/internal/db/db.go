@@ -2,6 +2,7 @@ package db
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/username/anime-streaming/internal/config"
 	"github.com/username/anime-streaming/internal/models"
@@ -39,8 +40,15 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 }
 
 // Migrate runs auto-migration for the database models
-func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+func Migrate(db *gorm.DB, cfg *config.Config) error {
+	if err := dedupeAbsoluteNumbers(db); err != nil {
+		return err
+	}
+	if err := dedupeRunningAnalyses(db); err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(
 		&models.User{},
 		&models.Content{},
 		&models.Episode{},
@@ -49,5 +57,201 @@ func Migrate(db *gorm.DB) error {
 		&models.WatchHistory{},
 		&models.StreamLink{},
 		&models.DownloadLink{},
-	)
+		&models.UserIdentity{},
+		&models.APIKey{},
+		&models.Profile{},
+		&models.DataExport{},
+		&models.ContentRelation{},
+		&models.ContentTitle{},
+		&models.ContentDescription{},
+		&models.Studio{},
+		&models.Person{},
+		&models.Character{},
+		&models.ContentStudio{},
+		&models.ContentStaff{},
+		&models.ContentCharacter{},
+		&models.EpisodeSchedule{},
+		&models.ContentRevision{},
+		&models.SlugRedirect{},
+		&models.Tag{},
+		&models.ContentTag{},
+		&models.TagSuggestion{},
+		&models.Collection{},
+		&models.CollectionItem{},
+		&models.HomeRail{},
+		&models.ViewEvent{},
+		&models.ContentViewHourly{},
+		&models.ContentViewDaily{},
+		&models.EpisodeCour{},
+		&models.StoryArc{},
+		&models.Chapter{},
+		&models.IntroAnalysis{},
+		&models.ChapterSuggestion{},
+	); err != nil {
+		return err
+	}
+
+	if err := migrateContentTypes(db); err != nil {
+		return err
+	}
+	if err := migrateMaturityRatings(db, models.MaturityRating(cfg.MaturityUnrated)); err != nil {
+		return err
+	}
+	if err := migrateEpisodeKinds(db); err != nil {
+		return err
+	}
+	return migrateLinkEpisodes(db)
+}
+
+// migrateMaturityRatings gives titles created before maturity ratings existed
+// the unrated rating, including titles in the trash. Parental limits only let rated titles through, so a
+// title without a rating would be hidden from every limited viewer.
+func migrateMaturityRatings(db *gorm.DB, unrated models.MaturityRating) error {
+	return db.Unscoped().Model(&models.Content{}).
+		Where("maturity_rating IS NULL OR maturity_rating = ''").
+		UpdateColumn("maturity_rating", unrated).Error
+}
+
+// migrateEpisodeKinds gives episodes saved while the type column was free text
+// the regular kind, including episodes in the trash. Episode listings only show
+// specials on request and pick regular episodes by their type, so an episode
+// with an unknown or empty type would disappear from its title.
+func migrateEpisodeKinds(db *gorm.DB) error {
+	kinds := []models.EpisodeKind{
+		models.EpisodeKindRegular,
+		models.EpisodeKindSpecial,
+		models.EpisodeKindOVA,
+		models.EpisodeKindRecap,
+	}
+	return db.Unscoped().Model(&models.Episode{}).
+		Where("type IS NULL OR type NOT IN ?", kinds).
+		UpdateColumn("type", models.EpisodeKindRegular).Error
+}
+
+// dedupeAbsoluteNumbers moves live episodes that share an absolute number with
+// an older episode of their title past the highest number, so the unique index
+// on the numbers can be created
+func dedupeAbsoluteNumbers(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Episode{}, "absolute_number") ||
+		db.Migrator().HasIndex(&models.Episode{}, "idx_episodes_content_absolute") {
+		return nil
+	}
+
+	return db.Exec(`UPDATE episodes SET absolute_number = moved.number
+		FROM (
+			SELECT e.id, ROW_NUMBER() OVER (PARTITION BY e.content_id ORDER BY e.season_number, e.episode_number, e.id) +
+				(SELECT MAX(m.absolute_number) FROM episodes m WHERE m.content_id = e.content_id AND m.deleted_at IS NULL) AS number
+			FROM episodes e
+			WHERE e.deleted_at IS NULL AND EXISTS (
+				SELECT 1 FROM episodes o
+				WHERE o.content_id = e.content_id AND o.absolute_number = e.absolute_number
+					AND o.deleted_at IS NULL AND o.id < e.id)
+		) AS moved
+		WHERE episodes.id = moved.id`).Error
+}
+
+// dedupeRunningAnalyses fails the queued or running intro analyses of a season
+// but the newest, so the unique index on running analyses can be created
+func dedupeRunningAnalyses(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.IntroAnalysis{}) ||
+		db.Migrator().HasIndex(&models.IntroAnalysis{}, "idx_intro_analyses_running") {
+		return nil
+	}
+
+	return db.Exec(`UPDATE intro_analyses SET status = ?, error = ?
+		WHERE status IN ? AND EXISTS (
+			SELECT 1 FROM intro_analyses n
+			WHERE n.content_id = intro_analyses.content_id AND n.season_number = intro_analyses.season_number
+				AND n.status IN ? AND n.id > intro_analyses.id)`,
+		models.IntroAnalysisFailed, "superseded by a newer analysis",
+		[]models.IntroAnalysisStatus{models.IntroAnalysisPending, models.IntroAnalysisProcessing},
+		[]models.IntroAnalysisStatus{models.IntroAnalysisPending, models.IntroAnalysisProcessing}).Error
+}
+
+// migrateContentTypes moves the old free-form contents.type column onto the
+// category_id foreign key. Types without a matching category get one, the
+// well-known names get their kind, and the column is dropped afterwards.
+func migrateContentTypes(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Content{}, "type") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		steps := []string{
+			`INSERT INTO categories (name, description, kind, position, version, created_at, updated_at)
+				SELECT DISTINCT type, '', 'other', 0, 1, NOW(), NOW() FROM contents
+				WHERE type <> '' AND NOT EXISTS (SELECT 1 FROM categories WHERE categories.name = contents.type)`,
+			`UPDATE categories SET kind = 'movie' WHERE name = 'Movie' AND kind = 'other'`,
+			`UPDATE categories SET kind = 'series' WHERE name IN ('Series', 'Anime', 'TV') AND kind = 'other'`,
+			`UPDATE contents SET category_id = categories.id FROM categories
+				WHERE contents.category_id IS NULL AND categories.name = contents.type`,
+		}
+		for _, step := range steps {
+			if err := tx.Exec(step).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropColumn(&models.Content{}, "type")
+	})
+}
+
+// migrateLinkEpisodes moves stream and download links from the old season and
+// episode numbers onto the episode_id foreign key. Links of movies belong to
+// the title itself and keep no episode; series links without a matching episode
+// are disabled. The number columns are dropped afterwards.
+func migrateLinkEpisodes(db *gorm.DB) error {
+	links := []struct {
+		table string
+		model interface{}
+	}{
+		{"stream_links", &models.StreamLink{}},
+		{"download_links", &models.DownloadLink{}},
+	}
+
+	for _, link := range links {
+		if !db.Migrator().HasColumn(link.model, "episode_number") {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// Prefer the live episode when a deleted one shares its numbers
+			err := tx.Exec(`UPDATE `+link.table+` SET episode_id = (
+					SELECT e.id FROM episodes e
+					WHERE e.content_id = `+link.table+`.content_id
+						AND e.season_number = `+link.table+`.season_number
+						AND e.episode_number = `+link.table+`.episode_number
+					ORDER BY e.deleted_at IS NULL DESC, e.id
+					LIMIT 1)
+				WHERE episode_id IS NULL AND NOT EXISTS (
+					SELECT 1 FROM contents c JOIN categories cat ON cat.id = c.category_id
+					WHERE c.id = `+link.table+`.content_id AND cat.kind = ?)`, models.CategoryKindMovie).Error
+			if err != nil {
+				return err
+			}
+
+			// Series links whose numbers match no episode would turn into links of the
+			// whole series once the numbers are gone; switch them off for an admin to sort out
+			orphans := tx.Exec(`UPDATE `+link.table+` SET enabled = false
+				WHERE episode_id IS NULL AND NOT EXISTS (
+					SELECT 1 FROM contents c JOIN categories cat ON cat.id = c.category_id
+					WHERE c.id = `+link.table+`.content_id AND cat.kind = ?)`, models.CategoryKindMovie)
+			if orphans.Error != nil {
+				return orphans.Error
+			}
+			if orphans.RowsAffected > 0 {
+				log.Printf("Disabled %d %s without a matching episode", orphans.RowsAffected, link.table)
+			}
+
+			for _, column := range []string{"episode_number", "season_number"} {
+				if err := tx.Migrator().DropColumn(link.model, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
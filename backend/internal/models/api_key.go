@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyScope represents a permission granted to an API key
+type APIKeyScope string
+
+const (
+	// APIKeyScopeRead allows read-only (GET) requests
+	APIKeyScopeRead APIKeyScope = "read"
+	// APIKeyScopeWrite allows mutating requests
+	APIKeyScopeWrite APIKeyScope = "write"
+	// APIKeyScopeAdmin allows the key to act with the owner's admin role
+	APIKeyScopeAdmin APIKeyScope = "admin"
+)
+
+// APIKey represents a hashed personal or service-account API key
+type APIKey struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	UserID     uint           `gorm:"not null;index" json:"user_id"`
+	Name       string         `gorm:"size:100;not null" json:"name"`
+	Prefix     string         `gorm:"size:16;not null;uniqueIndex" json:"prefix"`
+	KeyHash    string         `gorm:"size:64;not null" json:"-"`
+	Scopes     string         `gorm:"size:100;not null;default:'read'" json:"scopes"` // comma separated
+	ExpiresAt  *time.Time     `json:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	RevokedAt  *time.Time     `json:"revoked_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for APIKey
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// HasScope checks whether the key was granted the given scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range strings.Split(k.Scopes, ",") {
+		if APIKeyScope(strings.TrimSpace(s)) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive checks whether the key is neither revoked nor expired
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -6,6 +6,27 @@ import (
 	"gorm.io/gorm"
 )
 
+// CategoryKind tells how the titles of a category are played back
+type CategoryKind string
+
+const (
+	// CategoryKindMovie is for single feature-length titles
+	CategoryKindMovie CategoryKind = "movie"
+	// CategoryKindSeries is for titles made of episodes
+	CategoryKindSeries CategoryKind = "series"
+	// CategoryKindOther is for everything else, e.g. music videos or trailers
+	CategoryKindOther CategoryKind = "other"
+)
+
+// IsValid checks if the kind is known
+func (k CategoryKind) IsValid() bool {
+	switch k {
+	case CategoryKindMovie, CategoryKindSeries, CategoryKindOther:
+		return true
+	}
+	return false
+}
+
 // Category represents a content category (movies, TV shows, anime, etc.)
 type Category struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
@@ -15,11 +36,25 @@ type Category struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Optimistic locking; bumped on every update and exposed as the ETag
+	Version uint `gorm:"not null;default:1" json:"version"`
+
+	// URL identifier generated from the name; previous values are kept as redirects
+	Slug string `gorm:"size:255;uniqueIndex" json:"slug"`
+
+	// Playback kind, display order and icon; categories can be nested under a parent
+	Kind     CategoryKind `gorm:"size:20;not null;default:'other'" json:"kind"`
+	Position int          `gorm:"not null;default:0" json:"position"`
+	Icon     string       `gorm:"size:100" json:"icon"`
+	ParentID *uint        `gorm:"index" json:"parent_id"`
+
 	// Relationships
-	Contents []Content `gorm:"many2many:content_categories;" json:"contents,omitempty"`
+	Parent   *Category  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Children []Category `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	Contents []Content  `gorm:"many2many:content_categories;" json:"contents,omitempty"`
 }
 
 // TableName specifies the table name for Category
 func (Category) TableName() string {
 	return "categories"
-} 
\ No newline at end of file
+}
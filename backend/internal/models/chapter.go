@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+)
+
+// ChapterKind tells which part of an episode a chapter covers
+type ChapterKind string
+
+const (
+	// ChapterIntro is the opening song
+	ChapterIntro ChapterKind = "intro"
+	// ChapterRecap is a summary of the previous episodes
+	ChapterRecap ChapterKind = "recap"
+	// ChapterMain is the episode itself
+	ChapterMain ChapterKind = "main"
+	// ChapterCredits is the ending song and credits
+	ChapterCredits ChapterKind = "credits"
+	// ChapterPreview is the preview of the next episode
+	ChapterPreview ChapterKind = "preview"
+)
+
+// IsValid checks if the chapter kind is known
+func (k ChapterKind) IsValid() bool {
+	switch k {
+	case ChapterIntro, ChapterRecap, ChapterMain, ChapterCredits, ChapterPreview:
+		return true
+	}
+	return false
+}
+
+// IsSkippable tells whether players offer to skip chapters of this kind
+func (k ChapterKind) IsSkippable() bool {
+	return k == ChapterIntro || k == ChapterRecap || k == ChapterCredits || k == ChapterPreview
+}
+
+// Label is the default chapter title shown in players
+func (k ChapterKind) Label() string {
+	switch k {
+	case ChapterIntro:
+		return "Intro"
+	case ChapterRecap:
+		return "Recap"
+	case ChapterCredits:
+		return "Credits"
+	case ChapterPreview:
+		return "Preview"
+	}
+	return "Episode"
+}
+
+// Chapter marks a part of an episode, in seconds from the start of the video
+type Chapter struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	EpisodeID uint        `gorm:"not null;index" json:"episode_id"`
+	ContentID uint        `gorm:"not null;index" json:"content_id"`
+	Kind      ChapterKind `gorm:"size:20;not null" json:"kind"`
+	Title     string      `gorm:"size:100" json:"title"`
+	Start     float64     `gorm:"not null" json:"start"`
+	End       float64     `gorm:"not null" json:"end"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// TableName specifies the table name for Chapter
+func (Chapter) TableName() string {
+	return "chapters"
+}
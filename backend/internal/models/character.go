@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Character represents a fictional character; the same character may appear in several titles
+type Character struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"size:255;not null;index" json:"name"`
+	NativeName  string         `gorm:"size:255" json:"native_name"`
+	Description string         `gorm:"type:text" json:"description"`
+	ImageURL    string         `gorm:"size:255" json:"image_url"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for Character
+func (Character) TableName() string {
+	return "characters"
+}
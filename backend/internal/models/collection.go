@@ -0,0 +1,126 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Collection is an editor-curated, ordered list of titles, e.g. "Staff picks"
+type Collection struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Title       string         `gorm:"size:255;not null" json:"title"`
+	Description string         `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Window in which the collection is shown to users; nil leaves that side open
+	VisibleFrom  *time.Time `gorm:"index" json:"visible_from"`
+	VisibleUntil *time.Time `gorm:"index" json:"visible_until"`
+
+	// Relationships
+	Items []CollectionItem `gorm:"foreignKey:CollectionID" json:"items,omitempty"`
+}
+
+// TableName specifies the table name for Collection
+func (Collection) TableName() string {
+	return "collections"
+}
+
+// IsVisibleAt reports whether the collection's visibility window contains t
+func (c *Collection) IsVisibleAt(t time.Time) bool {
+	if c.VisibleFrom != nil && t.Before(*c.VisibleFrom) {
+		return false
+	}
+	if c.VisibleUntil != nil && !t.Before(*c.VisibleUntil) {
+		return false
+	}
+	return true
+}
+
+// CollectionItem places a title at a position within a collection
+type CollectionItem struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CollectionID uint      `gorm:"not null;uniqueIndex:idx_collection_item" json:"collection_id"`
+	ContentID    uint      `gorm:"not null;uniqueIndex:idx_collection_item;index" json:"content_id"`
+	Position     int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Relationships
+	Content *Content `gorm:"foreignKey:ContentID" json:"content,omitempty"`
+}
+
+// TableName specifies the table name for CollectionItem
+func (CollectionItem) TableName() string {
+	return "collection_items"
+}
+
+// RailType tells how the items of a homepage rail are chosen
+type RailType string
+
+const (
+	// RailCollection shows the titles of a curated collection in order
+	RailCollection RailType = "collection"
+	// RailNewEpisodes shows the most recently released episodes
+	RailNewEpisodes RailType = "new_episodes"
+	// RailTopRated shows the best rated titles, optionally within a genre
+	RailTopRated RailType = "top_rated"
+	// RailCurrentSeason shows the best rated titles of the active season
+	RailCurrentSeason RailType = "current_season"
+	// RailRecentlyAdded shows the titles added to the catalog last
+	RailRecentlyAdded RailType = "recently_added"
+)
+
+// IsValid checks if the rail type is known
+func (t RailType) IsValid() bool {
+	switch t {
+	case RailCollection, RailNewEpisodes, RailTopRated, RailCurrentSeason, RailRecentlyAdded:
+		return true
+	}
+	return false
+}
+
+// Bounds of the number of items a rail shows
+const (
+	RailLimitDefault = 12
+	RailLimitMax     = 50
+)
+
+// HomeRail is one row of the homepage layout. Collection rails are manual;
+// the other types are filled from the catalog when the layout is resolved.
+type HomeRail struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Title        string    `gorm:"size:255;not null" json:"title"`
+	Type         RailType  `gorm:"size:30;not null" json:"type"`
+	Position     int       `gorm:"not null;default:0;index" json:"position"`
+	Limit        int       `gorm:"not null;default:12" json:"limit"`
+	CollectionID *uint     `gorm:"index" json:"collection_id"` // for collection rails
+	GenreID      *uint     `gorm:"index" json:"genre_id"`      // optional for top rated rails
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Relationships
+	Collection *Collection `gorm:"foreignKey:CollectionID" json:"collection,omitempty"`
+	Genre      *Genre      `gorm:"foreignKey:GenreID" json:"genre,omitempty"`
+}
+
+// TableName specifies the table name for HomeRail
+func (HomeRail) TableName() string {
+	return "home_rails"
+}
+
+// HomeLayout is the resolved homepage: every rail with the items to show
+type HomeLayout struct {
+	Rails []ResolvedRail `json:"rails"`
+}
+
+// ResolvedRail is a rail with its items; episode rails fill Episodes, the others Contents
+type ResolvedRail struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Type        RailType  `json:"type"`
+	Contents    []Content `json:"contents,omitempty"`
+	Episodes    []Episode `json:"episodes,omitempty"`
+}
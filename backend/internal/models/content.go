@@ -14,14 +14,42 @@ type Content struct {
 	ID          uint       `gorm:"primaryKey" json:"id"`
 	Title       string     `gorm:"size:255;not null" json:"title"`
 	Description string     `gorm:"type:text" json:"description"`
-	Type        string     `gorm:"size:20;not null" json:"type"`
+	CategoryID  *uint      `gorm:"index" json:"category_id"`
 	CoverImage  string     `gorm:"size:255" json:"cover_image"`
 	ReleaseDate *time.Time `json:"release_date"`
 	Duration    *int       `json:"duration"` // in minutes, for movies
 	Rating      float32    `gorm:"default:0" json:"rating"`
 	SeasonID    *uint      `json:"season_id"`
 
+	// Age rating from the configured scheme; titles without one get the configured unrated rating
+	MaturityRating MaturityRating `gorm:"size:10;index" json:"maturity_rating"`
+
+	// Broadcast information for titles that are (or will be) airing
+	AiringStatus      AiringStatus `gorm:"size:20;index" json:"airing_status"`
+	BroadcastDay      string       `gorm:"size:10" json:"broadcast_day"`      // lowercase weekday, e.g. "saturday"
+	BroadcastTime     string       `gorm:"size:5" json:"broadcast_time"`      // HH:MM in BroadcastTimezone
+	BroadcastTimezone string       `gorm:"size:50" json:"broadcast_timezone"` // IANA name, e.g. "Asia/Tokyo"
+	EpisodeCount      *int         `json:"episode_count"`                     // expected number of episodes
+
+	// Editorial state; only published and unlisted titles are visible to non-staff
+	Status    PublishStatus `gorm:"size:20;not null;default:'published';index" json:"status"`
+	PublishAt *time.Time    `gorm:"index" json:"publish_at"`
+
+	// Optimistic locking; bumped on every update and exposed as the ETag
+	Version uint `gorm:"not null;default:1" json:"version"`
+
+	// URL identifier generated from the title; previous values are kept as redirects
+	Slug string `gorm:"size:255;uniqueIndex" json:"slug"`
+
+	// Name of the category, for clients that still read the old free-form type; not stored
+	Type string `gorm:"-" json:"type,omitempty"`
+
+	// Title and description picked from Accept-Language; not stored
+	DisplayTitle       string `gorm:"-" json:"display_title,omitempty"`
+	DisplayDescription string `gorm:"-" json:"display_description,omitempty"`
+
 	// Tambahan field baru
+	// Links of the title itself, i.e. of a movie; episode links are loaded with their episode
 	DownloadLinks []DownloadLink `gorm:"foreignKey:ContentID" json:"download_links"`
 	StreamLinks   []StreamLink   `gorm:"foreignKey:ContentID" json:"stream_links"`
 
@@ -34,37 +62,145 @@ type Content struct {
 	Genres     []Genre    `gorm:"many2many:content_genres;" json:"genres,omitempty"`
 	Categories []Category `gorm:"many2many:content_categories;" json:"categories,omitempty"`
 	Season     *Season    `gorm:"foreignKey:SeasonID" json:"season,omitempty"`
+	Category   *Category  `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+
+	// Alternative and localized titles and descriptions
+	Titles       []ContentTitle       `gorm:"foreignKey:ContentID" json:"titles,omitempty"`
+	Descriptions []ContentDescription `gorm:"foreignKey:ContentID" json:"descriptions,omitempty"`
+
+	// Free-form tags, most relevant first
+	Tags []ContentTag `gorm:"foreignKey:ContentID" json:"tags,omitempty"`
+}
+
+// AfterFind fills Type from the category when it was preloaded
+func (c *Content) AfterFind(tx *gorm.DB) error {
+	if c.Category != nil {
+		c.Type = c.Category.Name
+	}
+	return nil
+}
+
+// LinkKind tells stream links and download links apart in the link endpoints
+type LinkKind string
+
+const (
+	// LinkKindStream is a link to play an episode
+	LinkKindStream LinkKind = "stream"
+	// LinkKindDownload is a link to download an episode
+	LinkKindDownload LinkKind = "download"
+)
+
+// IsValid checks if the link kind is known
+func (k LinkKind) IsValid() bool {
+	return k == LinkKindStream || k == LinkKindDownload
+}
+
+// LinkHealthStatus is what the link checker last found at a link's URL
+type LinkHealthStatus string
+
+const (
+	// LinkHealthUnknown is a link that has not been checked yet
+	LinkHealthUnknown LinkHealthStatus = "unknown"
+	// LinkHealthAlive is a link whose last check succeeded
+	LinkHealthAlive LinkHealthStatus = "alive"
+	// LinkHealthFailing is a link whose latest checks failed, but not often enough to give up on it
+	LinkHealthFailing LinkHealthStatus = "failing"
+	// LinkHealthDead is a link found missing by too many checks in a row; it is hidden from viewers
+	LinkHealthDead LinkHealthStatus = "dead"
+)
+
+// IsValid checks if the health status is known
+func (s LinkHealthStatus) IsValid() bool {
+	switch s {
+	case LinkHealthUnknown, LinkHealthAlive, LinkHealthFailing, LinkHealthDead:
+		return true
+	}
+	return false
+}
+
+// LinkHealth is the outcome of the latest checks of a link's URL
+type LinkHealth struct {
+	HealthStatus  LinkHealthStatus `gorm:"size:10;not null;default:'unknown';index" json:"health_status"`
+	HTTPStatus    int              `gorm:"not null;default:0" json:"http_status"` // 0 when no response came back
+	LatencyMs     int              `gorm:"not null;default:0" json:"latency_ms"`
+	CheckError    string           `gorm:"size:255" json:"check_error,omitempty"`
+	FailureCount  int              `gorm:"not null;default:0" json:"failure_count"` // consecutive failed checks
+	LastCheckedAt *time.Time       `gorm:"index" json:"last_checked_at"`
+}
+
+// Reset forgets the checks, e.g. once the URL changed
+func (h *LinkHealth) Reset() {
+	*h = LinkHealth{HealthStatus: LinkHealthUnknown}
 }
 
 // DownloadLink represents a download link for content
 type DownloadLink struct {
-	ID            uint           `gorm:"primaryKey" json:"id"`
-	ContentID     uint           `gorm:"not null" json:"content_id"`
-	Name          string         `gorm:"size:100;not null" json:"name"`
-	Quality       string         `gorm:"size:20" json:"quality"`
-	URL           string         `gorm:"type:text;not null" json:"url"`
-	Server        string         `gorm:"size:50;not null;default:'external'" json:"server"`
-	EpisodeNumber int            `gorm:"default:1" json:"episode_number"`
-	SeasonNumber  int            `gorm:"default:1" json:"season_number"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ContentID uint   `gorm:"not null;index" json:"content_id"`
+	EpisodeID *uint  `gorm:"index" json:"episode_id"` // nil for movies
+	Name      string `gorm:"size:100;not null" json:"name"`
+	Quality   string `gorm:"size:20" json:"quality"`
+	URL       string `gorm:"type:text;not null" json:"url"`
+	Server    string `gorm:"size:50;not null;default:'external'" json:"server"`
+	Priority  int    `gorm:"not null;default:0" json:"priority"` // lower comes first
+	Enabled   bool   `gorm:"not null;default:true" json:"enabled"`
+
+	LinkHealth `gorm:"embedded"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // StreamLink represents a streaming link for content
 type StreamLink struct {
-	ID            uint           `gorm:"primaryKey" json:"id"`
-	ContentID     uint           `gorm:"not null" json:"content_id"`
-	Name          string         `gorm:"size:100;not null" json:"name"`
-	Quality       string         `gorm:"size:20" json:"quality"`
-	URL           string         `gorm:"type:text;not null" json:"url"`
-	Type          string         `gorm:"size:20;default:'embed'" json:"type"` // 'embed' atau 'self-hosted'
-	Server        string         `gorm:"size:50;not null;default:'local'" json:"server"`
-	EpisodeNumber int            `gorm:"default:1" json:"episode_number"`
-	SeasonNumber  int            `gorm:"default:1" json:"season_number"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ContentID uint   `gorm:"not null;index" json:"content_id"`
+	EpisodeID *uint  `gorm:"index" json:"episode_id"` // nil for movies
+	Name      string `gorm:"size:100;not null" json:"name"`
+	Quality   string `gorm:"size:20" json:"quality"`
+	URL       string `gorm:"type:text;not null" json:"url"`
+	Type      string `gorm:"size:20;default:'embed'" json:"type"` // 'embed' atau 'self-hosted'
+	Server    string `gorm:"size:50;not null;default:'local'" json:"server"`
+	Priority  int    `gorm:"not null;default:0" json:"priority"` // lower comes first
+	Enabled   bool   `gorm:"not null;default:true" json:"enabled"`
+
+	LinkHealth `gorm:"embedded"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// LinkHealthEntry is a row of the link health report
+type LinkHealthEntry struct {
+	Kind          LinkKind `json:"kind"`
+	ID            uint     `json:"id"`
+	ContentID     uint     `json:"content_id"`
+	ContentTitle  string   `json:"content_title"`
+	EpisodeID     *uint    `json:"episode_id"`
+	SeasonNumber  *int     `json:"season_number"`
+	EpisodeNumber *int     `json:"episode_number"`
+	Name          string   `json:"name"`
+	URL           string   `json:"url"`
+	Enabled       bool     `json:"enabled"`
+
+	LinkHealth `gorm:"embedded"`
+}
+
+// LinkHealthReport counts the links of each kind by health status and lists some of them
+type LinkHealthReport struct {
+	Summary  map[LinkKind]map[LinkHealthStatus]int64 `json:"summary"`
+	Data     []LinkHealthEntry                       `json:"data"`
+	Total    int64                                   `json:"total"`
+	Page     int                                     `json:"page"`
+	PageSize int                                     `json:"pageSize"`
+}
+
+// EpisodeLinks holds the stream and download links of an episode in priority order
+type EpisodeLinks struct {
+	StreamLinks   []StreamLink   `json:"stream_links"`
+	DownloadLinks []DownloadLink `json:"download_links"`
 }
 
 // TableName specifies the table name for Content
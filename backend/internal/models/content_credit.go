@@ -0,0 +1,60 @@
+package models
+
+// ContentStudio links a studio to a content with its role (e.g. "animation", "production")
+type ContentStudio struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ContentID uint   `gorm:"not null;uniqueIndex:idx_content_studio_role" json:"content_id"`
+	StudioID  uint   `gorm:"not null;uniqueIndex:idx_content_studio_role;index" json:"studio_id"`
+	Role      string `gorm:"size:50;not null;uniqueIndex:idx_content_studio_role" json:"role"`
+
+	// Relationships
+	Studio *Studio `gorm:"foreignKey:StudioID" json:"studio,omitempty"`
+}
+
+// TableName specifies the table name for ContentStudio
+func (ContentStudio) TableName() string {
+	return "content_studios"
+}
+
+// ContentStaff links a person to a content with a staff role (e.g. "Director")
+type ContentStaff struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	ContentID uint   `gorm:"not null;uniqueIndex:idx_content_staff_role" json:"content_id"`
+	PersonID  uint   `gorm:"not null;uniqueIndex:idx_content_staff_role;index" json:"person_id"`
+	Role      string `gorm:"size:100;not null;uniqueIndex:idx_content_staff_role" json:"role"`
+
+	// Relationships
+	Person *Person `gorm:"foreignKey:PersonID" json:"person,omitempty"`
+}
+
+// TableName specifies the table name for ContentStaff
+func (ContentStaff) TableName() string {
+	return "content_staff"
+}
+
+// ContentCharacter casts a character in a content, voiced by a person in a given language.
+// A character without a voice actor has a nil PersonID.
+type ContentCharacter struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	ContentID   uint   `gorm:"not null;index" json:"content_id"`
+	CharacterID uint   `gorm:"not null;index" json:"character_id"`
+	Role        string `gorm:"size:20;not null;default:'supporting'" json:"role"` // main, supporting
+	PersonID    *uint  `gorm:"index" json:"person_id"`
+	Language    string `gorm:"size:10" json:"language"` // dub language of the voice actor, e.g. "ja", "en"
+
+	// Relationships
+	Character *Character `gorm:"foreignKey:CharacterID" json:"character,omitempty"`
+	Person    *Person    `gorm:"foreignKey:PersonID" json:"voice_actor,omitempty"`
+}
+
+// TableName specifies the table name for ContentCharacter
+func (ContentCharacter) TableName() string {
+	return "content_characters"
+}
+
+// ContentCredits groups every credit of a content
+type ContentCredits struct {
+	Studios    []ContentStudio    `json:"studios"`
+	Staff      []ContentStaff     `json:"staff"`
+	Characters []ContentCharacter `json:"characters"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+)
+
+// RelationType represents how two titles of a franchise are related
+type RelationType string
+
+const (
+	// RelationSequel points to the title that follows this one
+	RelationSequel RelationType = "sequel"
+	// RelationPrequel points to the title that comes before this one
+	RelationPrequel RelationType = "prequel"
+	// RelationSideStory points to a side story of this title
+	RelationSideStory RelationType = "side_story"
+	// RelationSpinOff points to a spin-off of this title
+	RelationSpinOff RelationType = "spin_off"
+	// RelationParentStory points to the main story of a side story or spin-off
+	RelationParentStory RelationType = "parent_story"
+	// RelationAlternativeVersion points to a retelling of the same story
+	RelationAlternativeVersion RelationType = "alternative_version"
+	// RelationSummary points to a recap of this title
+	RelationSummary RelationType = "summary"
+	// RelationFullStory points to the title a summary recaps
+	RelationFullStory RelationType = "full_story"
+)
+
+// relationInverses maps every relation type to the edge stored in the opposite direction
+var relationInverses = map[RelationType]RelationType{
+	RelationSequel:             RelationPrequel,
+	RelationPrequel:            RelationSequel,
+	RelationSideStory:          RelationParentStory,
+	RelationSpinOff:            RelationParentStory,
+	RelationParentStory:        RelationSideStory,
+	RelationAlternativeVersion: RelationAlternativeVersion,
+	RelationSummary:            RelationFullStory,
+	RelationFullStory:          RelationSummary,
+}
+
+// IsValid checks if the relation type is known
+func (t RelationType) IsValid() bool {
+	_, ok := relationInverses[t]
+	return ok
+}
+
+// Inverse returns the relation type seen from the related title
+func (t RelationType) Inverse() RelationType {
+	return relationInverses[t]
+}
+
+// ContentRelation is a typed, directed edge between two titles.
+// Every edge is stored together with its inverse.
+type ContentRelation struct {
+	ID               uint         `gorm:"primaryKey" json:"id"`
+	ContentID        uint         `gorm:"not null;uniqueIndex:idx_content_relation_pair" json:"content_id"`
+	RelatedContentID uint         `gorm:"not null;uniqueIndex:idx_content_relation_pair;index" json:"related_content_id"`
+	Type             RelationType `gorm:"size:30;not null" json:"type"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+
+	// Relationships
+	RelatedContent *Content `gorm:"foreignKey:RelatedContentID" json:"related_content,omitempty"`
+}
+
+// TableName specifies the table name for ContentRelation
+func (ContentRelation) TableName() string {
+	return "content_relations"
+}
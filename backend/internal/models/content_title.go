@@ -0,0 +1,112 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// TitleKind represents the kind of an alternative title
+type TitleKind string
+
+const (
+	// TitleKindOfficial is the licensed title in a language
+	TitleKindOfficial TitleKind = "official"
+	// TitleKindSynonym is an abbreviation or fan name, used for search only
+	TitleKindSynonym TitleKind = "synonym"
+	// TitleKindRomaji is the romanized original title
+	TitleKindRomaji TitleKind = "romaji"
+	// TitleKindNative is the title in its original script
+	TitleKindNative TitleKind = "native"
+)
+
+// IsValid checks if the title kind is known
+func (k TitleKind) IsValid() bool {
+	switch k {
+	case TitleKindOfficial, TitleKindSynonym, TitleKindRomaji, TitleKindNative:
+		return true
+	}
+	return false
+}
+
+// ContentTitle is an alternative or localized title of a content
+type ContentTitle struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ContentID uint      `gorm:"not null;index" json:"content_id"`
+	Language  string    `gorm:"size:10;not null" json:"language"` // BCP 47 tag, e.g. "en", "ja", "id"
+	Kind      TitleKind `gorm:"size:20;not null;default:'official'" json:"kind"`
+	Title     string    `gorm:"size:255;not null;index" json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for ContentTitle
+func (ContentTitle) TableName() string {
+	return "content_titles"
+}
+
+// ContentDescription is a localized description of a content
+type ContentDescription struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ContentID   uint      `gorm:"not null;uniqueIndex:idx_content_description_language" json:"content_id"`
+	Language    string    `gorm:"size:10;not null;uniqueIndex:idx_content_description_language" json:"language"`
+	Description string    `gorm:"type:text;not null" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for ContentDescription
+func (ContentDescription) TableName() string {
+	return "content_descriptions"
+}
+
+// titleKindPreference is the order in which title kinds are picked for display
+var titleKindPreference = []TitleKind{TitleKindOfficial, TitleKindNative, TitleKindRomaji}
+
+// Localize fills DisplayTitle and DisplayDescription from the first of the
+// preferred languages that has a translation, falling back to Title and Description.
+// Titles and Descriptions must be loaded.
+func (c *Content) Localize(languages []string) {
+	c.DisplayTitle = c.Title
+	c.DisplayDescription = c.Description
+
+	if title, ok := c.localizedTitle(languages); ok {
+		c.DisplayTitle = title
+	}
+	for _, language := range languages {
+		if description, ok := c.localizedDescription(language); ok {
+			c.DisplayDescription = description
+			break
+		}
+	}
+}
+
+func (c *Content) localizedTitle(languages []string) (string, bool) {
+	for _, language := range languages {
+		for _, kind := range titleKindPreference {
+			for _, title := range c.Titles {
+				if title.Kind == kind && languageMatches(title.Language, language) {
+					return title.Title, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func (c *Content) localizedDescription(language string) (string, bool) {
+	for _, description := range c.Descriptions {
+		if languageMatches(description.Language, language) {
+			return description.Description, true
+		}
+	}
+	return "", false
+}
+
+// languageMatches compares language tags, letting "en" match "en-US" and vice versa
+func languageMatches(tag, wanted string) bool {
+	tag, wanted = strings.ToLower(tag), strings.ToLower(wanted)
+	if tag == wanted {
+		return true
+	}
+	return strings.SplitN(tag, "-", 2)[0] == strings.SplitN(wanted, "-", 2)[0]
+}
@@ -3,24 +3,16 @@ package models
 // ContentTypeHelper provides helper functions for content types
 type ContentTypeHelper struct{}
 
-// IsMovie checks if the given type is a movie
-func (h *ContentTypeHelper) IsMovie(contentType string) bool {
-	return contentType == "Movie"
+// IsMovie checks if the content belongs to a movie category.
+// The content must be loaded with its Category.
+func (h *ContentTypeHelper) IsMovie(content *Content) bool {
+	return content.Category != nil && content.Category.Kind == CategoryKindMovie
 }
 
-// IsSeries checks if the given type is a series or anime
-func (h *ContentTypeHelper) IsSeries(contentType string) bool {
-	return contentType == "Series" || contentType == "Anime"
-}
-
-// IsValidType checks if the given type is valid
-func (h *ContentTypeHelper) IsValidType(contentType string, categories []Category) bool {
-	for _, category := range categories {
-		if contentType == category.Name {
-			return true
-		}
-	}
-	return false
+// IsSeries checks if the content belongs to a series category.
+// The content must be loaded with its Category.
+func (h *ContentTypeHelper) IsSeries(content *Content) bool {
+	return content.Category != nil && content.Category.Kind == CategoryKindSeries
 }
 
 // NewContentTypeHelper creates a new ContentTypeHelper
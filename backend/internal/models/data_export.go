@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// DataExportStatus represents the state of a personal data export job
+type DataExportStatus string
+
+const (
+	// DataExportPending is a queued export
+	DataExportPending DataExportStatus = "pending"
+	// DataExportProcessing is an export being built
+	DataExportProcessing DataExportStatus = "processing"
+	// DataExportCompleted is an export ready for download
+	DataExportCompleted DataExportStatus = "completed"
+	// DataExportFailed is an export that could not be built
+	DataExportFailed DataExportStatus = "failed"
+)
+
+// DataExport represents a user's request for a copy of their personal data
+type DataExport struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	UserID      uint             `gorm:"not null;index" json:"user_id"`
+	Status      DataExportStatus `gorm:"size:20;not null;default:'pending'" json:"status"`
+	FilePath    string           `gorm:"size:255" json:"-"`
+	Error       string           `gorm:"size:255" json:"error,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at"`
+	ExpiresAt   *time.Time       `gorm:"index" json:"expires_at"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// TableName specifies the table name for DataExport
+func (DataExport) TableName() string {
+	return "data_exports"
+}
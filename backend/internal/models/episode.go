@@ -6,25 +6,159 @@ import (
 	"gorm.io/gorm"
 )
 
+// EpisodeKind tells regular episodes apart from the extras released alongside them
+type EpisodeKind string
+
+const (
+	// EpisodeKindRegular is a regular episode of the broadcast run
+	EpisodeKindRegular EpisodeKind = "episode"
+	// EpisodeKindSpecial is a special, e.g. a broadcast special or a bonus short
+	EpisodeKindSpecial EpisodeKind = "special"
+	// EpisodeKindOVA is an original video animation released outside the broadcast
+	EpisodeKindOVA EpisodeKind = "ova"
+	// EpisodeKindRecap is a recap of earlier episodes
+	EpisodeKindRecap EpisodeKind = "recap"
+)
+
+// IsValid checks if the kind is known
+func (k EpisodeKind) IsValid() bool {
+	switch k {
+	case EpisodeKindRegular, EpisodeKindSpecial, EpisodeKindOVA, EpisodeKindRecap:
+		return true
+	}
+	return false
+}
+
+// IsSpecial tells whether the kind is anything but a regular episode
+func (k EpisodeKind) IsSpecial() bool {
+	return k != EpisodeKindRegular
+}
+
+// EpisodeCanon tells whether an episode adapts the source material
+type EpisodeCanon string
+
+const (
+	// EpisodeCanonCanon is an episode adapted from the source material
+	EpisodeCanonCanon EpisodeCanon = "canon"
+	// EpisodeCanonFiller is an anime-original episode outside the source story
+	EpisodeCanonFiller EpisodeCanon = "filler"
+	// EpisodeCanonMixed is an episode that mixes canon and filler scenes
+	EpisodeCanonMixed EpisodeCanon = "mixed"
+)
+
+// IsValid checks if the canon flag is known
+func (c EpisodeCanon) IsValid() bool {
+	switch c {
+	case EpisodeCanonCanon, EpisodeCanonFiller, EpisodeCanonMixed:
+		return true
+	}
+	return false
+}
+
 // Episode represents an episode of a series
 type Episode struct {
 	ID            uint           `gorm:"primaryKey" json:"id"`
-	ContentID     uint           `gorm:"not null" json:"content_id"`
+	ContentID     uint           `gorm:"not null;uniqueIndex:idx_episodes_content_absolute,where:deleted_at IS NULL" json:"content_id"`
 	Title         string         `gorm:"size:255;not null" json:"title"`
 	Description   string         `gorm:"type:text" json:"description"`
-	Type          string         `gorm:"size:20;not null;default:'episode'" json:"type"`
+	Type          EpisodeKind    `gorm:"size:20;not null;default:'episode';index" json:"type"`
 	EpisodeNumber int            `gorm:"not null" json:"episode_number"`
 	SeasonNumber  int            `gorm:"default:1" json:"season_number"`
 	VideoPath     string         `gorm:"size:255;not null" json:"video_path"`
 	Duration      int            `gorm:"default:0" json:"duration"` // in seconds
 	ThumbnailURL  string         `gorm:"size:255" json:"thumbnail_url"`
 	ReleaseDate   *time.Time     `json:"release_date"`
+	Status        PublishStatus  `gorm:"size:20;not null;default:'published';index" json:"status"`
+	PublishAt     *time.Time     `gorm:"index" json:"publish_at"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Optimistic locking; bumped on every update and exposed as the ETag
+	Version uint `gorm:"not null;default:1" json:"version"`
+
+	// Number counted across all seasons; only regular episodes have one and no
+	// two live episodes of a title share it. Filled in from the episode order
+	// when not set explicitly.
+	AbsoluteNumber *int `gorm:"index;uniqueIndex:idx_episodes_content_absolute" json:"absolute_number"`
+
+	// Canon, filler or mixed, for the episode guide
+	Canon EpisodeCanon `gorm:"size:10;not null;default:'canon';index" json:"canon"`
+
+	// Relationships; only loaded where episodes are listed outside their title
+	Content *Content `gorm:"foreignKey:ContentID" json:"content,omitempty"`
+
+	// Intro, credits and other markers in playback order
+	Chapters []Chapter `gorm:"foreignKey:EpisodeID" json:"chapters,omitempty"`
+
+	// Links in priority order; purging an episode takes its links along
+	StreamLinks   []StreamLink   `gorm:"foreignKey:EpisodeID;constraint:OnDelete:CASCADE" json:"stream_links,omitempty"`
+	DownloadLinks []DownloadLink `gorm:"foreignKey:EpisodeID;constraint:OnDelete:CASCADE" json:"download_links,omitempty"`
 }
 
 // TableName specifies the table name for Episode
 func (Episode) TableName() string {
 	return "episodes"
 }
+
+// EpisodeCour maps a range of season-relative episode numbers to one part of a
+// split-cour season, e.g. episodes 13-24 of season 2 aired as "Part 2"
+type EpisodeCour struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	ContentID    uint       `gorm:"not null;uniqueIndex:idx_episode_cour" json:"content_id"`
+	SeasonNumber int        `gorm:"not null;uniqueIndex:idx_episode_cour" json:"season_number"`
+	Number       int        `gorm:"not null;uniqueIndex:idx_episode_cour" json:"number"` // 1 for the first cour of the season
+	Title        string     `gorm:"size:255" json:"title"`
+	FirstEpisode int        `gorm:"not null" json:"first_episode"`
+	LastEpisode  int        `gorm:"not null" json:"last_episode"`
+	StartDate    *time.Time `gorm:"type:date" json:"start_date"`
+}
+
+// TableName specifies the table name for EpisodeCour
+func (EpisodeCour) TableName() string {
+	return "episode_cours"
+}
+
+// EpisodeFilter narrows down the episodes of a title
+type EpisodeFilter struct {
+	Season          *int
+	Cour            *int // only with Season
+	IncludeSpecials bool
+	SkipFiller      bool // mixed episodes are kept
+}
+
+// StoryArc is a named range of episodes, counted by absolute number
+type StoryArc struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	ContentID    uint   `gorm:"not null;index" json:"content_id"`
+	Title        string `gorm:"size:255;not null" json:"title"`
+	Description  string `gorm:"type:text" json:"description"`
+	FirstEpisode int    `gorm:"not null" json:"first_episode"`
+	LastEpisode  int    `gorm:"not null" json:"last_episode"`
+
+	// When set, every episode of the arc is flagged with it on save
+	Canon EpisodeCanon `gorm:"size:10" json:"canon,omitempty"`
+}
+
+// TableName specifies the table name for StoryArc
+func (StoryArc) TableName() string {
+	return "story_arcs"
+}
+
+// GuideArc is a story arc with its episodes
+type GuideArc struct {
+	StoryArc
+	Episodes    []Episode `json:"episodes"`
+	FillerCount int       `json:"filler_count"`
+}
+
+// EpisodeGuide lists the episodes of a title by story arc, with the
+// regular episodes outside every arc and the specials at the end
+type EpisodeGuide struct {
+	Arcs        []GuideArc `json:"arcs"`
+	Ungrouped   []Episode  `json:"ungrouped"`
+	Specials    []Episode  `json:"specials"`
+	CanonCount  int        `json:"canon_count"`
+	FillerCount int        `json:"filler_count"`
+	MixedCount  int        `json:"mixed_count"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// AiringStatus represents the broadcast state of a title
+type AiringStatus string
+
+const (
+	// AiringNotYetAired is an announced title that has not started
+	AiringNotYetAired AiringStatus = "not_yet_aired"
+	// AiringCurrently is a title with new episodes being broadcast
+	AiringCurrently AiringStatus = "airing"
+	// AiringFinished is a title whose broadcast has ended
+	AiringFinished AiringStatus = "finished"
+	// AiringHiatus is a title whose broadcast is paused
+	AiringHiatus AiringStatus = "hiatus"
+	// AiringCancelled is a title that was cancelled
+	AiringCancelled AiringStatus = "cancelled"
+)
+
+// IsValid checks if the airing status is known; empty means unknown
+func (s AiringStatus) IsValid() bool {
+	switch s {
+	case "", AiringNotYetAired, AiringCurrently, AiringFinished, AiringHiatus, AiringCancelled:
+		return true
+	}
+	return false
+}
+
+// EpisodeSchedule is an upcoming broadcast of an episode
+type EpisodeSchedule struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ContentID     uint      `gorm:"not null;index" json:"content_id"`
+	SeasonNumber  int       `gorm:"default:1" json:"season_number"`
+	EpisodeNumber int       `gorm:"not null" json:"episode_number"`
+	Title         string    `gorm:"size:255" json:"title"`
+	AirsAt        time.Time `gorm:"not null;index" json:"airs_at"` // stored in UTC
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Relationships
+	Content *Content `gorm:"foreignKey:ContentID" json:"content,omitempty"`
+}
+
+// TableName specifies the table name for EpisodeSchedule
+func (EpisodeSchedule) TableName() string {
+	return "episode_schedules"
+}
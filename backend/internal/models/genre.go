@@ -15,6 +15,12 @@ type Genre struct {
 	UpdatedAt   time.Time      `json:"updatedAt"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Optimistic locking; bumped on every update and exposed as the ETag
+	Version uint `gorm:"not null;default:1" json:"version"`
+
+	// URL identifier generated from the name; previous values are kept as redirects
+	Slug string `gorm:"size:255;uniqueIndex" json:"slug"`
+
 	// Relationships
 	Contents []Content `json:"contents,omitempty" gorm:"many2many:content_genres;"`
 }
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+)
+
+// IntroAnalysisStatus represents the state of an intro detection job
+type IntroAnalysisStatus string
+
+const (
+	// IntroAnalysisPending is a queued analysis
+	IntroAnalysisPending IntroAnalysisStatus = "pending"
+	// IntroAnalysisProcessing is an analysis being run
+	IntroAnalysisProcessing IntroAnalysisStatus = "processing"
+	// IntroAnalysisCompleted is an analysis whose suggestions are ready for review
+	IntroAnalysisCompleted IntroAnalysisStatus = "completed"
+	// IntroAnalysisFailed is an analysis that could not be run
+	IntroAnalysisFailed IntroAnalysisStatus = "failed"
+)
+
+// IntroAnalysis is a background job that fingerprints the audio of the
+// episodes of one season and proposes intro and credits chapters
+type IntroAnalysis struct {
+	ID           uint                `gorm:"primaryKey" json:"id"`
+	ContentID    uint                `gorm:"not null;index;uniqueIndex:idx_intro_analyses_running,where:status IN ('pending'\\,'processing')" json:"content_id"`
+	SeasonNumber int                 `gorm:"not null;uniqueIndex:idx_intro_analyses_running" json:"season_number"`
+	Status       IntroAnalysisStatus `gorm:"size:20;not null;default:'pending'" json:"status"`
+	Error        string              `gorm:"size:255" json:"error,omitempty"`
+	EpisodeCount int                 `gorm:"not null;default:0" json:"episode_count"`
+	Suggested    int                 `gorm:"not null;default:0" json:"suggested"`
+	CompletedAt  *time.Time          `json:"completed_at"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+
+	// Relationships
+	Suggestions []ChapterSuggestion `gorm:"foreignKey:AnalysisID" json:"suggestions,omitempty"`
+}
+
+// TableName specifies the table name for IntroAnalysis
+func (IntroAnalysis) TableName() string {
+	return "intro_analyses"
+}
+
+// ChapterSuggestionStatus represents the review state of a detected chapter
+type ChapterSuggestionStatus string
+
+const (
+	// ChapterSuggestionPending is waiting for an admin
+	ChapterSuggestionPending ChapterSuggestionStatus = "pending"
+	// ChapterSuggestionAccepted was turned into a chapter
+	ChapterSuggestionAccepted ChapterSuggestionStatus = "accepted"
+	// ChapterSuggestionRejected was dismissed
+	ChapterSuggestionRejected ChapterSuggestionStatus = "rejected"
+)
+
+// ChapterSuggestion is an intro or credits chapter found by an analysis.
+// Confidence runs from 0 to 1: how many of the compared episodes share the
+// segment, weighted by how closely their audio matches.
+type ChapterSuggestion struct {
+	ID         uint                    `gorm:"primaryKey" json:"id"`
+	AnalysisID uint                    `gorm:"not null;index" json:"analysis_id"`
+	ContentID  uint                    `gorm:"not null;index" json:"content_id"`
+	EpisodeID  uint                    `gorm:"not null;index" json:"episode_id"`
+	Kind       ChapterKind             `gorm:"size:20;not null" json:"kind"`
+	Start      float64                 `gorm:"not null" json:"start"`
+	End        float64                 `gorm:"not null" json:"end"`
+	Confidence float64                 `gorm:"not null" json:"confidence"`
+	Status     ChapterSuggestionStatus `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+
+	// Relationships
+	Episode *Episode `gorm:"foreignKey:EpisodeID" json:"episode,omitempty"`
+}
+
+// TableName specifies the table name for ChapterSuggestion
+func (ChapterSuggestion) TableName() string {
+	return "chapter_suggestions"
+}
@@ -0,0 +1,93 @@
+package models
+
+// MaturityRating represents a content age rating such as "PG-13"
+type MaturityRating string
+
+// DefaultMaturityRatings is the rating scheme used when none is configured
+var DefaultMaturityRatings = []string{"G", "PG", "PG-13", "R", "R+"}
+
+// MaturityScheme is an ordered list of ratings from the most to the least family friendly
+type MaturityScheme []MaturityRating
+
+// NewMaturityScheme creates a MaturityScheme from rating names in ascending order
+func NewMaturityScheme(ratings []string) MaturityScheme {
+	scheme := make(MaturityScheme, 0, len(ratings))
+	for _, rating := range ratings {
+		scheme = append(scheme, MaturityRating(rating))
+	}
+	return scheme
+}
+
+// IsValid checks if the rating belongs to the scheme
+func (s MaturityScheme) IsValid(rating MaturityRating) bool {
+	return s.level(rating) >= 0
+}
+
+// Lowest returns the most family friendly rating in the scheme
+func (s MaturityScheme) Lowest() MaturityRating {
+	if len(s) == 0 {
+		return ""
+	}
+	return s[0]
+}
+
+// Stricter returns whichever of the two limits allows less; an empty limit means unrestricted
+func (s MaturityScheme) Stricter(a, b MaturityRating) MaturityRating {
+	if a == "" {
+		return b
+	}
+	if b == "" || s.level(a) <= s.level(b) {
+		return a
+	}
+	return b
+}
+
+// AllowedUpTo lists every rating up to and including max
+func (s MaturityScheme) AllowedUpTo(max MaturityRating) []MaturityRating {
+	level := s.level(max)
+	if level < 0 {
+		return []MaturityRating{}
+	}
+	return append([]MaturityRating{}, s[:level+1]...)
+}
+
+func (s MaturityScheme) level(rating MaturityRating) int {
+	for i, r := range s {
+		if r == rating {
+			return i
+		}
+	}
+	return -1
+}
+
+// Viewer describes who is browsing the catalog and is used to scope content queries
+type Viewer struct {
+	UserID    uint // zero for anonymous visitors
+	ProfileID *uint
+	Role      Role
+	// AllowedRatings lists the maturity ratings the viewer may see; nil means unrestricted
+	AllowedRatings []MaturityRating
+}
+
+// IsRestricted checks if the viewer has a maturity limit
+func (v *Viewer) IsRestricted() bool {
+	return v != nil && v.AllowedRatings != nil
+}
+
+// IsStaff checks if the viewer may see unpublished content; a nil viewer is internal
+func (v *Viewer) IsStaff() bool {
+	return v == nil || v.Role == RoleAdmin
+}
+
+// CanSee checks whether content with the given rating is visible to the viewer
+func (v *Viewer) CanSee(rating MaturityRating) bool {
+	if !v.IsRestricted() {
+		return true
+	}
+	for _, allowed := range v.AllowedRatings {
+		if allowed == rating {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Person represents a staff member or voice actor
+type Person struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	Name       string         `gorm:"size:255;not null;index" json:"name"`
+	NativeName string         `gorm:"size:255" json:"native_name"`
+	Bio        string         `gorm:"type:text" json:"bio"`
+	ImageURL   string         `gorm:"size:255" json:"image_url"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for Person
+func (Person) TableName() string {
+	return "people"
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxProfilesPerUser limits how many viewer profiles a single account may have
+const MaxProfilesPerUser = 5
+
+// Profile represents a viewer profile under a user account
+type Profile struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	UserID        uint           `gorm:"not null;index" json:"user_id"`
+	Name          string         `gorm:"size:50;not null" json:"name"`
+	AvatarURL     string         `gorm:"size:255" json:"avatar_url"`
+	IsKids        bool           `gorm:"default:false" json:"is_kids"`
+	MaturityLimit MaturityRating `gorm:"size:10" json:"maturity_limit"` // kids profiles default to the lowest rating
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for Profile
+func (Profile) TableName() string {
+	return "profiles"
+}
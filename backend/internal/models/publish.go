@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PublishStatus represents the editorial state of content or an episode
+type PublishStatus string
+
+const (
+	// PublishDraft is only visible to staff
+	PublishDraft PublishStatus = "draft"
+	// PublishScheduled goes live automatically at PublishAt
+	PublishScheduled PublishStatus = "scheduled"
+	// PublishPublished is live and listed
+	PublishPublished PublishStatus = "published"
+	// PublishUnlisted is live for anyone with the link but hidden from lists and search
+	PublishUnlisted PublishStatus = "unlisted"
+)
+
+// LiveStatuses are the statuses that non-staff viewers may open
+var LiveStatuses = []PublishStatus{PublishPublished, PublishUnlisted}
+
+// IsValid checks if the publish status is known
+func (s PublishStatus) IsValid() bool {
+	switch s {
+	case PublishDraft, PublishScheduled, PublishPublished, PublishUnlisted:
+		return true
+	}
+	return false
+}
+
+// ResolvePublishState validates a status and publish time and fills in defaults.
+// An empty status means published, which keeps the old "save goes live" behaviour.
+// A scheduled item whose time has already passed is published right away.
+func ResolvePublishState(status PublishStatus, publishAt *time.Time, now time.Time) (PublishStatus, *time.Time, error) {
+	if status == "" {
+		status = PublishPublished
+	}
+	if !status.IsValid() {
+		return "", nil, fmt.Errorf("invalid status: %s", status)
+	}
+
+	switch status {
+	case PublishScheduled:
+		if publishAt == nil {
+			return "", nil, errors.New("publish_at is required for scheduled items")
+		}
+		if !publishAt.After(now) {
+			status = PublishPublished
+		}
+	case PublishPublished, PublishUnlisted:
+		if publishAt == nil {
+			publishAt = &now
+		}
+	}
+
+	return status, publishAt, nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// RevisionEntity is the kind of record a revision was taken of
+type RevisionEntity string
+
+const (
+	RevisionEntityContent RevisionEntity = "content"
+	RevisionEntityEpisode RevisionEntity = "episode"
+)
+
+// RevisionAction is the mutation that produced a revision
+type RevisionAction string
+
+const (
+	RevisionCreate  RevisionAction = "create"
+	RevisionUpdate  RevisionAction = "update"
+	RevisionDelete  RevisionAction = "delete"
+	RevisionRestore RevisionAction = "restore"
+)
+
+// FieldChange describes one field that differs between two snapshots
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ContentRevision is a JSON snapshot of a content or one of its episodes taken
+// after every mutation, together with the fields that changed since the previous
+// one. It is stored in the same transaction as the mutation.
+type ContentRevision struct {
+	ID         uint                   `gorm:"primaryKey" json:"id"`
+	ContentID  uint                   `gorm:"not null;index" json:"content_id"`
+	EntityType RevisionEntity         `gorm:"size:20;not null;index:idx_revision_entity" json:"entity_type"`
+	EntityID   uint                   `gorm:"not null;index:idx_revision_entity" json:"entity_id"`
+	Action     RevisionAction         `gorm:"size:20;not null" json:"action"`
+	UserID     *uint                  `gorm:"index" json:"user_id"` // nil for changes made by the system
+	User       *User                  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Snapshot   map[string]interface{} `gorm:"type:jsonb;serializer:json;not null" json:"snapshot"`
+	Changes    []FieldChange          `gorm:"type:jsonb;serializer:json" json:"changes"`
+	CreatedAt  time.Time              `gorm:"index" json:"created_at"`
+}
@@ -16,6 +16,16 @@ type Season struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Optimistic locking; bumped on every update and exposed as the ETag
+	Version uint `gorm:"not null;default:1" json:"version"`
+
+	// URL identifier generated from the name and year; previous values are kept as redirects
+	Slug string `gorm:"size:255;uniqueIndex" json:"slug"`
+
+	// First and last day of the season; Status follows from them
+	StartDate *time.Time `gorm:"type:date;index" json:"start_date"`
+	EndDate   *time.Time `gorm:"type:date;index" json:"end_date"`
+
 	// Relationships
 	Contents []Content `gorm:"foreignKey:SeasonID" json:"contents,omitempty"`
 }
@@ -24,3 +34,68 @@ type Season struct {
 func (Season) TableName() string {
 	return "seasons"
 }
+
+// Season names, in calendar order
+const (
+	SeasonWinter = "Winter"
+	SeasonSpring = "Spring"
+	SeasonSummer = "Summer"
+	SeasonFall   = "Fall"
+)
+
+// SeasonNames lists the season names in calendar order
+var SeasonNames = []string{SeasonWinter, SeasonSpring, SeasonSummer, SeasonFall}
+
+// Season statuses
+const (
+	SeasonComingSoon = "Coming Soon"
+	SeasonActive     = "Active"
+	SeasonEnded      = "Ended"
+)
+
+// SeasonBounds returns the first and last day of a season in the broadcast
+// calendar: Winter is January to March, Spring April to June, Summer July to
+// September and Fall October to December
+func SeasonBounds(name string, year int) (start, end time.Time, ok bool) {
+	for i, candidate := range SeasonNames {
+		if candidate == name {
+			start = time.Date(year, time.Month(1+3*i), 1, 0, 0, 0, 0, time.UTC)
+			return start, start.AddDate(0, 3, -1), true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// SeasonAt returns the name and year of the calendar season containing t
+func SeasonAt(t time.Time) (name string, year int) {
+	return SeasonNames[(int(t.Month())-1)/3], t.Year()
+}
+
+// NextSeason returns the name and year of the season after the given one
+func NextSeason(name string, year int) (string, int) {
+	for i, candidate := range SeasonNames {
+		if candidate == name {
+			if i == len(SeasonNames)-1 {
+				return SeasonNames[0], year + 1
+			}
+			return SeasonNames[i+1], year
+		}
+	}
+	return name, year
+}
+
+// StatusAt derives the status of the season from its dates; the stored status
+// is kept for seasons without dates
+func (s *Season) StatusAt(t time.Time) string {
+	if s.StartDate == nil || s.EndDate == nil {
+		return s.Status
+	}
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch {
+	case today.Before(*s.StartDate):
+		return SeasonComingSoon
+	case today.After(*s.EndDate):
+		return SeasonEnded
+	}
+	return SeasonActive
+}
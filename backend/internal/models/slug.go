@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// SlugEntity is the kind of record a slug identifies
+type SlugEntity string
+
+const (
+	SlugContent  SlugEntity = "content"
+	SlugGenre    SlugEntity = "genre"
+	SlugCategory SlugEntity = "category"
+	SlugSeason   SlugEntity = "season"
+)
+
+// Table returns the table that holds records of the entity
+func (e SlugEntity) Table() string {
+	switch e {
+	case SlugContent:
+		return "contents"
+	case SlugGenre:
+		return "genres"
+	case SlugCategory:
+		return "categories"
+	case SlugSeason:
+		return "seasons"
+	}
+	return ""
+}
+
+// SlugRedirect remembers a slug a record used before it was renamed so old URLs keep working
+type SlugRedirect struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	EntityType SlugEntity `gorm:"size:20;not null;uniqueIndex:idx_slug_redirect" json:"entity_type"`
+	Slug       string     `gorm:"size:255;not null;uniqueIndex:idx_slug_redirect" json:"slug"`
+	EntityID   uint       `gorm:"not null;index" json:"entity_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Studio represents an animation or production studio
+type Studio struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"size:255;not null;unique" json:"name"`
+	Description string         `gorm:"type:text" json:"description"`
+	Website     string         `gorm:"size:255" json:"website"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for Studio
+func (Studio) TableName() string {
+	return "studios"
+}
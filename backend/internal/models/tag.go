@@ -0,0 +1,132 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// TagCategory groups tags by what they describe
+type TagCategory string
+
+const (
+	// TagCategoryTheme covers recurring themes, e.g. "found family"
+	TagCategoryTheme TagCategory = "theme"
+	// TagCategoryPlot covers plot devices, e.g. "time loop"
+	TagCategoryPlot TagCategory = "plot"
+	// TagCategorySetting covers where and when the story happens, e.g. "isekai"
+	TagCategorySetting TagCategory = "setting"
+	// TagCategoryCharacter covers character traits, e.g. "female protagonist"
+	TagCategoryCharacter TagCategory = "character"
+	// TagCategoryDemographic covers the intended audience, e.g. "seinen"
+	TagCategoryDemographic TagCategory = "demographic"
+	// TagCategoryOther is for everything else
+	TagCategoryOther TagCategory = "other"
+)
+
+// IsValid checks if the tag category is known
+func (c TagCategory) IsValid() bool {
+	switch c {
+	case TagCategoryTheme, TagCategoryPlot, TagCategorySetting, TagCategoryCharacter, TagCategoryDemographic, TagCategoryOther:
+		return true
+	}
+	return false
+}
+
+// Default and bounds of the relevance of a tag to a title
+const (
+	TagWeightMin     = 1
+	TagWeightMax     = 100
+	TagWeightDefault = 50
+)
+
+// Tag is a free-form theme that is finer than the curated genres
+type Tag struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	Name        string      `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	Category    TagCategory `gorm:"size:20;not null;default:'other';index" json:"category"`
+	Description string      `gorm:"type:text" json:"description"`
+	IsSpoiler   bool        `gorm:"not null;default:false" json:"is_spoiler"` // the tag gives the plot away wherever it is used
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// TableName specifies the table name for Tag
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// NormalizeTagName trims a tag name and collapses inner whitespace
+func NormalizeTagName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// ContentTag links a tag to a title with a weight telling how central it is
+type ContentTag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ContentID uint      `gorm:"not null;uniqueIndex:idx_content_tag" json:"content_id"`
+	TagID     uint      `gorm:"not null;uniqueIndex:idx_content_tag;index" json:"tag_id"`
+	Weight    int       `gorm:"not null;default:50" json:"weight"`
+	IsSpoiler bool      `gorm:"not null;default:false" json:"is_spoiler"` // spoiler for this title even if the tag is not
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Tag *Tag `gorm:"foreignKey:TagID" json:"tag,omitempty"`
+}
+
+// TableName specifies the table name for ContentTag
+func (ContentTag) TableName() string {
+	return "content_tags"
+}
+
+// TagSuggestionStatus represents where a suggestion is in moderation
+type TagSuggestionStatus string
+
+const (
+	// TagSuggestionPending waits for a moderator
+	TagSuggestionPending TagSuggestionStatus = "pending"
+	// TagSuggestionApproved has been applied to the title
+	TagSuggestionApproved TagSuggestionStatus = "approved"
+	// TagSuggestionRejected has been turned down
+	TagSuggestionRejected TagSuggestionStatus = "rejected"
+)
+
+// TagSuggestion is a tag a user proposed for a title. It names either an
+// existing tag or a new one that is created when the suggestion is approved.
+type TagSuggestion struct {
+	ID           uint                `gorm:"primaryKey" json:"id"`
+	ContentID    uint                `gorm:"not null;index" json:"content_id"`
+	TagID        *uint               `gorm:"index" json:"tag_id"`
+	Name         string              `gorm:"size:100;not null" json:"name"`
+	Category     TagCategory         `gorm:"size:20;not null;default:'other'" json:"category"`
+	IsSpoiler    bool                `gorm:"not null;default:false" json:"is_spoiler"`
+	Reason       string              `gorm:"type:text" json:"reason"`
+	UserID       uint                `gorm:"not null;index" json:"user_id"`
+	Status       TagSuggestionStatus `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	ReviewedByID *uint               `json:"reviewed_by_id"`
+	ReviewedAt   *time.Time          `json:"reviewed_at"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+
+	// Relationships
+	Content *Content `gorm:"foreignKey:ContentID" json:"content,omitempty"`
+	Tag     *Tag     `gorm:"foreignKey:TagID" json:"tag,omitempty"`
+	User    *User    `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for TagSuggestion
+func (TagSuggestion) TableName() string {
+	return "tag_suggestions"
+}
+
+// TagFilter narrows content lists by tag name: a title must carry every
+// included tag and none of the excluded ones
+type TagFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// IsEmpty reports whether the filter does not restrict anything
+func (f TagFilter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
@@ -18,18 +18,31 @@ const (
 
 // User represents a user in the system
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Username  string         `gorm:"size:100;not null;unique" json:"username"`
-	Email     string         `gorm:"size:100;not null;unique" json:"email"`
-	Password  string         `gorm:"size:255;not null" json:"-"` // Password is not included in JSON responses
-	Role      Role           `gorm:"size:20;not null;default:'user'" json:"role"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	LastLogin *time.Time     `json:"last_login"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	Username         string         `gorm:"size:100;not null;unique" json:"username"`
+	Email            string         `gorm:"size:100;not null;unique" json:"email"`
+	Password         string         `gorm:"size:255;not null" json:"-"` // Password is not included in JSON responses
+	Role             Role           `gorm:"size:20;not null;default:'user'" json:"role"`
+	IsServiceAccount bool           `gorm:"default:false" json:"is_service_account"` // API-key only, no password login
+	MaxMaturity      MaturityRating `gorm:"size:10" json:"max_maturity"`             // empty means unrestricted
+	ParentalPIN      string         `gorm:"size:255" json:"-"`                       // bcrypt hash
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	LastLogin        *time.Time     `json:"last_login"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Set when the user asked to delete the account; it is purged once this time passes
+	DeletionScheduledAt *time.Time `gorm:"index" json:"deletion_scheduled_at,omitempty"`
+
+	// Secret part of the private calendar feed URL; empty until the feed is first requested
+	CalendarToken string `gorm:"size:64;index" json:"-"`
+
+	// Wrong parental PINs in a row; too many lock the PIN until ParentalPINLockedUntil
+	ParentalPINFailures    int        `gorm:"not null;default:0" json:"-"`
+	ParentalPINLockedUntil *time.Time `json:"-"`
 }
 
 // TableName specifies the table name for User
 func (User) TableName() string {
 	return "users"
-} 
\ No newline at end of file
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserIdentity links a user to an account at an external OpenID Connect provider
+type UserIdentity struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"`
+	Provider  string         `gorm:"size:50;not null;uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject   string         `gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject" json:"subject"`
+	Email     string         `gorm:"size:100" json:"email"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for UserIdentity
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -0,0 +1,7 @@
+package models
+
+import "errors"
+
+// ErrVersionConflict is returned when a record was changed by someone else
+// between reading it and writing it back
+var ErrVersionConflict = errors.New("the record was modified by someone else, reload it and try again")
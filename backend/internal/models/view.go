@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+)
+
+// ViewSource tells which playback signal produced a view
+type ViewSource string
+
+const (
+	// ViewSourceStream is recorded when a video starts streaming
+	ViewSourceStream ViewSource = "stream"
+	// ViewSourceProgress is recorded when a player reports watch progress
+	ViewSourceProgress ViewSource = "progress"
+)
+
+// ViewEvent is a deduplicated view: one per viewer, title, episode and time window.
+// Events are rolled up into hourly and daily counters and pruned afterwards.
+type ViewEvent struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	ContentID uint       `gorm:"not null;uniqueIndex:idx_view_event_dedup" json:"content_id"`
+	EpisodeID uint       `gorm:"not null;default:0;uniqueIndex:idx_view_event_dedup" json:"episode_id"` // 0 for the title itself
+	ViewerKey string     `gorm:"size:64;not null;uniqueIndex:idx_view_event_dedup" json:"-"`            // user ID or a hash of IP and user agent
+	Bucket    time.Time  `gorm:"not null;uniqueIndex:idx_view_event_dedup" json:"bucket"`               // start of the dedup window
+	Source    ViewSource `gorm:"size:20;not null" json:"source"`
+	RolledUp  bool       `gorm:"not null;default:false;index" json:"rolled_up"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+}
+
+// TableName specifies the table name for ViewEvent
+func (ViewEvent) TableName() string {
+	return "view_events"
+}
+
+// ContentViewHourly counts the views of a title per hour
+type ContentViewHourly struct {
+	ContentID uint      `gorm:"primaryKey;autoIncrement:false" json:"content_id"`
+	Hour      time.Time `gorm:"primaryKey;index" json:"hour"`
+	Views     int64     `gorm:"not null;default:0" json:"views"`
+}
+
+// TableName specifies the table name for ContentViewHourly
+func (ContentViewHourly) TableName() string {
+	return "content_views_hourly"
+}
+
+// ContentViewDaily counts the views of a title per day
+type ContentViewDaily struct {
+	ContentID uint      `gorm:"primaryKey;autoIncrement:false" json:"content_id"`
+	Day       time.Time `gorm:"primaryKey;type:date;index" json:"day"`
+	Views     int64     `gorm:"not null;default:0" json:"views"`
+}
+
+// TableName specifies the table name for ContentViewDaily
+func (ContentViewDaily) TableName() string {
+	return "content_views_daily"
+}
+
+// TrendingWindow is the period trending rankings are computed over
+type TrendingWindow string
+
+const (
+	// TrendingDay ranks by the views of the last 24 hours
+	TrendingDay TrendingWindow = "24h"
+	// TrendingWeek ranks by the views of the last 7 days
+	TrendingWeek TrendingWindow = "7d"
+	// TrendingMonth ranks by the views of the last 30 days
+	TrendingMonth TrendingWindow = "30d"
+)
+
+// Duration returns the length of the window, or 0 if the window is unknown
+func (w TrendingWindow) Duration() time.Duration {
+	switch w {
+	case TrendingDay:
+		return 24 * time.Hour
+	case TrendingWeek:
+		return 7 * 24 * time.Hour
+	case TrendingMonth:
+		return 30 * 24 * time.Hour
+	}
+	return 0
+}
+
+// TrendingContent is a title with the number of views it got in a window
+type TrendingContent struct {
+	Content Content `json:"content"`
+	Views   int64   `json:"views"`
+}
+
+// ContentSort tells how content lists are ordered
+type ContentSort string
+
+const (
+	// ContentSortDefault keeps the database order
+	ContentSortDefault ContentSort = ""
+	// ContentSortPopularity puts the most viewed titles of the last week first
+	ContentSortPopularity ContentSort = "popularity"
+	// ContentSortRating puts the best rated titles first
+	ContentSortRating ContentSort = "rating"
+	// ContentSortNewest puts the titles added last first
+	ContentSortNewest ContentSort = "newest"
+)
+
+// IsValid checks if the sort is known
+func (s ContentSort) IsValid() bool {
+	switch s {
+	case ContentSortDefault, ContentSortPopularity, ContentSortRating, ContentSortNewest:
+		return true
+	}
+	return false
+}
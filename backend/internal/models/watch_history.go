@@ -10,6 +10,7 @@ import (
 type WatchHistory struct {
 	ID             uint           `gorm:"primaryKey" json:"id"`
 	UserID         uint           `gorm:"not null" json:"user_id"`
+	ProfileID      *uint          `gorm:"index" json:"profile_id"`
 	ContentID      uint           `gorm:"not null" json:"content_id"`
 	EpisodeID      *uint          `json:"episode_id"`
 	WatchProgress  int            `gorm:"default:0" json:"watch_progress"` // in seconds
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository handles database operations for API keys
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create creates a new API key
+func (r *APIKeyRepository) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindByID finds an API key by ID
+func (r *APIKeyRepository) FindByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByPrefix finds an API key by its public prefix, preloading the owner
+func (r *APIKeyRepository) FindByPrefix(prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.Preload("User").Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListByUserID lists all API keys owned by a user
+func (r *APIKeyRepository) ListByUserID(userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// Revoke marks an API key as revoked
+func (r *APIKeyRepository) Revoke(id uint) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllByUserID revokes every active API key of a user
+func (r *APIKeyRepository) RevokeAllByUserID(userID uint) error {
+	return r.db.Model(&models.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// TouchLastUsed records when an API key was last used
+func (r *APIKeyRepository) TouchLastUsed(id uint, usedAt time.Time) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt).Error
+}
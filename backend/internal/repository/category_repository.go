@@ -15,15 +15,30 @@ func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
+// categoryTree returns the ID of a category followed by the IDs of all its
+// subcategories, however deep. Empty if the category does not exist.
+func categoryTree(db *gorm.DB, id uint) ([]uint, error) {
+	var ids []uint
+	err := db.Raw(`WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id WHERE c.deleted_at IS NULL
+		) SELECT id FROM tree`, id).Scan(&ids).Error
+	return ids, err
+}
+
 // Create creates a new category
 func (r *CategoryRepository) Create(category *models.Category) error {
 	return r.db.Create(category).Error
 }
 
-// FindByID finds a category by ID
+// FindByID finds a category by ID with its direct subcategories
 func (r *CategoryRepository) FindByID(id uint) (*models.Category, error) {
 	var category models.Category
-	if err := r.db.First(&category, id).Error; err != nil {
+	err := r.db.Preload("Children", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, name")
+	}).First(&category, id).Error
+	if err != nil {
 		return nil, err
 	}
 	return &category, nil
@@ -40,21 +55,52 @@ func (r *CategoryRepository) FindByName(name string) (*models.Category, error) {
 
 // Update updates a category
 func (r *CategoryRepository) Update(category *models.Category) error {
-	return r.db.Save(category).Error
+	return updateVersioned(r.db, category, &category.Version)
 }
 
-// Delete deletes a category
+// Delete deletes a category. Its subcategories move up to its parent.
 func (r *CategoryRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Category{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var category models.Category
+		if err := tx.First(&category, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", id).
+			Updates(map[string]interface{}{
+				"parent_id": category.ParentID,
+				"version":   gorm.Expr("version + 1"),
+			}).Error; err != nil {
+			return err
+		}
+		// Titles only listed under the category lose the listing, trashed ones included
+		if err := tx.Exec("DELETE FROM content_categories WHERE category_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Category{}, id).Error
+	})
 }
 
-// List lists all categories
+// List lists all categories in display order. Nested categories carry their
+// parent_id so clients can build the tree.
 func (r *CategoryRepository) List() ([]models.Category, error) {
 	var categories []models.Category
-	err := r.db.Order("name").Find(&categories).Error
+	err := r.db.Order("position, name").Find(&categories).Error
 	return categories, err
 }
 
+// DescendantIDs returns the ID of a category followed by the IDs of all its subcategories
+func (r *CategoryRepository) DescendantIDs(id uint) ([]uint, error) {
+	return categoryTree(r.db, id)
+}
+
+// CountContents counts the titles whose primary category is the given one,
+// including those in the trash, which get it back when they are restored
+func (r *CategoryRepository) CountContents(id uint) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Content{}).Where("category_id = ?", id).Count(&count).Error
+	return count, err
+}
+
 // AddContentToCategory adds content to a category
 func (r *CategoryRepository) AddContentToCategory(categoryID, contentID uint) error {
 	return r.db.Exec(
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// CharacterRepository handles database operations for characters
+type CharacterRepository struct {
+	db *gorm.DB
+}
+
+// NewCharacterRepository creates a new CharacterRepository
+func NewCharacterRepository(db *gorm.DB) *CharacterRepository {
+	return &CharacterRepository{db: db}
+}
+
+// Create creates a new character
+func (r *CharacterRepository) Create(character *models.Character) error {
+	return r.db.Create(character).Error
+}
+
+// FindByID finds a character by ID
+func (r *CharacterRepository) FindByID(id uint) (*models.Character, error) {
+	var character models.Character
+	if err := r.db.First(&character, id).Error; err != nil {
+		return nil, err
+	}
+	return &character, nil
+}
+
+// Update updates a character
+func (r *CharacterRepository) Update(character *models.Character) error {
+	return r.db.Save(character).Error
+}
+
+// List lists characters with pagination, optionally filtered by name
+func (r *CharacterRepository) List(term string, page, pageSize int) ([]models.Character, int64, error) {
+	var characters []models.Character
+	var count int64
+	query := r.db.Model(&models.Character{})
+	if term != "" {
+		query = query.Where("name ILIKE ?", "%"+term+"%")
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("name").Offset(offset).Limit(pageSize).Find(&characters).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return characters, count, nil
+}
+
+// Delete deletes a character and removes it from every cast
+func (r *CharacterRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("character_id = ?", id).Delete(&models.ContentCharacter{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Character{}, id).Error
+	})
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// CollectionRepository handles database operations for curated collections and the homepage layout
+type CollectionRepository struct {
+	db *gorm.DB
+}
+
+// NewCollectionRepository creates a new CollectionRepository
+func NewCollectionRepository(db *gorm.DB) *CollectionRepository {
+	return &CollectionRepository{db: db}
+}
+
+// orderedItems preloads the items of a collection in their position order
+func orderedItems(db *gorm.DB) *gorm.DB {
+	return db.Order("collection_items.position, collection_items.id")
+}
+
+// Create creates a new collection
+func (r *CollectionRepository) Create(collection *models.Collection) error {
+	return r.db.Omit("Items").Create(collection).Error
+}
+
+// FindByID finds a collection by ID with its items and their titles
+func (r *CollectionRepository) FindByID(id uint) (*models.Collection, error) {
+	var collection models.Collection
+	if err := r.db.Preload("Items", orderedItems).Preload("Items.Content").First(&collection, id).Error; err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// List lists all collections, newest first
+func (r *CollectionRepository) List() ([]models.Collection, error) {
+	var collections []models.Collection
+	err := r.db.Order("created_at DESC").Find(&collections).Error
+	return collections, err
+}
+
+// Update updates the fields of a collection; items are replaced separately
+func (r *CollectionRepository) Update(collection *models.Collection) error {
+	return r.db.Omit("Items").Save(collection).Error
+}
+
+// Delete deletes a collection together with its items and the rails showing it
+func (r *CollectionRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id = ?", id).Delete(&models.HomeRail{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Collection{}, id).Error
+	})
+}
+
+// ReplaceItems replaces the items of a collection with the given titles in order
+func (r *CollectionRepository) ReplaceItems(collectionID uint, contentIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", collectionID).Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		if len(contentIDs) == 0 {
+			return nil
+		}
+		items := make([]models.CollectionItem, 0, len(contentIDs))
+		for i, contentID := range contentIDs {
+			items = append(items, models.CollectionItem{
+				CollectionID: collectionID,
+				ContentID:    contentID,
+				Position:     i,
+			})
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+// ListContents lists the titles of a collection the viewer may see, in collection order
+func (r *CollectionRepository) ListContents(viewer *models.Viewer, collectionID uint, limit int, preload ...string) ([]models.Content, error) {
+	var contents []models.Content
+	query := r.db.Model(&models.Content{}).
+		Joins("JOIN collection_items ON collection_items.content_id = contents.id").
+		Where("collection_items.collection_id = ?", collectionID).
+		Scopes(listedTo(viewer))
+	query = preloadFor(viewer, query, preload)
+	err := query.Order("collection_items.position, collection_items.id").Limit(limit).Find(&contents).Error
+	return contents, err
+}
+
+// ListRails lists the rails of the homepage layout in order
+func (r *CollectionRepository) ListRails() ([]models.HomeRail, error) {
+	var rails []models.HomeRail
+	err := r.db.Preload("Collection").Preload("Genre").Order("position, id").Find(&rails).Error
+	return rails, err
+}
+
+// ReplaceRails replaces the whole homepage layout
+func (r *CollectionRepository) ReplaceRails(rails []models.HomeRail) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.HomeRail{}).Error; err != nil {
+			return err
+		}
+		if len(rails) == 0 {
+			return nil
+		}
+		return tx.Omit("Collection", "Genre").Create(&rails).Error
+	})
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// ContentCreditRepository handles database operations for studio, staff and cast credits
+type ContentCreditRepository struct {
+	db *gorm.DB
+}
+
+// NewContentCreditRepository creates a new ContentCreditRepository
+func NewContentCreditRepository(db *gorm.DB) *ContentCreditRepository {
+	return &ContentCreditRepository{db: db}
+}
+
+// FindByContentID loads every credit of a content
+func (r *ContentCreditRepository) FindByContentID(contentID uint) (*models.ContentCredits, error) {
+	credits := &models.ContentCredits{}
+
+	if err := r.db.Preload("Studio").Where("content_id = ?", contentID).
+		Order("role, id").Find(&credits.Studios).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Preload("Person").Where("content_id = ?", contentID).
+		Order("role, id").Find(&credits.Staff).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Preload("Character").Preload("Person").Where("content_id = ?", contentID).
+		Order("role, character_id, language").Find(&credits.Characters).Error; err != nil {
+		return nil, err
+	}
+
+	return credits, nil
+}
+
+// Replace replaces every credit of a content
+func (r *ContentCreditRepository) Replace(contentID uint, credits *models.ContentCredits) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&models.ContentStudio{}, &models.ContentStaff{}, &models.ContentCharacter{}} {
+			if err := tx.Where("content_id = ?", contentID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range credits.Studios {
+			credits.Studios[i].ID = 0
+			credits.Studios[i].ContentID = contentID
+		}
+		for i := range credits.Staff {
+			credits.Staff[i].ID = 0
+			credits.Staff[i].ContentID = contentID
+		}
+		for i := range credits.Characters {
+			credits.Characters[i].ID = 0
+			credits.Characters[i].ContentID = contentID
+		}
+
+		if len(credits.Studios) > 0 {
+			if err := tx.Create(&credits.Studios).Error; err != nil {
+				return err
+			}
+		}
+		if len(credits.Staff) > 0 {
+			if err := tx.Create(&credits.Staff).Error; err != nil {
+				return err
+			}
+		}
+		if len(credits.Characters) > 0 {
+			if err := tx.Create(&credits.Characters).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
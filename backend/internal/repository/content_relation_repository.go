@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// ContentRelationRepository handles database operations for franchise relations
+type ContentRelationRepository struct {
+	db *gorm.DB
+}
+
+// NewContentRelationRepository creates a new ContentRelationRepository
+func NewContentRelationRepository(db *gorm.DB) *ContentRelationRepository {
+	return &ContentRelationRepository{db: db}
+}
+
+// CreatePair stores a relation together with its inverse
+func (r *ContentRelationRepository) CreatePair(relation, inverse *models.ContentRelation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(relation).Error; err != nil {
+			return err
+		}
+		return tx.Create(inverse).Error
+	})
+}
+
+// FindByID finds a relation by ID
+func (r *ContentRelationRepository) FindByID(id uint) (*models.ContentRelation, error) {
+	var relation models.ContentRelation
+	if err := r.db.First(&relation, id).Error; err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// DeletePair deletes the relation between two titles in both directions
+func (r *ContentRelationRepository) DeletePair(contentID, relatedContentID uint) error {
+	return r.db.
+		Where("(content_id = ? AND related_content_id = ?) OR (content_id = ? AND related_content_id = ?)",
+			contentID, relatedContentID, relatedContentID, contentID).
+		Delete(&models.ContentRelation{}).Error
+}
+
+// DeleteByContentID deletes every relation that touches a title
+func (r *ContentRelationRepository) DeleteByContentID(contentID uint) error {
+	return r.db.
+		Where("content_id = ? OR related_content_id = ?", contentID, contentID).
+		Delete(&models.ContentRelation{}).Error
+}
+
+// ListByContentID lists the relations of a title whose related title the viewer may see
+func (r *ContentRelationRepository) ListByContentID(viewer *models.Viewer, contentID uint) ([]models.ContentRelation, error) {
+	var relations []models.ContentRelation
+	err := r.db.
+		Joins("JOIN contents ON contents.id = content_relations.related_content_id AND contents.deleted_at IS NULL").
+		Scopes(visibleTo(viewer)).
+		Where("content_relations.content_id = ?", contentID).
+		Preload("RelatedContent").
+		Order("contents.release_date, contents.id").
+		Find(&relations).Error
+	return relations, err
+}
+
+// ListByContentIDs lists the outgoing edges of several titles, used to walk a franchise
+func (r *ContentRelationRepository) ListByContentIDs(contentIDs []uint) ([]models.ContentRelation, error) {
+	var relations []models.ContentRelation
+	err := r.db.Where("content_id IN ?", contentIDs).Find(&relations).Error
+	return relations, err
+}
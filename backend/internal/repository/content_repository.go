@@ -2,9 +2,11 @@ package repository
 
 import (
 	"log"
+	"time"
 
 	"github.com/username/anime-streaming/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ContentRepository handles database operations for content (movies/series)
@@ -37,28 +39,124 @@ func (r *ContentRepository) FindByID(id uint, preload ...string) (*models.Conten
 	return &content, nil
 }
 
+// FindWithDeleted finds content by ID, including soft-deleted content
+func (r *ContentRepository) FindWithDeleted(id uint, preload ...string) (*models.Content, error) {
+	var content models.Content
+	query := r.db.Unscoped()
+
+	for _, relation := range preload {
+		query = query.Preload(relation)
+	}
+
+	if err := query.First(&content, id).Error; err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+// FindVisibleByID finds content by ID if the viewer is allowed to see it
+func (r *ContentRepository) FindVisibleByID(viewer *models.Viewer, id uint, preload ...string) (*models.Content, error) {
+	var content models.Content
+	query := preloadFor(viewer, r.db.Scopes(visibleTo(viewer)), preload)
+
+	if err := query.First(&content, id).Error; err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+// FindVisibleByIDs finds the titles with the given IDs that the viewer is allowed to see
+func (r *ContentRepository) FindVisibleByIDs(viewer *models.Viewer, ids []uint) ([]models.Content, error) {
+	var contents []models.Content
+	err := r.db.Scopes(visibleTo(viewer)).Where("id IN ?", ids).Find(&contents).Error
+	return contents, err
+}
+
 // Update updates content
 func (r *ContentRepository) Update(content *models.Content) error {
-	return r.db.Save(content).Error
+	return updateVersioned(r.db, content, &content.Version)
+}
+
+// NumberEpisodes fills in the absolute numbers missing on the regular episodes of a content
+func (r *ContentRepository) NumberEpisodes(contentID uint) error {
+	return numberEpisodes(r.db, contentID)
 }
 
-// Delete deletes content
+// Delete soft-deletes content together with its episodes and links. All rows
+// share one deletion time so restoring the content brings back exactly these children.
 func (r *ContentRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Content{}, id).Error
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		children := []interface{}{&models.Episode{}, &models.StreamLink{}, &models.DownloadLink{}}
+		for _, model := range children {
+			if err := tx.Model(model).Where("content_id = ?", id).Update("deleted_at", now).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Content{}).Where("id = ?", id).Update("deleted_at", now).Error
+	})
+}
+
+// PublishDue publishes every scheduled content whose publish time has passed
+// and returns the IDs of the contents it published
+func (r *ContentRepository) PublishDue(now time.Time) ([]uint, error) {
+	return publishDue(r.db, &models.Content{}, now)
 }
 
-// List lists all content with pagination and optional filtering
-func (r *ContentRepository) List(page, pageSize int, filters map[string]interface{}, preload ...string) ([]models.Content, int64, error) {
+// publishDue flips the due scheduled rows of a model to published. The rows
+// are locked while they are picked so that each one is published once.
+func publishDue(db *gorm.DB, model interface{}, now time.Time) ([]uint, error) {
+	var ids []uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(model).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status = ? AND publish_at <= ?", models.PublishScheduled, now).
+			Order("id").
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		return tx.Model(model).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":  models.PublishPublished,
+				"version": gorm.Expr("version + 1"),
+			}).Error
+	})
+	return ids, err
+}
+
+// List lists all content with pagination, optional filtering and sorting
+func (r *ContentRepository) List(viewer *models.Viewer, page, pageSize int, filters map[string]interface{}, sort models.ContentSort, preload ...string) ([]models.Content, int64, error) {
 	var contents []models.Content
 	var count int64
-	query := r.db.Model(&models.Content{})
+	query := r.db.Model(&models.Content{}).Scopes(listedTo(viewer))
 
 	// Apply filters
 	if filters != nil {
 		for key, value := range filters {
 			// Log the filter being applied
 			log.Printf("Applying filter: %s = %v", key, value)
-			query = query.Where(key+" = ?", value)
+			switch key {
+			case "studio_id":
+				studios := r.db.Model(&models.ContentStudio{}).Select("content_id").Where("studio_id = ?", value)
+				query = query.Where("contents.id IN (?)", studios)
+			case "type":
+				// The old free-form type is now the name of the category
+				categories := r.db.Model(&models.Category{}).Select("id").Where("name = ?", value)
+				query = query.Where("contents.category_id IN (?)", categories)
+			case "tags":
+				if filter, ok := value.(models.TagFilter); ok {
+					query = query.Scopes(taggedWith(filter))
+				}
+			case "person_id":
+				// Matches staff credits as well as voice roles
+				staff := r.db.Model(&models.ContentStaff{}).Select("content_id").Where("person_id = ?", value)
+				cast := r.db.Model(&models.ContentCharacter{}).Select("content_id").Where("person_id = ?", value)
+				query = query.Where("contents.id IN (?) OR contents.id IN (?)", staff, cast)
+			default:
+				query = query.Where(key+" = ?", value)
+			}
 		}
 	}
 
@@ -67,11 +165,19 @@ func (r *ContentRepository) List(page, pageSize int, filters map[string]interfac
 		return nil, 0, err
 	}
 
-	// Apply preloading
-	for _, relation := range preload {
-		query = query.Preload(relation)
+	// Apply sorting
+	switch sort {
+	case models.ContentSortPopularity:
+		query = query.Scopes(byPopularity(time.Now().Add(-popularityWindow)))
+	case models.ContentSortRating:
+		query = query.Order("contents.rating DESC, contents.id DESC")
+	case models.ContentSortNewest:
+		query = query.Order("contents.created_at DESC, contents.id DESC")
 	}
 
+	// Apply preloading
+	query = preloadFor(viewer, query, preload)
+
 	// Apply pagination
 	offset := (page - 1) * pageSize
 	if err := query.Offset(offset).Limit(pageSize).Find(&contents).Error; err != nil {
@@ -82,10 +188,12 @@ func (r *ContentRepository) List(page, pageSize int, filters map[string]interfac
 }
 
 // Search searches content by title
-func (r *ContentRepository) Search(term string, page, pageSize int, preload ...string) ([]models.Content, int64, error) {
+func (r *ContentRepository) Search(viewer *models.Viewer, term string, tags models.TagFilter, page, pageSize int, preload ...string) ([]models.Content, int64, error) {
 	var contents []models.Content
 	var count int64
-	query := r.db.Model(&models.Content{}).Where("title ILIKE ?", "%"+term+"%")
+	aliases := r.db.Model(&models.ContentTitle{}).Select("content_id").Where("title ILIKE ?", "%"+term+"%")
+	query := r.db.Model(&models.Content{}).Scopes(listedTo(viewer), taggedWith(tags)).
+		Where("contents.title ILIKE ? OR contents.id IN (?)", "%"+term+"%", aliases)
 
 	// Count total items
 	if err := query.Count(&count).Error; err != nil {
@@ -93,9 +201,7 @@ func (r *ContentRepository) Search(term string, page, pageSize int, preload ...s
 	}
 
 	// Apply preloading
-	for _, relation := range preload {
-		query = query.Preload(relation)
-	}
+	query = preloadFor(viewer, query, preload)
 
 	// Apply pagination
 	offset := (page - 1) * pageSize
@@ -106,13 +212,44 @@ func (r *ContentRepository) Search(term string, page, pageSize int, preload ...s
 	return contents, count, nil
 }
 
+// ListTopRated lists the best rated titles, optionally within a genre or season
+func (r *ContentRepository) ListTopRated(viewer *models.Viewer, genreID, seasonID *uint, limit int, preload ...string) ([]models.Content, error) {
+	var contents []models.Content
+	query := r.db.Model(&models.Content{}).Scopes(listedTo(viewer))
+	if genreID != nil {
+		query = query.Where("contents.id IN (?)", r.db.Table("content_genres").Where("genre_id = ?", *genreID).Select("content_id"))
+	}
+	if seasonID != nil {
+		query = query.Where("contents.season_id = ?", *seasonID)
+	}
+	query = preloadFor(viewer, query, preload)
+	err := query.Order("contents.rating DESC, contents.id DESC").Limit(limit).Find(&contents).Error
+	return contents, err
+}
+
+// ListBySeason lists the titles of a season the viewer may see, in broadcast order
+func (r *ContentRepository) ListBySeason(viewer *models.Viewer, seasonID uint, preload ...string) ([]models.Content, error) {
+	var contents []models.Content
+	query := preloadFor(viewer, r.db.Model(&models.Content{}).Scopes(listedTo(viewer)).Where("contents.season_id = ?", seasonID), preload)
+	err := query.Order("contents.broadcast_time, contents.title").Find(&contents).Error
+	return contents, err
+}
+
+// ListRecentlyAdded lists the titles added to the catalog last
+func (r *ContentRepository) ListRecentlyAdded(viewer *models.Viewer, limit int, preload ...string) ([]models.Content, error) {
+	var contents []models.Content
+	query := preloadFor(viewer, r.db.Model(&models.Content{}).Scopes(listedTo(viewer)), preload)
+	err := query.Order("contents.created_at DESC, contents.id DESC").Limit(limit).Find(&contents).Error
+	return contents, err
+}
+
 // FindByGenre finds content by genre
-func (r *ContentRepository) FindByGenre(genreID uint, page, pageSize int, preload ...string) ([]models.Content, int64, error) {
+func (r *ContentRepository) FindByGenre(viewer *models.Viewer, genreID uint, page, pageSize int, preload ...string) ([]models.Content, int64, error) {
 	var contents []models.Content
 	var count int64
 
-	subQuery := r.db.Table("content_genres").Where("genre_id = ?", genreID).Select("id")
-	query := r.db.Model(&models.Content{}).Where("id IN (?)", subQuery)
+	subQuery := r.db.Table("content_genres").Where("genre_id = ?", genreID).Select("content_id")
+	query := r.db.Model(&models.Content{}).Scopes(listedTo(viewer)).Where("id IN (?)", subQuery)
 
 	// Count total items
 	if err := query.Count(&count).Error; err != nil {
@@ -120,9 +257,7 @@ func (r *ContentRepository) FindByGenre(genreID uint, page, pageSize int, preloa
 	}
 
 	// Apply preloading
-	for _, relation := range preload {
-		query = query.Preload(relation)
-	}
+	query = preloadFor(viewer, query, preload)
 
 	// Apply pagination
 	offset := (page - 1) * pageSize
@@ -134,13 +269,19 @@ func (r *ContentRepository) FindByGenre(genreID uint, page, pageSize int, preloa
 }
 
 // FindByCategory finds content by category
-func (r *ContentRepository) FindByCategory(categoryID uint, page, pageSize int, preload ...string) ([]models.Content, int64, error) {
+func (r *ContentRepository) FindByCategory(viewer *models.Viewer, categoryID uint, page, pageSize int, preload ...string) ([]models.Content, int64, error) {
 	var contents []models.Content
 	var count int64
 
-	log.Printf("Check Category in findByCategory repo: %s", categoryID)
-	subQuery := r.db.Table("categories").Where("id = ?", categoryID).Select("name")
-	query := r.db.Model(&models.Content{}).Where("type IN (?)", subQuery)
+	// Titles of subcategories are listed too, as are titles filed under the
+	// category as a secondary one
+	categoryIDs, err := categoryTree(r.db, categoryID)
+	if err != nil {
+		return nil, 0, err
+	}
+	secondary := r.db.Table("content_categories").Select("content_id").Where("category_id IN ?", categoryIDs)
+	query := r.db.Model(&models.Content{}).Scopes(listedTo(viewer)).
+		Where("contents.category_id IN ? OR contents.id IN (?)", categoryIDs, secondary)
 
 	// Count total items
 	if err := query.Count(&count).Error; err != nil {
@@ -148,9 +289,7 @@ func (r *ContentRepository) FindByCategory(categoryID uint, page, pageSize int,
 	}
 
 	// Apply preloading
-	for _, relation := range preload {
-		query = query.Preload(relation)
-	}
+	query = preloadFor(viewer, query, preload)
 
 	// Apply pagination
 	offset := (page - 1) * pageSize
@@ -161,6 +300,168 @@ func (r *ContentRepository) FindByCategory(categoryID uint, page, pageSize int,
 	return contents, count, nil
 }
 
+// FindByTitleOrAlias finds content whose main title or any alternative title matches exactly
+func (r *ContentRepository) FindByTitleOrAlias(title string) (*models.Content, error) {
+	var content models.Content
+	aliases := r.db.Model(&models.ContentTitle{}).Select("content_id").Where("title = ?", title)
+	if err := r.db.Where("title = ? OR id IN (?)", title, aliases).Order("id").First(&content).Error; err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+// ReplaceTitles replaces all alternative titles of a content
+func (r *ContentRepository) ReplaceTitles(contentID uint, titles []models.ContentTitle) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("content_id = ?", contentID).Delete(&models.ContentTitle{}).Error; err != nil {
+			return err
+		}
+		if len(titles) == 0 {
+			return nil
+		}
+		for i := range titles {
+			titles[i].ID = 0
+			titles[i].ContentID = contentID
+		}
+		return tx.Create(&titles).Error
+	})
+}
+
+// ReplaceDescriptions replaces all localized descriptions of a content
+func (r *ContentRepository) ReplaceDescriptions(contentID uint, descriptions []models.ContentDescription) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("content_id = ?", contentID).Delete(&models.ContentDescription{}).Error; err != nil {
+			return err
+		}
+		if len(descriptions) == 0 {
+			return nil
+		}
+		for i := range descriptions {
+			descriptions[i].ID = 0
+			descriptions[i].ContentID = contentID
+		}
+		return tx.Create(&descriptions).Error
+	})
+}
+
+// ReplaceGenres replaces the genres of a content
+func (r *ContentRepository) ReplaceGenres(contentID uint, genreIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		genres := []models.Genre{}
+		if len(genreIDs) > 0 {
+			if err := tx.Where("id IN ?", genreIDs).Find(&genres).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Content{ID: contentID}).Association("Genres").Replace(genres)
+	})
+}
+
+// EpisodeIDs lists the IDs of the live episodes of a content
+func (r *ContentRepository) EpisodeIDs(contentID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Episode{}).Where("content_id = ?", contentID).Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ListEpisodes lists the live episodes of a content of every kind and status
+func (r *ContentRepository) ListEpisodes(contentID uint) ([]models.Episode, error) {
+	var episodes []models.Episode
+	err := r.db.Where("content_id = ?", contentID).Order("id").Find(&episodes).Error
+	return episodes, err
+}
+
+// ReplaceLinks makes the given links the stream and download links of an
+// episode, or of the title itself when episodeID is nil. Links are matched by
+// URL, so a link that is sent again keeps its ID, enabled flag and health; the
+// links that are not sent again are deleted.
+func (r *ContentRepository) ReplaceLinks(contentID uint, episodeID *uint, streamLinks []models.StreamLink, downloadLinks []models.DownloadLink) error {
+	owner := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("content_id = ?", contentID)
+		if episodeID == nil {
+			return db.Where("episode_id IS NULL")
+		}
+		return db.Where("episode_id = ?", *episodeID)
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existingStreams []models.StreamLink
+		if err := tx.Scopes(owner).Order("id").Find(&existingStreams).Error; err != nil {
+			return err
+		}
+		byURL := make(map[string]uint, len(existingStreams))
+		for _, link := range existingStreams {
+			if _, ok := byURL[link.URL]; !ok {
+				byURL[link.URL] = link.ID
+			}
+		}
+		kept := []uint{}
+		for i := range streamLinks {
+			link := &streamLinks[i]
+			link.ContentID, link.EpisodeID = contentID, episodeID
+			if id, ok := byURL[link.URL]; ok {
+				delete(byURL, link.URL)
+				link.ID = id
+				err := tx.Model(&models.StreamLink{ID: id}).Updates(map[string]interface{}{
+					"name":     link.Name,
+					"quality":  link.Quality,
+					"type":     link.Type,
+					"priority": link.Priority,
+				}).Error
+				if err != nil {
+					return err
+				}
+			} else if err := tx.Create(link).Error; err != nil {
+				return err
+			}
+			kept = append(kept, link.ID)
+		}
+		stale := tx.Scopes(owner)
+		if len(kept) > 0 {
+			stale = stale.Where("id NOT IN ?", kept)
+		}
+		if err := stale.Delete(&models.StreamLink{}).Error; err != nil {
+			return err
+		}
+
+		var existingDownloads []models.DownloadLink
+		if err := tx.Scopes(owner).Order("id").Find(&existingDownloads).Error; err != nil {
+			return err
+		}
+		byURL = make(map[string]uint, len(existingDownloads))
+		for _, link := range existingDownloads {
+			if _, ok := byURL[link.URL]; !ok {
+				byURL[link.URL] = link.ID
+			}
+		}
+		kept = []uint{}
+		for i := range downloadLinks {
+			link := &downloadLinks[i]
+			link.ContentID, link.EpisodeID = contentID, episodeID
+			if id, ok := byURL[link.URL]; ok {
+				delete(byURL, link.URL)
+				link.ID = id
+				err := tx.Model(&models.DownloadLink{ID: id}).Updates(map[string]interface{}{
+					"name":     link.Name,
+					"quality":  link.Quality,
+					"priority": link.Priority,
+				}).Error
+				if err != nil {
+					return err
+				}
+			} else if err := tx.Create(link).Error; err != nil {
+				return err
+			}
+			kept = append(kept, link.ID)
+		}
+		stale = tx.Scopes(owner)
+		if len(kept) > 0 {
+			stale = stale.Where("id NOT IN ?", kept)
+		}
+		return stale.Delete(&models.DownloadLink{}).Error
+	})
+}
+
 // AddStreamLink adds a stream link to content
 func (r *ContentRepository) AddStreamLink(contentID uint, streamLink *models.StreamLink) error {
 	streamLink.ContentID = contentID
@@ -177,13 +478,3 @@ func (r *ContentRepository) AddDownloadLink(contentID uint, downloadLink *models
 func (r *ContentRepository) GetDB() *gorm.DB {
 	return r.db
 }
-
-// DeleteStreamLinks deletes all stream links for a content
-func (r *ContentRepository) DeleteStreamLinks(contentID uint) error {
-	return r.db.Where("content_id = ?", contentID).Delete(&models.StreamLink{}).Error
-}
-
-// DeleteDownloadLinks deletes all download links for a content
-func (r *ContentRepository) DeleteDownloadLinks(contentID uint) error {
-	return r.db.Where("content_id = ?", contentID).Delete(&models.DownloadLink{}).Error
-}
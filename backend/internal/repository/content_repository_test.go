@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// capturedQueries records the SQL of every query run on db
+func capturedQueries(t *testing.T, db *gorm.DB) *[]string {
+	queries := []string{}
+	err := db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+	})
+	require.NoError(t, err)
+	return &queries
+}
+
+func TestFindByGenreMatchesTitlesOfTheGenre(t *testing.T) {
+	db := dryRunDB(t)
+	queries := capturedQueries(t, db)
+
+	_, _, err := NewContentRepository(db).FindByGenre(nil, 4, 1, 20)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, *queries)
+	for _, sql := range *queries {
+		// The link table's content_id, not its own id, names the titles of the genre
+		assert.NotContains(t, sql, "SELECT id FROM \"content_genres\"")
+	}
+	assert.Contains(t, (*queries)[len(*queries)-1], "WHERE id IN (SELECT content_id FROM \"content_genres\" WHERE genre_id = $1)")
+}
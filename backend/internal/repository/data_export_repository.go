@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// DataExportRepository handles database operations for personal data exports
+type DataExportRepository struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository creates a new DataExportRepository
+func NewDataExportRepository(db *gorm.DB) *DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+// Create creates a new export job
+func (r *DataExportRepository) Create(export *models.DataExport) error {
+	return r.db.Create(export).Error
+}
+
+// FindByID finds an export job by ID
+func (r *DataExportRepository) FindByID(id uint) (*models.DataExport, error) {
+	var export models.DataExport
+	if err := r.db.First(&export, id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Update updates an export job
+func (r *DataExportRepository) Update(export *models.DataExport) error {
+	return r.db.Save(export).Error
+}
+
+// Delete deletes an export job
+func (r *DataExportRepository) Delete(id uint) error {
+	return r.db.Delete(&models.DataExport{}, id).Error
+}
+
+// ListByUserID lists all export jobs of a user
+func (r *DataExportRepository) ListByUserID(userID uint) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&exports).Error
+	return exports, err
+}
+
+// ListExpired lists exports whose download window has passed
+func (r *DataExportRepository) ListExpired(now time.Time) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := r.db.Where("expires_at IS NOT NULL AND expires_at < ?", now).Find(&exports).Error
+	return exports, err
+}
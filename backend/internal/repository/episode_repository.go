@@ -2,9 +2,11 @@ package repository
 
 import (
 	"log"
+	"time"
 
 	"github.com/username/anime-streaming/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // EpisodeRepository handles database operations for episodes
@@ -33,64 +35,346 @@ func (r *EpisodeRepository) FindByID(id uint) (*models.Episode, error) {
 	return &episode, nil
 }
 
+// FindWithDeleted finds an episode by ID, including soft-deleted episodes
+func (r *EpisodeRepository) FindWithDeleted(id uint) (*models.Episode, error) {
+	var episode models.Episode
+	if err := r.db.Unscoped().First(&episode, id).Error; err != nil {
+		return nil, err
+	}
+	return &episode, nil
+}
+
+// FindVisibleByID finds an episode by ID if the viewer is allowed to see it
+func (r *EpisodeRepository) FindVisibleByID(viewer *models.Viewer, id uint) (*models.Episode, error) {
+	var episode models.Episode
+	if err := r.db.Scopes(withChapters, withLinks(viewer), episodeVisibleTo(viewer)).First(&episode, id).Error; err != nil {
+		return nil, err
+	}
+	return &episode, nil
+}
+
 // Update updates an episode
 func (r *EpisodeRepository) Update(episode *models.Episode) error {
-	return r.db.Save(episode).Error
+	return updateVersioned(r.db, episode, &episode.Version)
 }
 
-// Delete deletes an episode
+// Delete soft-deletes an episode together with its stream and download links,
+// stamping them with the same deletion time
 func (r *EpisodeRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Episode{}, id).Error
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var episode models.Episode
+		if err := tx.First(&episode, id).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{&models.StreamLink{}, &models.DownloadLink{}} {
+			if err := tx.Model(model).Where("episode_id = ?", episode.ID).Update("deleted_at", now).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&episode).Update("deleted_at", now).Error
+	})
 }
 
-// ListByContentID lists all episodes for a specific content ID with optional season filter
-func (r *EpisodeRepository) ListByContentID(contentID uint, season *int) ([]models.Episode, error) {
-	var episodes []models.Episode
-	query := r.db.Where("content_id = ?", contentID)
+// PublishDue publishes every scheduled episode whose publish time has passed
+// and returns the IDs of the episodes it published
+func (r *EpisodeRepository) PublishDue(now time.Time) ([]uint, error) {
+	return publishDue(r.db, &models.Episode{}, now)
+}
+
+// withChapters loads the chapters of the episodes in playback order
+func withChapters(db *gorm.DB) *gorm.DB {
+	return db.Preload("Chapters", func(db *gorm.DB) *gorm.DB {
+		return db.Order("chapters.start")
+	})
+}
+
+// episodesMatching narrows a query on the episodes table down to the filter.
+// A cour is resolved to its range of season-relative episode numbers.
+func episodesMatching(filter models.EpisodeFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !filter.IncludeSpecials {
+			db = db.Where("episodes.type = ?", models.EpisodeKindRegular)
+		}
+		if filter.SkipFiller {
+			db = db.Where("episodes.canon <> ?", models.EpisodeCanonFiller)
+		}
+		if filter.Season == nil {
+			return db
+		}
+		db = db.Where("episodes.season_number = ?", *filter.Season)
+		if filter.Cour != nil {
+			cour := db.Session(&gorm.Session{NewDB: true}).
+				Model(&models.EpisodeCour{}).
+				Select("1").
+				Where("episode_cours.content_id = episodes.content_id AND episode_cours.season_number = episodes.season_number").
+				Where("episode_cours.number = ?", *filter.Cour).
+				Where("episodes.episode_number BETWEEN episode_cours.first_episode AND episode_cours.last_episode")
+			db = db.Where("EXISTS (?)", cour)
+		}
+		return db
+	}
+}
+
+// numberEpisodes gives the regular episodes of a title without an absolute
+// number one. The episodes are locked while they are numbered so two saves
+// cannot hand out the same number.
+func numberEpisodes(db *gorm.DB, contentID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var episodes []models.Episode
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "absolute_number").
+			Where("content_id = ? AND type = ?", contentID, models.EpisodeKindRegular).
+			Order("season_number, episode_number, id").
+			Find(&episodes).Error
+		if err != nil {
+			return err
+		}
+
+		for id, number := range absoluteNumbers(episodes) {
+			if err := tx.Model(&models.Episode{}).Where("id = ?", id).UpdateColumn("absolute_number", number).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// absoluteNumbers picks numbers for the unnumbered episodes among the live
+// regular episodes of a title, given in season and episode order. An episode
+// follows the one before it when that number is free, e.g. when it was deleted
+// and added again; otherwise it goes after the highest number so no two live
+// episodes ever share one. Numbered episodes keep theirs.
+func absoluteNumbers(episodes []models.Episode) map[uint]int {
+	used := map[int]bool{}
+	highest := 0
+	for _, episode := range episodes {
+		if episode.AbsoluteNumber != nil {
+			used[*episode.AbsoluteNumber] = true
+			if *episode.AbsoluteNumber > highest {
+				highest = *episode.AbsoluteNumber
+			}
+		}
+	}
+
+	numbers := map[uint]int{}
+	previous := 0
+	for _, episode := range episodes {
+		if episode.AbsoluteNumber != nil {
+			previous = *episode.AbsoluteNumber
+			continue
+		}
 
-	if season != nil {
-		query = query.Where("season_number = ?", *season)
+		number := previous + 1
+		if used[number] {
+			number = highest + 1
+		}
+		used[number] = true
+		if number > highest {
+			highest = number
+		}
+		numbers[episode.ID] = number
+		previous = number
 	}
+	return numbers
+}
+
+// AbsoluteNumberTaken checks whether another live episode of a content already has the number
+func (r *EpisodeRepository) AbsoluteNumberTaken(contentID uint, number int, exceptID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Episode{}).
+		Where("content_id = ? AND absolute_number = ? AND id <> ?", contentID, number, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
 
-	err := query.Order("season_number, episode_number").Find(&episodes).Error
+// ListByContentID lists the episodes of a content, optionally narrowed down
+// to a season or cour and with or without specials
+func (r *EpisodeRepository) ListByContentID(viewer *models.Viewer, contentID uint, filter models.EpisodeFilter) ([]models.Episode, error) {
+	var episodes []models.Episode
+	err := r.db.Scopes(withChapters, episodeListedTo(viewer), episodesMatching(filter)).
+		Where("episodes.content_id = ?", contentID).
+		Order("episodes.season_number, episodes.episode_number").
+		Find(&episodes).Error
 	return episodes, err
 }
 
-// GetNextEpisode gets the next episode in a series
-func (r *EpisodeRepository) GetNextEpisode(contentID uint, currentSeason, currentEpisode int) (*models.Episode, error) {
-	var nextEpisode models.Episode
+// FindByAbsoluteNumber finds a regular episode of a content by its absolute number
+func (r *EpisodeRepository) FindByAbsoluteNumber(viewer *models.Viewer, contentID uint, number int) (*models.Episode, error) {
+	var episode models.Episode
+	err := r.db.Scopes(withChapters, episodeListedTo(viewer)).
+		Where("episodes.content_id = ? AND episodes.absolute_number = ?", contentID, number).
+		First(&episode).Error
+	if err != nil {
+		return nil, err
+	}
+	return &episode, nil
+}
+
+// NumberEpisodes fills in the absolute numbers missing on the regular episodes of a content
+func (r *EpisodeRepository) NumberEpisodes(contentID uint) error {
+	return numberEpisodes(r.db, contentID)
+}
+
+// ListCours lists the split-cour mapping of a content in airing order
+func (r *EpisodeRepository) ListCours(contentID uint) ([]models.EpisodeCour, error) {
+	var cours []models.EpisodeCour
+	err := r.db.Where("content_id = ?", contentID).Order("season_number, number").Find(&cours).Error
+	return cours, err
+}
 
-	// Try to find the next episode in the same season
-	err := r.db.Where(
-		"content_id = ? AND season_number = ? AND episode_number > ?",
-		contentID, currentSeason, currentEpisode,
-	).Order("episode_number").First(&nextEpisode).Error
+// ReplaceCours replaces the split-cour mapping of a content
+func (r *EpisodeRepository) ReplaceCours(contentID uint, cours []models.EpisodeCour) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceCours(tx, contentID, cours)
+	})
+}
 
-	if err == nil {
-		return &nextEpisode, nil
+func replaceCours(tx *gorm.DB, contentID uint, cours []models.EpisodeCour) error {
+	if err := tx.Where("content_id = ?", contentID).Delete(&models.EpisodeCour{}).Error; err != nil {
+		return err
+	}
+	if len(cours) == 0 {
+		return nil
 	}
+	return tx.Create(&cours).Error
+}
 
-	if err == gorm.ErrRecordNotFound {
-		// Try to find the first episode of the next season
-		err = r.db.Where(
-			"content_id = ? AND season_number > ?",
-			contentID, currentSeason,
-		).Order("season_number, episode_number").First(&nextEpisode).Error
+// ListRecentlyReleased lists the episodes that went live last across the catalog, with their title
+func (r *EpisodeRepository) ListRecentlyReleased(viewer *models.Viewer, limit int) ([]models.Episode, error) {
+	var episodes []models.Episode
+	err := r.db.Scopes(episodeListedTo(viewer)).
+		Preload("Content").
+		Order("COALESCE(episodes.publish_at, episodes.created_at) DESC, episodes.id DESC").
+		Limit(limit).
+		Find(&episodes).Error
+	return episodes, err
+}
 
-		if err != nil {
-			return nil, err
+// ListArcs lists the story arcs of a content in episode order
+func (r *EpisodeRepository) ListArcs(contentID uint) ([]models.StoryArc, error) {
+	var arcs []models.StoryArc
+	err := r.db.Where("content_id = ?", contentID).Order("first_episode").Find(&arcs).Error
+	return arcs, err
+}
+
+// ReplaceArcs replaces the story arcs of a content. Arcs that carry a canon
+// flag stamp it on every episode in their range; the episodes that changed are returned.
+func (r *EpisodeRepository) ReplaceArcs(contentID uint, arcs []models.StoryArc) ([]uint, error) {
+	var changed []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("content_id = ?", contentID).Delete(&models.StoryArc{}).Error; err != nil {
+			return err
+		}
+		if len(arcs) == 0 {
+			return nil
+		}
+		if err := tx.Create(&arcs).Error; err != nil {
+			return err
 		}
 
-		return &nextEpisode, nil
+		for _, arc := range arcs {
+			if arc.Canon == "" {
+				continue
+			}
+			var ids []uint
+			err := tx.Model(&models.Episode{}).
+				Where("content_id = ? AND absolute_number BETWEEN ? AND ? AND canon <> ?", contentID, arc.FirstEpisode, arc.LastEpisode, arc.Canon).
+				Pluck("id", &ids).Error
+			if err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			err = tx.Model(&models.Episode{}).
+				Where("id IN ?", ids).
+				Updates(map[string]interface{}{
+					"canon":   arc.Canon,
+					"version": gorm.Expr("version + 1"),
+				}).Error
+			if err != nil {
+				return err
+			}
+			changed = append(changed, ids...)
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// ListChapters lists the chapters of an episode in playback order
+func (r *EpisodeRepository) ListChapters(episodeID uint) ([]models.Chapter, error) {
+	var chapters []models.Chapter
+	err := r.db.Where("episode_id = ?", episodeID).Order("start").Find(&chapters).Error
+	return chapters, err
+}
+
+// FindChapter finds the first chapter of the given kind in an episode
+func (r *EpisodeRepository) FindChapter(episodeID uint, kind models.ChapterKind) (*models.Chapter, error) {
+	var chapter models.Chapter
+	if err := r.db.Where("episode_id = ? AND kind = ?", episodeID, kind).Order("start").First(&chapter).Error; err != nil {
+		return nil, err
+	}
+	return &chapter, nil
+}
+
+// ReplaceChapters replaces the chapters of an episode
+func (r *EpisodeRepository) ReplaceChapters(episodeID uint, chapters []models.Chapter) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceChapters(tx, episodeID, chapters)
+	})
+}
+
+func replaceChapters(tx *gorm.DB, episodeID uint, chapters []models.Chapter) error {
+	if err := tx.Where("episode_id = ?", episodeID).Delete(&models.Chapter{}).Error; err != nil {
+		return err
 	}
+	if len(chapters) == 0 {
+		return nil
+	}
+	return tx.Create(&chapters).Error
+}
 
-	return nil, err
+// GetNextEpisode gets the episode that follows the given one in season and
+// episode order. Specials and filler are skipped as the filter says; its
+// season and cour are ignored.
+func (r *EpisodeRepository) GetNextEpisode(viewer *models.Viewer, contentID uint, currentSeason, currentEpisode int, filter models.EpisodeFilter) (*models.Episode, error) {
+	var nextEpisode models.Episode
+	filter.Season, filter.Cour = nil, nil
+	err := r.db.Scopes(withChapters, episodeListedTo(viewer), episodesMatching(filter)).
+		Where("episodes.content_id = ?", contentID).
+		Where("(episodes.season_number, episodes.episode_number) > (?, ?)", currentSeason, currentEpisode).
+		Order("episodes.season_number, episodes.episode_number").
+		First(&nextEpisode).Error
+	if err != nil {
+		return nil, err
+	}
+	return &nextEpisode, nil
+}
+
+// GetPreviousEpisode gets the episode that comes before the given one in
+// season and episode order. Specials and filler are skipped as the filter
+// says; its season and cour are ignored.
+func (r *EpisodeRepository) GetPreviousEpisode(viewer *models.Viewer, contentID uint, currentSeason, currentEpisode int, filter models.EpisodeFilter) (*models.Episode, error) {
+	var previousEpisode models.Episode
+	filter.Season, filter.Cour = nil, nil
+	err := r.db.Scopes(withChapters, episodeListedTo(viewer), episodesMatching(filter)).
+		Where("episodes.content_id = ?", contentID).
+		Where("(episodes.season_number, episodes.episode_number) < (?, ?)", currentSeason, currentEpisode).
+		Order("episodes.season_number DESC, episodes.episode_number DESC").
+		First(&previousEpisode).Error
+	if err != nil {
+		return nil, err
+	}
+	return &previousEpisode, nil
 }
 
 // GetLatestEpisode gets the latest episode for a content
-func (r *EpisodeRepository) GetLatestEpisode(contentID uint) (*models.Episode, error) {
+func (r *EpisodeRepository) GetLatestEpisode(viewer *models.Viewer, contentID uint) (*models.Episode, error) {
 	var episode models.Episode
-	err := r.db.Where("content_id = ?", contentID).
+	err := r.db.Scopes(withChapters, episodeListedTo(viewer)).Where("content_id = ?", contentID).
 		Order("season_number DESC, episode_number DESC").
 		First(&episode).Error
 
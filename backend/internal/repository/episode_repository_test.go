@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+// numbered builds live regular episodes in episode order; zero means not numbered yet
+func numbered(numbers ...int) []models.Episode {
+	episodes := make([]models.Episode, 0, len(numbers))
+	for i, number := range numbers {
+		episode := models.Episode{ID: uint(i + 1)}
+		if number > 0 {
+			n := number
+			episode.AbsoluteNumber = &n
+		}
+		episodes = append(episodes, episode)
+	}
+	return episodes
+}
+
+func TestAbsoluteNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		episodes []models.Episode
+		want     map[uint]int
+	}{
+		{
+			name:     "new title",
+			episodes: numbered(0, 0, 0),
+			want:     map[uint]int{1: 1, 2: 2, 3: 3},
+		},
+		{
+			name:     "next episode",
+			episodes: numbered(1, 2, 3, 0),
+			want:     map[uint]int{4: 4},
+		},
+		{
+			// Episode 2 was deleted and added again
+			name:     "gap left by a deleted episode",
+			episodes: numbered(1, 0, 3, 4),
+			want:     map[uint]int{2: 2},
+		},
+		{
+			// A missing episode added between two numbered ones
+			name:     "added out of order",
+			episodes: numbered(1, 2, 0, 3, 4),
+			want:     map[uint]int{3: 5},
+		},
+		{
+			name:     "several added out of order",
+			episodes: numbered(1, 0, 0, 2, 3),
+			want:     map[uint]int{2: 4, 3: 5},
+		},
+		{
+			// Explicit numbers, e.g. a second season continuing from episode 13
+			name:     "continuing explicit numbers",
+			episodes: numbered(13, 14, 0, 0),
+			want:     map[uint]int{3: 15, 4: 16},
+		},
+		{
+			name:     "nothing to number",
+			episodes: numbered(1, 2),
+			want:     map[uint]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := absoluteNumbers(tt.episodes)
+			assert.Equal(t, tt.want, got)
+
+			// No two episodes end up with the same number
+			seen := map[int]bool{}
+			for _, episode := range tt.episodes {
+				number, ok := got[episode.ID]
+				if episode.AbsoluteNumber != nil {
+					number, ok = *episode.AbsoluteNumber, true
+				}
+				if assert.True(t, ok) {
+					assert.False(t, seen[number], "number %d given twice", number)
+					seen[number] = true
+				}
+			}
+		})
+	}
+}
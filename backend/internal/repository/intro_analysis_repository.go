@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IntroAnalysisRepository handles database operations for intro detection jobs and their suggestions
+type IntroAnalysisRepository struct {
+	db *gorm.DB
+}
+
+// NewIntroAnalysisRepository creates a new IntroAnalysisRepository
+func NewIntroAnalysisRepository(db *gorm.DB) *IntroAnalysisRepository {
+	return &IntroAnalysisRepository{db: db}
+}
+
+// Create creates a new analysis job
+func (r *IntroAnalysisRepository) Create(analysis *models.IntroAnalysis) error {
+	return r.db.Create(analysis).Error
+}
+
+// CreateUnlessRunning creates a new analysis job unless its season already
+// has one queued or running, which the unique index on running jobs decides
+func (r *IntroAnalysisRepository) CreateUnlessRunning(analysis *models.IntroAnalysis) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(analysis)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByID finds an analysis job by ID with its suggestions
+func (r *IntroAnalysisRepository) FindByID(id uint) (*models.IntroAnalysis, error) {
+	var analysis models.IntroAnalysis
+	err := r.db.Preload("Suggestions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("episode_id, start")
+	}).First(&analysis, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &analysis, nil
+}
+
+// Update updates an analysis job
+func (r *IntroAnalysisRepository) Update(analysis *models.IntroAnalysis) error {
+	return r.db.Omit("Suggestions").Save(analysis).Error
+}
+
+// List lists analysis jobs, newest first, optionally for one content
+func (r *IntroAnalysisRepository) List(contentID *uint) ([]models.IntroAnalysis, error) {
+	var analyses []models.IntroAnalysis
+	query := r.db.Order("created_at DESC")
+	if contentID != nil {
+		query = query.Where("content_id = ?", *contentID)
+	}
+	err := query.Limit(100).Find(&analyses).Error
+	return analyses, err
+}
+
+// FailUnfinished marks every queued or running analysis as failed
+func (r *IntroAnalysisRepository) FailUnfinished(reason string) error {
+	return r.db.Model(&models.IntroAnalysis{}).
+		Where("status IN ?", []models.IntroAnalysisStatus{models.IntroAnalysisPending, models.IntroAnalysisProcessing}).
+		Updates(map[string]interface{}{"status": models.IntroAnalysisFailed, "error": reason}).Error
+}
+
+// ReplacePendingSuggestions stores the suggestions of an analysis, dropping the
+// suggestions still waiting for review on the same episodes
+func (r *IntroAnalysisRepository) ReplacePendingSuggestions(episodeIDs []uint, suggestions []models.ChapterSuggestion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(episodeIDs) > 0 {
+			err := tx.Where("episode_id IN ? AND status = ?", episodeIDs, models.ChapterSuggestionPending).
+				Delete(&models.ChapterSuggestion{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(suggestions) == 0 {
+			return nil
+		}
+		return tx.Create(&suggestions).Error
+	})
+}
+
+// FindSuggestion finds a chapter suggestion by ID
+func (r *IntroAnalysisRepository) FindSuggestion(id uint) (*models.ChapterSuggestion, error) {
+	var suggestion models.ChapterSuggestion
+	if err := r.db.First(&suggestion, id).Error; err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+// ListSuggestions lists chapter suggestions with their episodes, optionally
+// for one content and in one review state
+func (r *IntroAnalysisRepository) ListSuggestions(contentID *uint, status models.ChapterSuggestionStatus, page, pageSize int) ([]models.ChapterSuggestion, int64, error) {
+	var suggestions []models.ChapterSuggestion
+	var count int64
+
+	query := r.db.Model(&models.ChapterSuggestion{})
+	if contentID != nil {
+		query = query.Where("content_id = ?", *contentID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Episode").
+		Order("content_id, episode_id, start").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&suggestions).Error
+	return suggestions, count, err
+}
+
+// ReviewSuggestion records the review of a suggestion still waiting for one.
+// It reports false when another review got there first.
+func (r *IntroAnalysisRepository) ReviewSuggestion(id uint, status models.ChapterSuggestionStatus) (bool, error) {
+	result := r.db.Model(&models.ChapterSuggestion{}).
+		Where("id = ? AND status = ?", id, models.ChapterSuggestionPending).
+		Update("status", status)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// LinkRepository handles database operations for stream and download links
+type LinkRepository struct {
+	db *gorm.DB
+}
+
+// NewLinkRepository creates a new LinkRepository
+func NewLinkRepository(db *gorm.DB) *LinkRepository {
+	return &LinkRepository{db: db}
+}
+
+// FindStreamLink finds a stream link by ID
+func (r *LinkRepository) FindStreamLink(id uint) (*models.StreamLink, error) {
+	var link models.StreamLink
+	if err := r.db.First(&link, id).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindDownloadLink finds a download link by ID
+func (r *LinkRepository) FindDownloadLink(id uint) (*models.DownloadLink, error) {
+	var link models.DownloadLink
+	if err := r.db.First(&link, id).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// CreateStreamLink creates a new stream link
+func (r *LinkRepository) CreateStreamLink(link *models.StreamLink) error {
+	enabled := link.Enabled
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(link).Error; err != nil {
+			return err
+		}
+		return keepDisabled(tx, link, &link.Enabled, enabled)
+	})
+}
+
+// CreateDownloadLink creates a new download link
+func (r *LinkRepository) CreateDownloadLink(link *models.DownloadLink) error {
+	enabled := link.Enabled
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(link).Error; err != nil {
+			return err
+		}
+		return keepDisabled(tx, link, &link.Enabled, enabled)
+	})
+}
+
+// keepDisabled switches a just created link off when it was meant to be. GORM
+// inserts the column default in place of a false Enabled.
+func keepDisabled(tx *gorm.DB, link interface{}, field *bool, enabled bool) error {
+	if enabled {
+		return nil
+	}
+	*field = false
+	return tx.Model(link).Update("enabled", false).Error
+}
+
+// UpdateStreamLink updates a stream link
+func (r *LinkRepository) UpdateStreamLink(link *models.StreamLink) error {
+	return r.db.Save(link).Error
+}
+
+// UpdateDownloadLink updates a download link
+func (r *LinkRepository) UpdateDownloadLink(link *models.DownloadLink) error {
+	return r.db.Save(link).Error
+}
+
+// DeleteStreamLink deletes a stream link
+func (r *LinkRepository) DeleteStreamLink(id uint) error {
+	return r.db.Delete(&models.StreamLink{}, id).Error
+}
+
+// DeleteDownloadLink deletes a download link
+func (r *LinkRepository) DeleteDownloadLink(id uint) error {
+	return r.db.Delete(&models.DownloadLink{}, id).Error
+}
+
+// linkModel returns the table and model behind a link kind
+func linkModel(kind models.LinkKind) (string, interface{}) {
+	if kind == models.LinkKindDownload {
+		return "download_links", &models.DownloadLink{}
+	}
+	return "stream_links", &models.StreamLink{}
+}
+
+// DueStreamLinks lists external stream links not checked since the given time, least recently checked first
+func (r *LinkRepository) DueStreamLinks(checkedBefore time.Time, limit int) ([]models.StreamLink, error) {
+	var links []models.StreamLink
+	err := r.db.Where("type <> ?", "self-hosted").
+		Where("last_checked_at IS NULL OR last_checked_at < ?", checkedBefore).
+		Order("last_checked_at NULLS FIRST, id").
+		Limit(limit).
+		Find(&links).Error
+	return links, err
+}
+
+// DueDownloadLinks lists download links not checked since the given time, least recently checked first
+func (r *LinkRepository) DueDownloadLinks(checkedBefore time.Time, limit int) ([]models.DownloadLink, error) {
+	var links []models.DownloadLink
+	err := r.db.Where("last_checked_at IS NULL OR last_checked_at < ?", checkedBefore).
+		Order("last_checked_at NULLS FIRST, id").
+		Limit(limit).
+		Find(&links).Error
+	return links, err
+}
+
+// SaveHealth records the outcome of a check of the given URL without touching
+// the link's update time. A link whose URL was changed in the meantime is left
+// alone, since the check says nothing about the new one.
+func (r *LinkRepository) SaveHealth(kind models.LinkKind, id uint, url string, health models.LinkHealth) error {
+	_, model := linkModel(kind)
+	return r.db.Model(model).Where("id = ? AND url = ?", id, url).UpdateColumns(map[string]interface{}{
+		"health_status":   health.HealthStatus,
+		"http_status":     health.HTTPStatus,
+		"latency_ms":      health.LatencyMs,
+		"check_error":     health.CheckError,
+		"failure_count":   health.FailureCount,
+		"last_checked_at": health.LastCheckedAt,
+	}).Error
+}
+
+// HealthSummary counts the links of each kind by health status
+func (r *LinkRepository) HealthSummary() (map[models.LinkKind]map[models.LinkHealthStatus]int64, error) {
+	summary := map[models.LinkKind]map[models.LinkHealthStatus]int64{}
+	for _, kind := range []models.LinkKind{models.LinkKindStream, models.LinkKindDownload} {
+		var rows []struct {
+			HealthStatus models.LinkHealthStatus
+			Count        int64
+		}
+		_, model := linkModel(kind)
+		err := r.db.Model(model).Select("health_status, COUNT(*) AS count").Group("health_status").Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+
+		summary[kind] = map[models.LinkHealthStatus]int64{}
+		for _, row := range rows {
+			summary[kind][row.HealthStatus] = row.Count
+		}
+	}
+	return summary, nil
+}
+
+// HealthReport lists the links of a kind with their title and episode, worst
+// first, optionally only those in one health status
+func (r *LinkRepository) HealthReport(kind models.LinkKind, status models.LinkHealthStatus, page, pageSize int) ([]models.LinkHealthEntry, int64, error) {
+	table, _ := linkModel(kind)
+	query := r.db.Table(table + " l").
+		Joins("JOIN contents c ON c.id = l.content_id").
+		Joins("LEFT JOIN episodes e ON e.id = l.episode_id").
+		Where("l.deleted_at IS NULL")
+	if status != "" {
+		query = query.Where("l.health_status = ?", status)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	entries := []models.LinkHealthEntry{}
+	err := query.Select(`l.id, l.content_id, c.title AS content_title, l.episode_id,
+			e.season_number, e.episode_number, l.name, l.url, l.enabled,
+			l.health_status, l.http_status, l.latency_ms, l.check_error, l.failure_count, l.last_checked_at`).
+		Order("l.failure_count DESC, l.last_checked_at DESC, l.id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range entries {
+		entries[i].Kind = kind
+	}
+	return entries, count, nil
+}
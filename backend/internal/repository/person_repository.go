@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// PersonRepository handles database operations for people
+type PersonRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonRepository creates a new PersonRepository
+func NewPersonRepository(db *gorm.DB) *PersonRepository {
+	return &PersonRepository{db: db}
+}
+
+// Create creates a new person
+func (r *PersonRepository) Create(person *models.Person) error {
+	return r.db.Create(person).Error
+}
+
+// FindByID finds a person by ID
+func (r *PersonRepository) FindByID(id uint) (*models.Person, error) {
+	var person models.Person
+	if err := r.db.First(&person, id).Error; err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// Update updates a person
+func (r *PersonRepository) Update(person *models.Person) error {
+	return r.db.Save(person).Error
+}
+
+// List lists people with pagination, optionally filtered by name
+func (r *PersonRepository) List(term string, page, pageSize int) ([]models.Person, int64, error) {
+	var people []models.Person
+	var count int64
+	query := r.db.Model(&models.Person{})
+	if term != "" {
+		query = query.Where("name ILIKE ?", "%"+term+"%")
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("name").Offset(offset).Limit(pageSize).Find(&people).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return people, count, nil
+}
+
+// Delete deletes a person and removes their staff and voice credits
+func (r *PersonRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("person_id = ?", id).Delete(&models.ContentStaff{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ContentCharacter{}).Where("person_id = ?", id).Update("person_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Person{}, id).Error
+	})
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// ProfileRepository handles database operations for viewer profiles
+type ProfileRepository struct {
+	db *gorm.DB
+}
+
+// NewProfileRepository creates a new ProfileRepository
+func NewProfileRepository(db *gorm.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+// Create creates a new profile
+func (r *ProfileRepository) Create(profile *models.Profile) error {
+	return r.db.Create(profile).Error
+}
+
+// FindByID finds a profile by ID
+func (r *ProfileRepository) FindByID(id uint) (*models.Profile, error) {
+	var profile models.Profile
+	if err := r.db.First(&profile, id).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Update updates a profile
+func (r *ProfileRepository) Update(profile *models.Profile) error {
+	return r.db.Save(profile).Error
+}
+
+// Delete deletes a profile together with its watch history
+func (r *ProfileRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("profile_id = ?", id).Delete(&models.WatchHistory{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Profile{}, id).Error
+	})
+}
+
+// ListByUserID lists all profiles of a user
+func (r *ProfileRepository) ListByUserID(userID uint) ([]models.Profile, error) {
+	var profiles []models.Profile
+	err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&profiles).Error
+	return profiles, err
+}
+
+// CountByUserID counts the profiles of a user
+func (r *ProfileRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Profile{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// RevisionRepository handles database operations for content revisions
+type RevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewRevisionRepository creates a new RevisionRepository
+func NewRevisionRepository(db *gorm.DB) *RevisionRepository {
+	return &RevisionRepository{db: db}
+}
+
+// Transaction runs fn in a database transaction; repositories created on tx take part in it
+func (r *RevisionRepository) Transaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+// Create stores a revision
+func (r *RevisionRepository) Create(revision *models.ContentRevision) error {
+	return r.db.Create(revision).Error
+}
+
+// FindByID finds a revision by ID
+func (r *RevisionRepository) FindByID(id uint) (*models.ContentRevision, error) {
+	var revision models.ContentRevision
+	if err := r.db.Preload("User").First(&revision, id).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// FindLatest finds the most recent revision of a content or episode
+func (r *RevisionRepository) FindLatest(entityType models.RevisionEntity, entityID uint) (*models.ContentRevision, error) {
+	var revision models.ContentRevision
+	err := r.db.
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("id DESC").
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// ListByContentID lists the revisions of a content and its episodes, newest first
+func (r *RevisionRepository) ListByContentID(contentID uint, page, pageSize int) ([]models.ContentRevision, int64, error) {
+	var revisions []models.ContentRevision
+	var total int64
+
+	query := r.db.Model(&models.ContentRevision{}).Where("content_id = ?", contentID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("User").Order("id DESC").Offset(offset).Limit(pageSize).Find(&revisions).Error
+	return revisions, total, err
+}
+
+// RestoreContentRelations puts back the genres, categories, cours and arcs of
+// a content restored from a snapshot, in one transaction. Nil cours or arcs
+// come from snapshots taken before they were recorded and are left alone.
+func (r *RevisionRepository) RestoreContentRelations(contentID uint, genreIDs, categoryIDs []uint, cours []models.EpisodeCour, arcs []models.StoryArc) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		content := &models.Content{ID: contentID}
+
+		genres := []models.Genre{}
+		if len(genreIDs) > 0 {
+			if err := tx.Where("id IN ?", genreIDs).Find(&genres).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(content).Association("Genres").Replace(genres); err != nil {
+			return err
+		}
+
+		categories := []models.Category{}
+		if len(categoryIDs) > 0 {
+			if err := tx.Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(content).Association("Categories").Replace(categories); err != nil {
+			return err
+		}
+
+		if cours != nil {
+			if err := replaceCours(tx, content.ID, cours); err != nil {
+				return err
+			}
+		}
+		if arcs != nil {
+			// Only the arcs come back; the canon flags they once stamped are part of the episode revisions
+			if err := tx.Where("content_id = ?", content.ID).Delete(&models.StoryArc{}).Error; err != nil {
+				return err
+			}
+			if len(arcs) > 0 {
+				if err := tx.Create(&arcs).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
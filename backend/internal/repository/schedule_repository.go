@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// ScheduleRepository handles database operations for episode schedules
+type ScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepository creates a new ScheduleRepository
+func NewScheduleRepository(db *gorm.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// Create creates new schedule entries
+func (r *ScheduleRepository) Create(schedules []models.EpisodeSchedule) error {
+	return r.db.Create(&schedules).Error
+}
+
+// FindByID finds a schedule entry by ID
+func (r *ScheduleRepository) FindByID(id uint) (*models.EpisodeSchedule, error) {
+	var schedule models.EpisodeSchedule
+	if err := r.db.First(&schedule, id).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// Delete deletes a schedule entry
+func (r *ScheduleRepository) Delete(id uint) error {
+	return r.db.Delete(&models.EpisodeSchedule{}, id).Error
+}
+
+// ListByContentID lists the schedule of a content in airing order
+func (r *ScheduleRepository) ListByContentID(contentID uint) ([]models.EpisodeSchedule, error) {
+	var schedules []models.EpisodeSchedule
+	err := r.db.Where("content_id = ?", contentID).Order("airs_at").Find(&schedules).Error
+	return schedules, err
+}
+
+// ListBetween lists the broadcasts in [from, to) of titles the viewer may see
+func (r *ScheduleRepository) ListBetween(viewer *models.Viewer, from, to time.Time) ([]models.EpisodeSchedule, error) {
+	var schedules []models.EpisodeSchedule
+	err := r.db.Scopes(contentVisibleTo(viewer)).
+		Where("airs_at >= ? AND airs_at < ?", from, to).
+		Preload("Content").
+		Order("airs_at").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// ListBetweenForContents lists the broadcasts in [from, to) of the given titles
+// listed to the viewer. Titles in the trash are left out even for staff.
+func (r *ScheduleRepository) ListBetweenForContents(viewer *models.Viewer, contentIDs []uint, from, to time.Time) ([]models.EpisodeSchedule, error) {
+	var schedules []models.EpisodeSchedule
+	if len(contentIDs) == 0 {
+		return schedules, nil
+	}
+	listed := r.db.Model(&models.Content{}).Scopes(listedTo(viewer)).Select("contents.id")
+	err := r.db.Where("content_id IN ? AND airs_at >= ? AND airs_at < ?", contentIDs, from, to).
+		Where("content_id IN (?)", listed).
+		Preload("Content").
+		Order("airs_at").
+		Find(&schedules).Error
+	return schedules, err
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+func TestListBetweenForContentsScopesToViewer(t *testing.T) {
+	tests := []struct {
+		name   string
+		viewer *models.Viewer
+		staff  bool
+	}{
+		{name: "user", viewer: &models.Viewer{UserID: 3, Role: models.RoleUser}},
+		{name: "staff", viewer: &models.Viewer{UserID: 1, Role: models.RoleAdmin}, staff: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := dryRunDB(t)
+			queries := capturedQueries(t, db)
+
+			now := time.Now()
+			_, err := NewScheduleRepository(db).ListBetweenForContents(tt.viewer, []uint{4, 5}, now, now.Add(time.Hour))
+			require.NoError(t, err)
+
+			sql := ""
+			for _, query := range *queries {
+				if strings.Contains(query, `FROM "episode_schedules"`) {
+					sql = query
+				}
+			}
+			require.NotEmpty(t, sql)
+			// Trashed titles are left out for everyone
+			assert.Contains(t, sql, `content_id IN (SELECT contents.id FROM "contents" WHERE`)
+			assert.Contains(t, sql, `"contents"."deleted_at" IS NULL`)
+			if tt.staff {
+				assert.NotContains(t, sql, "contents.status")
+			} else {
+				assert.Contains(t, sql, "contents.status = ", "drafts, scheduled and unlisted titles stay out of the feed")
+			}
+		})
+	}
+}
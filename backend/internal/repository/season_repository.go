@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/username/anime-streaming/internal/models"
 	"gorm.io/gorm"
 )
@@ -31,7 +34,7 @@ func (r *SeasonRepository) FindByID(id uint) (*models.Season, error) {
 
 // Update updates a season
 func (r *SeasonRepository) Update(season *models.Season) error {
-	return r.db.Save(season).Error
+	return updateVersioned(r.db, season, &season.Version)
 }
 
 // Delete deletes a season
@@ -60,11 +63,43 @@ func (r *SeasonRepository) FindByNameAndYear(name string, year int) (*models.Sea
 	return &season, nil
 }
 
-// GetCurrentSeason gets the current active season
-func (r *SeasonRepository) GetCurrentSeason() (*models.Season, error) {
+// ExistsWithDeleted checks whether a season was ever created, including seasons in the trash
+func (r *SeasonRepository) ExistsWithDeleted(name string, year int) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Season{}).Where("name = ? AND year = ?", name, year).Count(&count).Error
+	return count > 0, err
+}
+
+// GetCurrentSeason gets the season whose dates contain the given day. Seasons
+// without dates fall back to the one marked active.
+func (r *SeasonRepository) GetCurrentSeason(day time.Time) (*models.Season, error) {
 	var season models.Season
-	if err := r.db.Where("status = ?", "Active").First(&season).Error; err != nil {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	err := r.db.Where("start_date <= ? AND end_date >= ?", day, day).Order("start_date DESC").First(&season).Error
+	if err == nil {
+		return &season, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err := r.db.Where("status = ?", models.SeasonActive).First(&season).Error; err != nil {
 		return nil, err
 	}
 	return &season, nil
 }
+
+// UpdateLifecycle stores the dates and derived status of a season
+func (r *SeasonRepository) UpdateLifecycle(season *models.Season) error {
+	result := r.db.Model(&models.Season{}).
+		Where("id = ?", season.ID).
+		Updates(map[string]interface{}{
+			"start_date": season.StartDate,
+			"end_date":   season.EndDate,
+			"status":     season.Status,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error == nil {
+		season.Version++
+	}
+	return result.Error
+}
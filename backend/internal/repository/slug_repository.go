@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// SlugRepository handles slug lookups and redirect history
+type SlugRepository struct {
+	db *gorm.DB
+}
+
+// NewSlugRepository creates a new SlugRepository
+func NewSlugRepository(db *gorm.DB) *SlugRepository {
+	return &SlugRepository{db: db}
+}
+
+// FindIDBySlug finds the record of an entity that currently uses a slug
+func (r *SlugRepository) FindIDBySlug(entity models.SlugEntity, slug string) (uint, error) {
+	var ids []uint
+	if err := r.db.Table(entity.Table()).Where("slug = ? AND deleted_at IS NULL", slug).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return ids[0], nil
+}
+
+// FindSlugByID returns the current slug of a record, including soft-deleted ones
+func (r *SlugRepository) FindSlugByID(entity models.SlugEntity, id uint) (string, error) {
+	var slugs []*string
+	if err := r.db.Table(entity.Table()).Where("id = ?", id).Limit(1).Pluck("slug", &slugs).Error; err != nil {
+		return "", err
+	}
+	if len(slugs) == 0 {
+		return "", gorm.ErrRecordNotFound
+	}
+	if slugs[0] == nil {
+		return "", nil
+	}
+	return *slugs[0], nil
+}
+
+// FindRedirect finds the record a former slug pointed to
+func (r *SlugRepository) FindRedirect(entity models.SlugEntity, slug string) (*models.SlugRedirect, error) {
+	var redirect models.SlugRedirect
+	if err := r.db.Where("entity_type = ? AND slug = ?", entity, slug).First(&redirect).Error; err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// IsTaken reports whether a slug is used by another record of the entity, as a
+// current slug (soft-deleted records included) or as a redirect
+func (r *SlugRepository) IsTaken(entity models.SlugEntity, slug string, exceptID uint) (bool, error) {
+	var count int64
+	err := r.db.Table(entity.Table()).Where("slug = ? AND id <> ?", slug, exceptID).Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = r.db.Model(&models.SlugRedirect{}).
+		Where("entity_type = ? AND slug = ? AND entity_id <> ?", entity, slug, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SetSlug stores the slug of a record directly, bypassing versioning
+func (r *SlugRepository) SetSlug(entity models.SlugEntity, id uint, slug string) error {
+	return r.db.Table(entity.Table()).Where("id = ?", id).Update("slug", slug).Error
+}
+
+// Move records that a record went from one slug to another. A redirect for
+// the new slug is dropped in case the record got an older slug back.
+func (r *SlugRepository) Move(entity models.SlugEntity, id uint, from, to string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("entity_type = ? AND slug = ?", entity, to).Delete(&models.SlugRedirect{}).Error; err != nil {
+			return err
+		}
+
+		var existing models.SlugRedirect
+		err := tx.Where("entity_type = ? AND slug = ?", entity, from).First(&existing).Error
+		if err == nil {
+			existing.EntityID = id
+			return tx.Save(&existing).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return tx.Create(&models.SlugRedirect{EntityType: entity, Slug: from, EntityID: id}).Error
+	})
+}
+
+// SlugSource is a record without a slug and the text its slug is made from
+type SlugSource struct {
+	ID     uint
+	Source string
+}
+
+// slugSources maps each entity to the SQL expression its slug is generated from
+var slugSources = map[models.SlugEntity]string{
+	models.SlugContent:  "title",
+	models.SlugGenre:    "name",
+	models.SlugCategory: "name",
+	models.SlugSeason:   "name || ' ' || year",
+}
+
+// ListMissing lists the records of an entity that have no slug yet, or only an
+// all-digit one that reads as an ID
+func (r *SlugRepository) ListMissing(entity models.SlugEntity) ([]SlugSource, error) {
+	var sources []SlugSource
+	err := r.db.Table(entity.Table()).
+		Select("id, " + slugSources[entity] + " AS source").
+		Where("slug IS NULL OR slug = '' OR slug ~ '^[0-9]+$'").
+		Order("id").
+		Scan(&sources).Error
+	return sources, err
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// StudioRepository handles database operations for studios
+type StudioRepository struct {
+	db *gorm.DB
+}
+
+// NewStudioRepository creates a new StudioRepository
+func NewStudioRepository(db *gorm.DB) *StudioRepository {
+	return &StudioRepository{db: db}
+}
+
+// Create creates a new studio
+func (r *StudioRepository) Create(studio *models.Studio) error {
+	return r.db.Create(studio).Error
+}
+
+// FindByID finds a studio by ID
+func (r *StudioRepository) FindByID(id uint) (*models.Studio, error) {
+	var studio models.Studio
+	if err := r.db.First(&studio, id).Error; err != nil {
+		return nil, err
+	}
+	return &studio, nil
+}
+
+// Update updates a studio
+func (r *StudioRepository) Update(studio *models.Studio) error {
+	return r.db.Save(studio).Error
+}
+
+// List lists studios with pagination, optionally filtered by name
+func (r *StudioRepository) List(term string, page, pageSize int) ([]models.Studio, int64, error) {
+	var studios []models.Studio
+	var count int64
+	query := r.db.Model(&models.Studio{})
+	if term != "" {
+		query = query.Where("name ILIKE ?", "%"+term+"%")
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("name").Offset(offset).Limit(pageSize).Find(&studios).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return studios, count, nil
+}
+
+// Delete deletes a studio and unlinks it from every content
+func (r *StudioRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("studio_id = ?", id).Delete(&models.ContentStudio{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Studio{}, id).Error
+	})
+}
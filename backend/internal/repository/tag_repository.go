@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository handles database operations for tags, their links to titles and suggestions
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new TagRepository
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// taggedWith restricts a query on the contents table with a tag filter.
+// Tag names are matched case-insensitively.
+func taggedWith(filter models.TagFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		tagged := func(names ...string) *gorm.DB {
+			lowered := make([]string, 0, len(names))
+			for _, name := range names {
+				lowered = append(lowered, strings.ToLower(name))
+			}
+			return db.Session(&gorm.Session{NewDB: true}).
+				Table("content_tags").
+				Joins("JOIN tags ON tags.id = content_tags.tag_id").
+				Where("LOWER(tags.name) IN ?", lowered).
+				Select("content_tags.content_id")
+		}
+		for _, name := range filter.Include {
+			db = db.Where("contents.id IN (?)", tagged(name))
+		}
+		if len(filter.Exclude) > 0 {
+			db = db.Where("contents.id NOT IN (?)", tagged(filter.Exclude...))
+		}
+		return db
+	}
+}
+
+// Create creates a new tag
+func (r *TagRepository) Create(tag *models.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+// FindByID finds a tag by ID
+func (r *TagRepository) FindByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.First(&tag, id).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindByName finds a tag by name, ignoring case
+func (r *TagRepository) FindByName(name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.Where("LOWER(name) = ?", strings.ToLower(name)).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// List lists tags by name, optionally within a category
+func (r *TagRepository) List(category models.TagCategory) ([]models.Tag, error) {
+	var tags []models.Tag
+	query := r.db.Order("name")
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	err := query.Find(&tags).Error
+	return tags, err
+}
+
+// Update updates a tag
+func (r *TagRepository) Update(tag *models.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// Delete deletes a tag together with its links to titles and the suggestions naming it
+func (r *TagRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", id).Delete(&models.ContentTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", id).Delete(&models.TagSuggestion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, id).Error
+	})
+}
+
+// ListByContentID lists the tags of a title, most relevant first
+func (r *TagRepository) ListByContentID(contentID uint) ([]models.ContentTag, error) {
+	var tags []models.ContentTag
+	err := r.db.
+		Where("content_id = ?", contentID).
+		Preload("Tag").
+		Order("weight DESC, id").
+		Find(&tags).Error
+	return tags, err
+}
+
+// FindContentTag finds the link between a title and a tag
+func (r *TagRepository) FindContentTag(contentID, tagID uint) (*models.ContentTag, error) {
+	var link models.ContentTag
+	if err := r.db.Where("content_id = ? AND tag_id = ?", contentID, tagID).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// SetContentTag links a tag to a title, or updates the weight and spoiler flag of an existing link
+func (r *TagRepository) SetContentTag(link *models.ContentTag) error {
+	return setContentTag(r.db, link)
+}
+
+func setContentTag(db *gorm.DB, link *models.ContentTag) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "content_id"}, {Name: "tag_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"weight", "is_spoiler", "updated_at"}),
+	}).Create(link).Error
+}
+
+// RemoveContentTag unlinks a tag from a title
+func (r *TagRepository) RemoveContentTag(contentID, tagID uint) (int64, error) {
+	result := r.db.Where("content_id = ? AND tag_id = ?", contentID, tagID).Delete(&models.ContentTag{})
+	return result.RowsAffected, result.Error
+}
+
+// CreateSuggestion stores a tag suggestion
+func (r *TagRepository) CreateSuggestion(suggestion *models.TagSuggestion) error {
+	return r.db.Create(suggestion).Error
+}
+
+// FindSuggestion finds a suggestion by ID
+func (r *TagRepository) FindSuggestion(id uint) (*models.TagSuggestion, error) {
+	var suggestion models.TagSuggestion
+	if err := r.db.First(&suggestion, id).Error; err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+// HasPendingSuggestion checks whether the user already suggested the tag for the title
+func (r *TagRepository) HasPendingSuggestion(contentID, userID uint, name string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.TagSuggestion{}).
+		Where("content_id = ? AND user_id = ? AND status = ? AND LOWER(name) = ?",
+			contentID, userID, models.TagSuggestionPending, strings.ToLower(name)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListSuggestionsByUserID lists all suggestions a user made, oldest first
+func (r *TagRepository) ListSuggestionsByUserID(userID uint) ([]models.TagSuggestion, error) {
+	var suggestions []models.TagSuggestion
+	err := r.db.Where("user_id = ?", userID).Order("created_at, id").Find(&suggestions).Error
+	return suggestions, err
+}
+
+// ListSuggestions lists suggestions with the given status, oldest first
+func (r *TagRepository) ListSuggestions(status models.TagSuggestionStatus, page, pageSize int) ([]models.TagSuggestion, int64, error) {
+	var suggestions []models.TagSuggestion
+	var count int64
+	query := r.db.Model(&models.TagSuggestion{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.
+		Preload("Content").
+		Preload("Tag").
+		Preload("User").
+		Order("created_at, id").
+		Offset(offset).Limit(pageSize).
+		Find(&suggestions).Error
+	return suggestions, count, err
+}
+
+// Approve applies a suggestion: the tag is created if it does not exist yet,
+// linked to the title, and the suggestion is marked as approved
+func (r *TagRepository) Approve(suggestion *models.TagSuggestion, tag *models.Tag, link *models.ContentTag, reviewerID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if tag.ID == 0 {
+			if err := tx.Create(tag).Error; err != nil {
+				return err
+			}
+		}
+
+		link.ContentID = suggestion.ContentID
+		link.TagID = tag.ID
+		if err := setContentTag(tx, link); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		suggestion.TagID = &tag.ID
+		suggestion.Status = models.TagSuggestionApproved
+		suggestion.ReviewedByID = &reviewerID
+		suggestion.ReviewedAt = &now
+		return tx.Save(suggestion).Error
+	})
+}
+
+// Reject marks a suggestion as rejected
+func (r *TagRepository) Reject(suggestion *models.TagSuggestion, reviewerID uint) error {
+	now := time.Now()
+	suggestion.Status = models.TagSuggestionRejected
+	suggestion.ReviewedByID = &reviewerID
+	suggestion.ReviewedAt = &now
+	return r.db.Save(suggestion).Error
+}
@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// TrashRepository handles soft-deleted contents, episodes, genres and seasons
+type TrashRepository struct {
+	db *gorm.DB
+}
+
+// NewTrashRepository creates a new TrashRepository
+func NewTrashRepository(db *gorm.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// listDeleted loads a page of soft-deleted rows of a model, most recently deleted first
+func (r *TrashRepository) listDeleted(model, dest interface{}, page, pageSize int) (int64, error) {
+	var total int64
+	query := r.db.Unscoped().Model(model).Where("deleted_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("deleted_at DESC").Offset(offset).Limit(pageSize).Find(dest).Error
+	return total, err
+}
+
+// ListDeletedContents lists soft-deleted contents
+func (r *TrashRepository) ListDeletedContents(page, pageSize int) ([]models.Content, int64, error) {
+	var contents []models.Content
+	total, err := r.listDeleted(&models.Content{}, &contents, page, pageSize)
+	return contents, total, err
+}
+
+// ListDeletedEpisodes lists soft-deleted episodes
+func (r *TrashRepository) ListDeletedEpisodes(page, pageSize int) ([]models.Episode, int64, error) {
+	var episodes []models.Episode
+	total, err := r.listDeleted(&models.Episode{}, &episodes, page, pageSize)
+	return episodes, total, err
+}
+
+// ListDeletedGenres lists soft-deleted genres
+func (r *TrashRepository) ListDeletedGenres(page, pageSize int) ([]models.Genre, int64, error) {
+	var genres []models.Genre
+	total, err := r.listDeleted(&models.Genre{}, &genres, page, pageSize)
+	return genres, total, err
+}
+
+// ListDeletedSeasons lists soft-deleted seasons
+func (r *TrashRepository) ListDeletedSeasons(page, pageSize int) ([]models.Season, int64, error) {
+	var seasons []models.Season
+	total, err := r.listDeleted(&models.Season{}, &seasons, page, pageSize)
+	return seasons, total, err
+}
+
+// ListExpiredIDs lists the IDs of rows of a model that were deleted before the cutoff
+func (r *TrashRepository) ListExpiredIDs(model interface{}, cutoff time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Unscoped().Model(model).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// deletedAt returns the deletion time of a soft-deleted row
+func deletedAt(tx *gorm.DB, model interface{}, id uint) (time.Time, error) {
+	var row struct{ DeletedAt gorm.DeletedAt }
+	if err := tx.Unscoped().Model(model).Select("deleted_at").Where("id = ?", id).Take(&row).Error; err != nil {
+		return time.Time{}, err
+	}
+	if !row.DeletedAt.Valid {
+		return time.Time{}, errors.New("record is not in the trash")
+	}
+	return row.DeletedAt.Time, nil
+}
+
+// undelete clears deleted_at on the rows matched by query
+func undelete(tx *gorm.DB, model interface{}, query string, args ...interface{}) error {
+	return tx.Unscoped().Model(model).Where(query, args...).Update("deleted_at", nil).Error
+}
+
+// RestoreContent restores a content with the episodes and links that were deleted along with it
+func (r *TrashRepository) RestoreContent(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		at, err := deletedAt(tx, &models.Content{}, id)
+		if err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{&models.Episode{}, &models.StreamLink{}, &models.DownloadLink{}} {
+			if err := undelete(tx, model, "content_id = ? AND deleted_at = ?", id, at); err != nil {
+				return err
+			}
+		}
+		return undelete(tx, &models.Content{}, "id = ?", id)
+	})
+}
+
+// RestoreEpisode restores an episode with the links that were deleted along with it.
+// The content has to be live. An absolute number taken in the meantime is replaced.
+func (r *TrashRepository) RestoreEpisode(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		at, err := deletedAt(tx, &models.Episode{}, id)
+		if err != nil {
+			return err
+		}
+
+		var episode models.Episode
+		if err := tx.Unscoped().First(&episode, id).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&models.Content{}, episode.ContentID).Error; err != nil {
+			return errors.New("the content of this episode is deleted, restore it first")
+		}
+
+		for _, model := range []interface{}{&models.StreamLink{}, &models.DownloadLink{}} {
+			if err := undelete(tx, model, "episode_id = ? AND deleted_at = ?", id, at); err != nil {
+				return err
+			}
+		}
+
+		// Another episode may have taken its absolute number in the meantime
+		if episode.AbsoluteNumber != nil {
+			var taken int64
+			err := tx.Model(&models.Episode{}).
+				Where("content_id = ? AND absolute_number = ?", episode.ContentID, *episode.AbsoluteNumber).
+				Count(&taken).Error
+			if err != nil {
+				return err
+			}
+			if taken > 0 {
+				if err := tx.Unscoped().Model(&episode).UpdateColumn("absolute_number", nil).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := undelete(tx, &models.Episode{}, "id = ?", id); err != nil {
+			return err
+		}
+		return numberEpisodes(tx, episode.ContentID)
+	})
+}
+
+// RestoreGenre restores a genre; its content links were never removed
+func (r *TrashRepository) RestoreGenre(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := deletedAt(tx, &models.Genre{}, id); err != nil {
+			return err
+		}
+		return undelete(tx, &models.Genre{}, "id = ?", id)
+	})
+}
+
+// RestoreSeason restores a season
+func (r *TrashRepository) RestoreSeason(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := deletedAt(tx, &models.Season{}, id); err != nil {
+			return err
+		}
+		return undelete(tx, &models.Season{}, "id = ?", id)
+	})
+}
+
+// FindContentMedia lists the stored media paths of a content: its cover, the
+// videos and thumbnails of its episodes and its self-hosted streams
+func (r *TrashRepository) FindContentMedia(id uint) ([]string, error) {
+	var content models.Content
+	if err := r.db.Unscoped().First(&content, id).Error; err != nil {
+		return nil, err
+	}
+
+	var episodes []models.Episode
+	if err := r.db.Unscoped().Where("content_id = ?", id).Find(&episodes).Error; err != nil {
+		return nil, err
+	}
+	var links []models.StreamLink
+	if err := r.db.Unscoped().Where("content_id = ? AND type = ?", id, "self-hosted").Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	paths := []string{content.CoverImage}
+	for _, episode := range episodes {
+		paths = append(paths, episode.VideoPath, episode.ThumbnailURL)
+	}
+	for _, link := range links {
+		paths = append(paths, link.URL)
+	}
+	return paths, nil
+}
+
+// FindEpisodeMedia lists the stored media paths of an episode
+func (r *TrashRepository) FindEpisodeMedia(id uint) ([]string, error) {
+	var episode models.Episode
+	if err := r.db.Unscoped().First(&episode, id).Error; err != nil {
+		return nil, err
+	}
+
+	var links []models.StreamLink
+	err := r.db.Unscoped().
+		Where("episode_id = ? AND type = ? AND deleted_at IS NOT NULL", id, "self-hosted").
+		Find(&links).Error
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{episode.VideoPath, episode.ThumbnailURL}
+	for _, link := range links {
+		paths = append(paths, link.URL)
+	}
+	return paths, nil
+}
+
+// PurgeContent permanently deletes a soft-deleted content and everything that hangs off it
+func (r *TrashRepository) PurgeContent(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := deletedAt(tx, &models.Content{}, id); err != nil {
+			return err
+		}
+
+		children := []interface{}{
+			&models.WatchHistory{},
+			&models.StreamLink{},
+			&models.DownloadLink{},
+			&models.Episode{},
+			&models.EpisodeSchedule{},
+			&models.EpisodeCour{},
+			&models.StoryArc{},
+			&models.Chapter{},
+			&models.ChapterSuggestion{},
+			&models.IntroAnalysis{},
+			&models.ContentTitle{},
+			&models.ContentDescription{},
+			&models.ContentStudio{},
+			&models.ContentStaff{},
+			&models.ContentCharacter{},
+			&models.ContentRevision{},
+			&models.ContentTag{},
+			&models.TagSuggestion{},
+			&models.CollectionItem{},
+			&models.ViewEvent{},
+			&models.ContentViewHourly{},
+			&models.ContentViewDaily{},
+		}
+		for _, model := range children {
+			if err := tx.Unscoped().Where("content_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("content_id = ? OR related_content_id = ?", id, id).Delete(&models.ContentRelation{}).Error; err != nil {
+			return err
+		}
+		for _, table := range []string{"content_genres", "content_categories"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE content_id = ?", id).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("entity_type = ? AND entity_id = ?", models.SlugContent, id).Delete(&models.SlugRedirect{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&models.Content{}, id).Error
+	})
+}
+
+// PurgeEpisode permanently deletes a soft-deleted episode with its deleted links, chapters and watch history
+func (r *TrashRepository) PurgeEpisode(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := deletedAt(tx, &models.Episode{}, id); err != nil {
+			return err
+		}
+
+		var episode models.Episode
+		if err := tx.Unscoped().First(&episode, id).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{&models.StreamLink{}, &models.DownloadLink{}} {
+			err := tx.Unscoped().Where("episode_id = ? AND deleted_at IS NOT NULL", id).Delete(model).Error
+			if err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().Where("episode_id = ?", id).Delete(&models.WatchHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("episode_id = ?", id).Delete(&models.Chapter{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("episode_id = ?", id).Delete(&models.ChapterSuggestion{}).Error; err != nil {
+			return err
+		}
+		err := tx.Where("entity_type = ? AND entity_id = ?", models.RevisionEntityEpisode, id).
+			Delete(&models.ContentRevision{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&models.Episode{}, id).Error
+	})
+}
+
+// PurgeGenre permanently deletes a soft-deleted genre and its content links
+func (r *TrashRepository) PurgeGenre(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := deletedAt(tx, &models.Genre{}, id); err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM content_genres WHERE genre_id = ?", id).Error; err != nil {
+			return err
+		}
+		// A genre rail without its genre would silently turn into an overall one
+		if err := tx.Where("genre_id = ?", id).Delete(&models.HomeRail{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entity_type = ? AND entity_id = ?", models.SlugGenre, id).Delete(&models.SlugRedirect{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Genre{}, id).Error
+	})
+}
+
+// PurgeSeason permanently deletes a soft-deleted season and detaches its contents
+func (r *TrashRepository) PurgeSeason(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := deletedAt(tx, &models.Season{}, id); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Content{}).Where("season_id = ?", id).Update("season_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entity_type = ? AND entity_id = ?", models.SlugSeason, id).Delete(&models.SlugRedirect{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Season{}, id).Error
+	})
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository handles database operations for external login identities
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository creates a new UserIdentityRepository
+func NewUserIdentityRepository(db *gorm.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+// Create creates a new identity
+func (r *UserIdentityRepository) Create(identity *models.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// FindByProviderSubject finds an identity by provider name and subject identifier
+func (r *UserIdentityRepository) FindByProviderSubject(provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// ListByUserID lists all identities linked to a user
+func (r *UserIdentityRepository) ListByUserID(userID uint) ([]models.UserIdentity, error) {
+	var identities []models.UserIdentity
+	err := r.db.Where("user_id = ?", userID).Order("provider").Find(&identities).Error
+	return identities, err
+}
+
+// Delete deletes an identity
+func (r *UserIdentityRepository) Delete(id uint) error {
+	return r.db.Delete(&models.UserIdentity{}, id).Error
+}
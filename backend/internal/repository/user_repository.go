@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/username/anime-streaming/internal/models"
 	"gorm.io/gorm"
 )
@@ -52,11 +54,73 @@ func (r *UserRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+// RecordPINFailure counts a wrong parental PIN. The failure that reaches
+// maxFailures locks the PIN until lockedUntil and starts the count over.
+func (r *UserRepository) RecordPINFailure(id uint, maxFailures int, lockedUntil time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"parental_pin_locked_until": gorm.Expr("CASE WHEN parental_pin_failures + 1 >= ? THEN ?::timestamptz ELSE parental_pin_locked_until END", maxFailures, lockedUntil),
+		"parental_pin_failures":     gorm.Expr("CASE WHEN parental_pin_failures + 1 >= ? THEN 0 ELSE parental_pin_failures + 1 END", maxFailures),
+	}).Error
+}
+
+// ResetPINFailures forgets the wrong parental PINs entered so far
+func (r *UserRepository) ResetPINFailures(id uint) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"parental_pin_failures": 0, "parental_pin_locked_until": nil}).Error
+}
+
 // Delete deletes a user
 func (r *UserRepository) Delete(id uint) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
 
+// FindByCalendarToken finds a user by the secret of their calendar feed
+func (r *UserRepository) FindByCalendarToken(token string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("calendar_token = ? AND calendar_token <> ''", token).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListDueForDeletion lists users whose deletion grace period has ended
+func (r *UserRepository) ListDueForDeletion(now time.Time) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).Find(&users).Error
+	return users, err
+}
+
+// Purge saves the anonymized user, hard-deletes all personal data that belongs to it
+// and finally soft-deletes the user row, all in one transaction
+func (r *UserRepository) Purge(user *models.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		personalData := []interface{}{
+			&models.WatchHistory{},
+			&models.Profile{},
+			&models.UserIdentity{},
+			&models.APIKey{},
+			&models.DataExport{},
+			&models.TagSuggestion{},
+		}
+		for _, model := range personalData {
+			if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// Suggestions of others the user moderated stay, without naming the moderator
+		if err := tx.Model(&models.TagSuggestion{}).Where("reviewed_by_id = ?", user.ID).
+			UpdateColumn("reviewed_by_id", nil).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		return tx.Delete(user).Error
+	})
+}
+
 // List lists all users with pagination
 func (r *UserRepository) List(page, pageSize int) ([]models.User, int64, error) {
 	var users []models.User
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// updateVersioned writes every column of a record only if its version column
+// still holds *version, and bumps the version on success. Associations are
+// left alone; they are managed through their own calls.
+func updateVersioned(db *gorm.DB, record interface{}, version *uint) error {
+	expected := *version
+	*version = expected + 1
+
+	result := db.Model(record).
+		Where("version = ?", expected).
+		Select("*").
+		Omit(clause.Associations, "created_at").
+		Updates(record)
+	if result.Error != nil {
+		*version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		*version = expected
+		return models.ErrVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// popularityWindow is how far back the popularity sort of content lists looks
+const popularityWindow = 7 * 24 * time.Hour
+
+// ViewRepository handles database operations for view events and their rollups
+type ViewRepository struct {
+	db *gorm.DB
+}
+
+// NewViewRepository creates a new ViewRepository
+func NewViewRepository(db *gorm.DB) *ViewRepository {
+	return &ViewRepository{db: db}
+}
+
+// byPopularity orders a query on the contents table by the views of the last week
+func byPopularity(since time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		views := db.Session(&gorm.Session{NewDB: true}).
+			Model(&models.ContentViewHourly{}).
+			Select("content_id, SUM(views) AS views").
+			Where("hour >= ?", since).
+			Group("content_id")
+		return db.
+			Joins("LEFT JOIN (?) AS popularity ON popularity.content_id = contents.id", views).
+			Order("COALESCE(popularity.views, 0) DESC, contents.id DESC")
+	}
+}
+
+// Record stores a view unless the viewer already has one for the same title,
+// episode and window. It reports whether a new view was stored.
+func (r *ViewRepository) Record(event *models.ViewEvent) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RollUp adds the views not counted yet to the hourly and daily counters.
+// The events are claimed and counted in a single statement, so an event
+// committed while the roll-up runs is either counted now or left for the
+// next one, never marked without being counted.
+func (r *ViewRepository) RollUp() (int64, error) {
+	var rolled int64
+	err := r.db.Raw(`WITH claimed AS (
+			UPDATE view_events SET rolled_up = true WHERE rolled_up = false
+			RETURNING content_id, created_at
+		), hourly AS (
+			INSERT INTO content_views_hourly (content_id, hour, views)
+			SELECT content_id, date_trunc('hour', created_at), COUNT(*) FROM claimed GROUP BY 1, 2
+			ON CONFLICT (content_id, hour) DO UPDATE SET views = content_views_hourly.views + EXCLUDED.views
+		), daily AS (
+			INSERT INTO content_views_daily (content_id, day, views)
+			SELECT content_id, CAST(created_at AS date), COUNT(*) FROM claimed GROUP BY 1, 2
+			ON CONFLICT (content_id, day) DO UPDATE SET views = content_views_daily.views + EXCLUDED.views
+		)
+		SELECT COUNT(*) FROM claimed`).Scan(&rolled).Error
+	return rolled, err
+}
+
+// Prune deletes counted events that can no longer collide with a new view,
+// and counters older than the rankings need
+func (r *ViewRepository) Prune(eventsBefore, hourlyBefore, dailyBefore time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rolled_up = ? AND bucket < ?", true, eventsBefore).Delete(&models.ViewEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("hour < ?", hourlyBefore).Delete(&models.ContentViewHourly{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("day < ?", dailyBefore).Delete(&models.ContentViewDaily{}).Error
+	})
+}
+
+// viewCount is a title with its number of views
+type viewCount struct {
+	ContentID uint
+	Views     int64
+}
+
+// ListTrending ranks the titles the viewer may see by their views since the
+// given time. Short windows are read from the hourly counters, long ones
+// from the daily counters.
+func (r *ViewRepository) ListTrending(viewer *models.Viewer, since time.Time, daily bool, limit int, preload ...string) ([]models.TrendingContent, error) {
+	table, column := "content_views_hourly", "hour"
+	if daily {
+		table, column = "content_views_daily", "day"
+	}
+
+	var counts []viewCount
+	err := r.db.Table(table+" AS v").
+		Select("v.content_id, SUM(v.views) AS views").
+		Joins("JOIN contents ON contents.id = v.content_id AND contents.deleted_at IS NULL").
+		Scopes(listedTo(viewer)).
+		Where("v."+column+" >= ?", since).
+		Group("v.content_id").
+		Order("views DESC, v.content_id DESC").
+		Limit(limit).
+		Scan(&counts).Error
+	if err != nil || len(counts) == 0 {
+		return []models.TrendingContent{}, err
+	}
+
+	ids := make([]uint, 0, len(counts))
+	for _, count := range counts {
+		ids = append(ids, count.ContentID)
+	}
+	var contents []models.Content
+	if err := preloadFor(viewer, r.db.Where("id IN ?", ids), preload).Find(&contents).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Content, len(contents))
+	for _, content := range contents {
+		byID[content.ID] = content
+	}
+
+	trending := make([]models.TrendingContent, 0, len(counts))
+	for _, count := range counts {
+		if content, ok := byID[count.ContentID]; ok {
+			trending = append(trending, models.TrendingContent{Content: content, Views: count.Views})
+		}
+	}
+	return trending, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/gorm"
+)
+
+// visibleTo restricts a query on the contents table to titles the viewer may open:
+// within their maturity limit and, for non-staff, published or unlisted.
+// A nil viewer is used for internal and admin queries and is never restricted.
+func visibleTo(viewer *models.Viewer) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if viewer.IsRestricted() {
+			ratings := make([]string, 0, len(viewer.AllowedRatings))
+			for _, rating := range viewer.AllowedRatings {
+				ratings = append(ratings, string(rating))
+			}
+			db = db.Where("contents.maturity_rating IN ?", ratings)
+		}
+		if !viewer.IsStaff() {
+			db = db.Where("contents.status IN ?", models.LiveStatuses)
+		}
+		return db
+	}
+}
+
+// listedTo is visibleTo for lists and search, which leave out unlisted titles
+func listedTo(viewer *models.Viewer) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(visibleTo(viewer))
+		if !viewer.IsStaff() {
+			db = db.Where("contents.status = ?", models.PublishPublished)
+		}
+		return db
+	}
+}
+
+// contentVisibleTo restricts a query on a table with a content_id column
+// (episodes, links, ...) to rows whose content the viewer may see
+func contentVisibleTo(viewer *models.Viewer) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !viewer.IsRestricted() && viewer.IsStaff() {
+			return db
+		}
+		visible := db.Session(&gorm.Session{NewDB: true}).
+			Model(&models.Content{}).
+			Scopes(visibleTo(viewer)).
+			Select("contents.id")
+		return db.Where("content_id IN (?)", visible)
+	}
+}
+
+// episodeVisibleTo restricts a query on the episodes table to episodes the viewer may open
+func episodeVisibleTo(viewer *models.Viewer) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(contentVisibleTo(viewer))
+		if !viewer.IsStaff() {
+			db = db.Where("episodes.status IN ?", models.LiveStatuses)
+		}
+		return db
+	}
+}
+
+// episodeListedTo is episodeVisibleTo for episode lists, which leave out unlisted episodes
+func episodeListedTo(viewer *models.Viewer) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(episodeVisibleTo(viewer))
+		if !viewer.IsStaff() {
+			db = db.Where("episodes.status = ?", models.PublishPublished)
+		}
+		return db
+	}
+}
+
+// linkVisibleTo orders stream or download links by priority and hides dead
+// and disabled links and the links of episodes that are not live yet
+func linkVisibleTo(viewer *models.Viewer, table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Order(table + ".priority, " + table + ".id")
+		if viewer.IsStaff() {
+			return db
+		}
+		return db.Where(table+".enabled AND "+table+".health_status <> ?", models.LinkHealthDead).
+			Where("NOT EXISTS (SELECT 1 FROM episodes e WHERE e.id = "+table+".episode_id"+
+				" AND e.deleted_at IS NULL AND e.status NOT IN ?)", models.LiveStatuses)
+	}
+}
+
+// titleLinks keeps the links of the title itself, leaving out episode links
+func titleLinks(table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(table + ".episode_id IS NULL")
+	}
+}
+
+// withLinks loads the stream and download links of episodes the viewer may use
+func withLinks(viewer *models.Viewer) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload("StreamLinks", linkVisibleTo(viewer, "stream_links")).
+			Preload("DownloadLinks", linkVisibleTo(viewer, "download_links"))
+	}
+}
+
+// preloadFor preloads content relations, applying the viewer's visibility to
+// episodes and their links so unpublished episodes do not leak through a title
+func preloadFor(viewer *models.Viewer, query *gorm.DB, relations []string) *gorm.DB {
+	for _, relation := range relations {
+		switch relation {
+		case "Episodes":
+			query = query.Preload(relation, episodeListedTo(viewer)).Preload("Episodes.Chapters", func(db *gorm.DB) *gorm.DB {
+				return db.Order("chapters.start")
+			}).
+				Preload("Episodes.StreamLinks", linkVisibleTo(viewer, "stream_links")).
+				Preload("Episodes.DownloadLinks", linkVisibleTo(viewer, "download_links"))
+		case "StreamLinks":
+			query = query.Preload(relation, titleLinks("stream_links"), linkVisibleTo(viewer, "stream_links"))
+		case "DownloadLinks":
+			query = query.Preload(relation, titleLinks("download_links"), linkVisibleTo(viewer, "download_links"))
+		case "Tags":
+			query = query.Preload(relation, func(db *gorm.DB) *gorm.DB {
+				return db.Order("content_tags.weight DESC")
+			}).Preload("Tags.Tag")
+		default:
+			query = query.Preload(relation)
+		}
+	}
+	return query
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/username/anime-streaming/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds postgres queries without a database to run them against
+func dryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+// contentQuery returns the SQL and arguments of a content query under a viewer scope
+func contentQuery(t *testing.T, scope func(db *gorm.DB) *gorm.DB) (string, []interface{}) {
+	var contents []models.Content
+	stmt := dryRunDB(t).Scopes(scope).Find(&contents).Statement
+	return stmt.SQL.String(), stmt.Vars
+}
+
+// ratingsIn returns the ratings a query lets through, or nil when it has no maturity condition
+func ratingsIn(sql string, vars []interface{}) []string {
+	if !strings.Contains(sql, "contents.maturity_rating IN") {
+		return nil
+	}
+	ratings := []string{}
+	for _, v := range vars {
+		if rating, ok := v.(string); ok {
+			ratings = append(ratings, rating)
+		}
+	}
+	return ratings
+}
+
+func TestViewerScopes(t *testing.T) {
+	profileID := uint(7)
+	scheme := models.NewMaturityScheme(models.DefaultMaturityRatings)
+
+	tests := []struct {
+		name    string
+		viewer  *models.Viewer
+		ratings []string // nil when the viewer sees every rating
+		staff   bool     // staff also see drafts, scheduled and unlisted titles
+	}{
+		// Guests are unrestricted unless a guest limit is configured
+		{name: "guest", viewer: &models.Viewer{}},
+		{name: "guest with a limit", viewer: &models.Viewer{AllowedRatings: scheme.AllowedUpTo("PG-13")}, ratings: []string{"G", "PG", "PG-13"}},
+		{name: "kids profile", viewer: &models.Viewer{UserID: 3, ProfileID: &profileID, AllowedRatings: scheme.AllowedUpTo(scheme.Lowest())}, ratings: []string{"G"}},
+		{name: "profile with a limit outside the scheme", viewer: &models.Viewer{UserID: 3, ProfileID: &profileID, AllowedRatings: scheme.AllowedUpTo("NC-17")}, ratings: []string{}},
+		{name: "user without a limit", viewer: &models.Viewer{UserID: 3, Role: models.RoleUser}},
+		{name: "staff", viewer: &models.Viewer{UserID: 1, Role: models.RoleAdmin}, staff: true},
+		{name: "internal", viewer: nil, staff: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars := contentQuery(t, visibleTo(tt.viewer))
+			assert.Equal(t, tt.ratings, ratingsIn(sql, vars))
+			if tt.staff {
+				assert.NotContains(t, sql, "contents.status")
+			} else {
+				assert.Contains(t, sql, "contents.status IN")
+				assert.Contains(t, vars, models.PublishUnlisted, "unlisted titles open by link")
+				assert.NotContains(t, vars, models.PublishDraft)
+			}
+
+			sql, vars = contentQuery(t, listedTo(tt.viewer))
+			assert.Equal(t, tt.ratings, ratingsIn(sql, vars))
+			if tt.staff {
+				assert.NotContains(t, sql, "contents.status")
+			} else {
+				assert.Contains(t, sql, "contents.status = ", "lists only show published titles")
+			}
+		})
+	}
+}
@@ -33,9 +33,10 @@ func (r *WatchHistoryRepository) FindByID(id uint) (*models.WatchHistory, error)
 }
 
 // FindByUserAndContent finds a watch history record by user ID and content ID
-func (r *WatchHistoryRepository) FindByUserAndContent(userID, contentID uint) (*models.WatchHistory, error) {
+func (r *WatchHistoryRepository) FindByUserAndContent(userID uint, profileID *uint, contentID uint) (*models.WatchHistory, error) {
 	var history models.WatchHistory
-	err := r.db.Where("user_id = ? AND content_id = ?", userID, contentID).
+	err := r.db.Scopes(byViewerProfile(userID, profileID)).
+		Where("content_id = ?", contentID).
 		Order("watched_at DESC").
 		First(&history).Error
 	
@@ -47,9 +48,10 @@ func (r *WatchHistoryRepository) FindByUserAndContent(userID, contentID uint) (*
 }
 
 // FindByUserAndEpisode finds a watch history record by user ID and episode ID
-func (r *WatchHistoryRepository) FindByUserAndEpisode(userID uint, episodeID uint) (*models.WatchHistory, error) {
+func (r *WatchHistoryRepository) FindByUserAndEpisode(userID uint, profileID *uint, episodeID uint) (*models.WatchHistory, error) {
 	var history models.WatchHistory
-	err := r.db.Where("user_id = ? AND episode_id = ?", userID, episodeID).
+	err := r.db.Scopes(byViewerProfile(userID, profileID)).
+		Where("episode_id = ?", episodeID).
 		Order("watched_at DESC").
 		First(&history).Error
 	
@@ -72,18 +74,18 @@ func (r *WatchHistoryRepository) Delete(id uint) error {
 }
 
 // GetUserHistory gets a user's watch history with pagination
-func (r *WatchHistoryRepository) GetUserHistory(userID uint, page, pageSize int) ([]models.WatchHistory, int64, error) {
+func (r *WatchHistoryRepository) GetUserHistory(userID uint, profileID *uint, page, pageSize int) ([]models.WatchHistory, int64, error) {
 	var histories []models.WatchHistory
 	var count int64
 	
 	if err := r.db.Model(&models.WatchHistory{}).
-		Where("user_id = ?", userID).
+		Scopes(byViewerProfile(userID, profileID)).
 		Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
 	
 	offset := (page - 1) * pageSize
-	err := r.db.Where("user_id = ?", userID).
+	err := r.db.Scopes(byViewerProfile(userID, profileID)).
 		Preload("Content").
 		Preload("Episode").
 		Order("watched_at DESC").
@@ -94,11 +96,29 @@ func (r *WatchHistoryRepository) GetUserHistory(userID uint, page, pageSize int)
 	return histories, count, err
 }
 
+// ListAllByUserID lists every watch history record of a user across all profiles
+func (r *WatchHistoryRepository) ListAllByUserID(userID uint) ([]models.WatchHistory, error) {
+	var histories []models.WatchHistory
+	err := r.db.Where("user_id = ?", userID).Order("watched_at DESC").Find(&histories).Error
+	return histories, err
+}
+
+// ListContentIDsByUserID lists the distinct titles a user has watched on any profile
+func (r *WatchHistoryRepository) ListContentIDsByUserID(userID uint) ([]uint, error) {
+	var contentIDs []uint
+	err := r.db.Model(&models.WatchHistory{}).
+		Where("user_id = ?", userID).
+		Distinct().
+		Pluck("content_id", &contentIDs).Error
+	return contentIDs, err
+}
+
 // GetContinueWatching gets content that a user has started but not completed
-func (r *WatchHistoryRepository) GetContinueWatching(userID uint, limit int) ([]models.WatchHistory, error) {
+func (r *WatchHistoryRepository) GetContinueWatching(userID uint, profileID *uint, limit int) ([]models.WatchHistory, error) {
 	var histories []models.WatchHistory
 	
-	err := r.db.Where("user_id = ? AND completed_watch = false", userID).
+	err := r.db.Scopes(byViewerProfile(userID, profileID)).
+		Where("completed_watch = false").
 		Preload("Content").
 		Preload("Episode").
 		Order("watched_at DESC").
@@ -109,11 +129,11 @@ func (r *WatchHistoryRepository) GetContinueWatching(userID uint, limit int) ([]
 }
 
 // UpdateProgress updates the watch progress for a movie or episode
-func (r *WatchHistoryRepository) UpdateProgress(userID, contentID uint, episodeID *uint, progress int, completed bool) error {
+func (r *WatchHistoryRepository) UpdateProgress(userID uint, profileID *uint, contentID uint, episodeID *uint, progress int, completed bool) error {
 	var history models.WatchHistory
 	
 	// Try to find an existing record
-	query := r.db.Where("user_id = ? AND content_id = ?", userID, contentID)
+	query := r.db.Scopes(byViewerProfile(userID, profileID)).Where("content_id = ?", contentID)
 	if episodeID != nil {
 		query = query.Where("episode_id = ?", *episodeID)
 	} else {
@@ -126,6 +146,7 @@ func (r *WatchHistoryRepository) UpdateProgress(userID, contentID uint, episodeI
 		// Create a new record
 		history = models.WatchHistory{
 			UserID:         userID,
+			ProfileID:      profileID,
 			ContentID:      contentID,
 			EpisodeID:      episodeID,
 			WatchProgress:  progress,
@@ -143,4 +164,16 @@ func (r *WatchHistoryRepository) UpdateProgress(userID, contentID uint, episodeI
 	history.WatchedAt = time.Now()
 	
 	return r.Update(&history)
-} 
\ No newline at end of file
+}
+
+// byViewerProfile scopes watch history to a user and one of their profiles.
+// Requests without a selected profile see the account-level history.
+func byViewerProfile(userID uint, profileID *uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("user_id = ?", userID)
+		if profileID != nil {
+			return db.Where("profile_id = ?", *profileID)
+		}
+		return db.Where("profile_id IS NULL")
+	}
+}
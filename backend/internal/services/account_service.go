@@ -0,0 +1,350 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dataExportTTL is how long a finished export stays available for download
+const dataExportTTL = 7 * 24 * time.Hour
+
+// AccountService handles personal data exports and account deletion
+type AccountService struct {
+	userRepo         *repository.UserRepository
+	watchHistoryRepo *repository.WatchHistoryRepository
+	profileRepo      *repository.ProfileRepository
+	identityRepo     *repository.UserIdentityRepository
+	apiKeyRepo       *repository.APIKeyRepository
+	tagRepo          *repository.TagRepository
+	exportRepo       *repository.DataExportRepository
+	exportPath       string
+	deletionGrace    time.Duration
+}
+
+// NewAccountService creates a new AccountService
+func NewAccountService(
+	userRepo *repository.UserRepository,
+	watchHistoryRepo *repository.WatchHistoryRepository,
+	profileRepo *repository.ProfileRepository,
+	identityRepo *repository.UserIdentityRepository,
+	apiKeyRepo *repository.APIKeyRepository,
+	tagRepo *repository.TagRepository,
+	exportRepo *repository.DataExportRepository,
+	exportPath string,
+	deletionGrace time.Duration,
+) *AccountService {
+	return &AccountService{
+		userRepo:         userRepo,
+		watchHistoryRepo: watchHistoryRepo,
+		profileRepo:      profileRepo,
+		identityRepo:     identityRepo,
+		apiKeyRepo:       apiKeyRepo,
+		tagRepo:          tagRepo,
+		exportRepo:       exportRepo,
+		exportPath:       exportPath,
+		deletionGrace:    deletionGrace,
+	}
+}
+
+// RequestExport queues a personal data export; the ZIP is built in the background
+func (s *AccountService) RequestExport(userID uint) (*models.DataExport, error) {
+	export := &models.DataExport{
+		UserID: userID,
+		Status: models.DataExportPending,
+	}
+	if err := s.exportRepo.Create(export); err != nil {
+		return nil, err
+	}
+
+	go s.runExport(export.ID)
+
+	return export, nil
+}
+
+// GetExport retrieves an export job owned by the user
+func (s *AccountService) GetExport(userID, exportID uint) (*models.DataExport, error) {
+	export, err := s.exportRepo.FindByID(exportID)
+	if err != nil || export.UserID != userID {
+		return nil, errors.New("export not found")
+	}
+	return export, nil
+}
+
+// ListExports lists the export jobs of a user
+func (s *AccountService) ListExports(userID uint) ([]models.DataExport, error) {
+	return s.exportRepo.ListByUserID(userID)
+}
+
+// GetExportFile returns the path of a finished export ready for download
+func (s *AccountService) GetExportFile(userID, exportID uint) (string, error) {
+	export, err := s.GetExport(userID, exportID)
+	if err != nil {
+		return "", err
+	}
+	if export.Status != models.DataExportCompleted {
+		return "", errors.New("export is not ready")
+	}
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		return "", errors.New("export has expired")
+	}
+	return export.FilePath, nil
+}
+
+// runExport builds the ZIP archive for an export job and records the outcome
+func (s *AccountService) runExport(exportID uint) {
+	export, err := s.exportRepo.FindByID(exportID)
+	if err != nil {
+		log.Printf("Data export %d not found: %v", exportID, err)
+		return
+	}
+
+	export.Status = models.DataExportProcessing
+	if err := s.exportRepo.Update(export); err != nil {
+		log.Printf("Failed to update data export %d: %v", exportID, err)
+		return
+	}
+
+	path, err := s.buildExport(export)
+	if err != nil {
+		log.Printf("Data export %d failed: %v", exportID, err)
+		export.Status = models.DataExportFailed
+		export.Error = "failed to build export"
+	} else {
+		now := time.Now()
+		expiresAt := now.Add(dataExportTTL)
+		export.Status = models.DataExportCompleted
+		export.FilePath = path
+		export.CompletedAt = &now
+		export.ExpiresAt = &expiresAt
+	}
+
+	if err := s.exportRepo.Update(export); err != nil {
+		log.Printf("Failed to update data export %d: %v", exportID, err)
+	}
+}
+
+// buildExport writes every piece of personal data we hold for the user into a ZIP file
+func (s *AccountService) buildExport(export *models.DataExport) (string, error) {
+	user, err := s.userRepo.FindByID(export.UserID)
+	if err != nil {
+		return "", err
+	}
+	profiles, err := s.profileRepo.ListByUserID(user.ID)
+	if err != nil {
+		return "", err
+	}
+	history, err := s.watchHistoryRepo.ListAllByUserID(user.ID)
+	if err != nil {
+		return "", err
+	}
+	identities, err := s.identityRepo.ListByUserID(user.ID)
+	if err != nil {
+		return "", err
+	}
+	apiKeys, err := s.apiKeyRepo.ListByUserID(user.ID)
+	if err != nil {
+		return "", err
+	}
+	tagSuggestions, err := s.tagRepo.ListSuggestionsByUserID(user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(s.exportPath, 0700); err != nil {
+		return "", err
+	}
+
+	token, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.exportPath, fmt.Sprintf("export_%d_%d_%s.zip", user.ID, export.ID, token))
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	entries := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", user},
+		{"viewer_profiles.json", profiles},
+		{"watch_history.json", history},
+		{"linked_accounts.json", identities},
+		{"api_keys.json", apiKeys},
+		{"tag_suggestions.json", tagSuggestions},
+	}
+	for _, entry := range entries {
+		w, err := archive.Create(entry.name)
+		if err != nil {
+			os.Remove(path)
+			return "", err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(entry.data); err != nil {
+			os.Remove(path)
+			return "", err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// RequestDeletion schedules the account for deletion after the grace period.
+// API keys are revoked right away so automated access stops immediately.
+func (s *AccountService) RequestDeletion(userID uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletionScheduledAt != nil {
+		return user, nil
+	}
+
+	scheduledAt := time.Now().Add(s.deletionGrace)
+	user.DeletionScheduledAt = &scheduledAt
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.apiKeyRepo.RevokeAllByUserID(userID); err != nil {
+		log.Printf("Failed to revoke API keys of user %d: %v", userID, err)
+	}
+
+	return user, nil
+}
+
+// CancelDeletion keeps an account that is still within its grace period
+func (s *AccountService) CancelDeletion(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.DeletionScheduledAt == nil {
+		return errors.New("account is not scheduled for deletion")
+	}
+
+	user.DeletionScheduledAt = nil
+	return s.userRepo.Update(user)
+}
+
+// PurgeDueAccounts anonymizes every account whose grace period has ended and
+// removes the personal data attached to it
+func (s *AccountService) PurgeDueAccounts() (int, error) {
+	users, err := s.userRepo.ListDueForDeletion(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range users {
+		if err := s.purgeAccount(&users[i]); err != nil {
+			log.Printf("Failed to purge user %d: %v", users[i].ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// purgeAccount anonymizes the user row and deletes everything linked to it
+func (s *AccountService) purgeAccount(user *models.User) error {
+	exports, err := s.exportRepo.ListByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		s.removeExportFile(&export)
+	}
+
+	// The row is kept (soft-deleted) so foreign keys stay valid, but nothing in it identifies the person
+	secret, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.Username = fmt.Sprintf("deleted-user-%d", user.ID)
+	user.Email = fmt.Sprintf("deleted-user-%d@deleted.invalid", user.ID)
+	user.Password = string(hashedPassword)
+	user.ParentalPIN = ""
+	user.CalendarToken = ""
+	user.LastLogin = nil
+	user.DeletionScheduledAt = nil
+
+	if err := s.userRepo.Purge(user); err != nil {
+		return err
+	}
+
+	log.Printf("Purged account %d", user.ID)
+	return nil
+}
+
+// CleanupExpiredExports deletes export archives whose download window has passed
+func (s *AccountService) CleanupExpiredExports() error {
+	exports, err := s.exportRepo.ListExpired(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		s.removeExportFile(&export)
+		if err := s.exportRepo.Delete(export.ID); err != nil {
+			log.Printf("Failed to delete data export %d: %v", export.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *AccountService) removeExportFile(export *models.DataExport) {
+	if export.FilePath == "" {
+		return
+	}
+	if err := os.Remove(export.FilePath); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to remove export file %s: %v", export.FilePath, err)
+	}
+}
+
+// StartScheduler periodically purges accounts past their grace period and expired exports
+func (s *AccountService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if purged, err := s.PurgeDueAccounts(); err != nil {
+				log.Printf("Account purge failed: %v", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d accounts past their deletion grace period", purged)
+			}
+			if err := s.CleanupExpiredExports(); err != nil {
+				log.Printf("Export cleanup failed: %v", err)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
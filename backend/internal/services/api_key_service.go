@@ -0,0 +1,213 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// apiKeyTokenPrefix marks PortalAnime API keys so they are easy to spot in leaks
+	apiKeyTokenPrefix = "pa_"
+	// apiKeyTouchInterval limits how often last-used timestamps are written
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyService handles business logic for API keys and service accounts
+type APIKeyService struct {
+	apiKeyRepo *repository.APIKeyRepository
+	userRepo   *repository.UserRepository
+}
+
+// NewAPIKeyService creates a new APIKeyService
+func NewAPIKeyService(apiKeyRepo *repository.APIKeyRepository, userRepo *repository.UserRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// CreateKey creates a new API key for a user and returns the plaintext key, which is shown only once
+func (s *APIKeyService) CreateKey(userID uint, name string, scopes []models.APIKeyScope, expiresAt *time.Time) (*models.APIKey, string, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, "", fmt.Errorf("user not found: %v", err)
+	}
+
+	if len(scopes) == 0 {
+		scopes = []models.APIKeyScope{models.APIKeyScopeRead}
+	}
+	scopeNames := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !isValidAPIKeyScope(scope) {
+			return nil, "", fmt.Errorf("invalid scope: %s", scope)
+		}
+		scopeNames = append(scopeNames, string(scope))
+	}
+
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return nil, "", errors.New("expiry must be in the future")
+	}
+
+	prefix, err := randomHex(6)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	plaintext := apiKeyTokenPrefix + prefix + "_" + secret
+	key := &models.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashAPIKey(plaintext),
+		Scopes:    strings.Join(scopeNames, ","),
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, "", err
+	}
+
+	log.Printf("Created API key %s for user %d with scopes %s", prefix, userID, key.Scopes)
+	return key, plaintext, nil
+}
+
+// ListKeys lists API keys owned by a user
+func (s *APIKeyService) ListKeys(userID uint) ([]models.APIKey, error) {
+	return s.apiKeyRepo.ListByUserID(userID)
+}
+
+// RevokeKey revokes an API key owned by the given user
+func (s *APIKeyService) RevokeKey(userID, keyID uint) error {
+	key, err := s.apiKeyRepo.FindByID(keyID)
+	if err != nil || key.UserID != userID {
+		return errors.New("api key not found")
+	}
+	return s.apiKeyRepo.Revoke(keyID)
+}
+
+// ValidateKey checks a plaintext API key and returns the user ID and the role the key may act as
+func (s *APIKeyService) ValidateKey(plaintext string, method string) (uint, models.Role, error) {
+	if !strings.HasPrefix(plaintext, apiKeyTokenPrefix) {
+		return 0, "", errors.New("invalid api key")
+	}
+	parts := strings.SplitN(strings.TrimPrefix(plaintext, apiKeyTokenPrefix), "_", 2)
+	if len(parts) != 2 {
+		return 0, "", errors.New("invalid api key")
+	}
+
+	key, err := s.apiKeyRepo.FindByPrefix(parts[0])
+	if err != nil {
+		return 0, "", errors.New("invalid api key")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashAPIKey(plaintext))) != 1 {
+		return 0, "", errors.New("invalid api key")
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return 0, "", errors.New("api key is revoked or expired")
+	}
+	if key.User == nil {
+		return 0, "", errors.New("api key owner not found")
+	}
+
+	// Read-only keys may only perform safe requests
+	if method != "GET" && method != "HEAD" && method != "OPTIONS" && !key.HasScope(models.APIKeyScopeWrite) {
+		return 0, "", errors.New("api key does not have write scope")
+	}
+
+	// Without the admin scope a key never carries more than user privileges
+	role := models.RoleUser
+	if key.HasScope(models.APIKeyScopeAdmin) {
+		role = key.User.Role
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.apiKeyRepo.TouchLastUsed(key.ID, now); err != nil {
+			log.Printf("Failed to update last used time for API key %s: %v", key.Prefix, err)
+		}
+	}
+
+	return key.UserID, role, nil
+}
+
+// CreateServiceAccount creates a non-human account that can only authenticate with API keys
+func (s *APIKeyService) CreateServiceAccount(username string, role models.Role) (*models.User, error) {
+	if role != models.RoleAdmin && role != models.RoleUser {
+		return nil, errors.New("invalid role")
+	}
+
+	if _, err := s.userRepo.FindByUsername(username); err == nil {
+		return nil, errors.New("username already exists")
+	}
+
+	// Service accounts get an unusable random password
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username:         username,
+		Email:            username + "@service.local",
+		Password:         string(hashedPassword),
+		Role:             role,
+		IsServiceAccount: true,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetServiceAccount retrieves a service account by ID
+func (s *APIKeyService) GetServiceAccount(id uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil || !user.IsServiceAccount {
+		return nil, errors.New("service account not found")
+	}
+	return user, nil
+}
+
+// hashAPIKey hashes a plaintext key; keys are high-entropy so a fast hash is sufficient
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomHex returns size random bytes encoded as hex
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func isValidAPIKeyScope(scope models.APIKeyScope) bool {
+	switch scope {
+	case models.APIKeyScopeRead, models.APIKeyScopeWrite, models.APIKeyScopeAdmin:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+// ErrInvalidCategory is wrapped by the errors for categories that cannot be saved as given
+var ErrInvalidCategory = errors.New("invalid category")
+
+// CategoryInUseError is returned when a category that titles still belong to is deleted
+type CategoryInUseError struct {
+	Titles int64
+}
+
+func (e *CategoryInUseError) Error() string {
+	return fmt.Sprintf("Category is still used by %d titles", e.Titles)
+}
+
+// CategoryService handles business logic for categories
+type CategoryService struct {
+	categoryRepo *repository.CategoryRepository
+	slugService  *SlugService
+}
+
+// NewCategoryService creates a new CategoryService
+func NewCategoryService(categoryRepo *repository.CategoryRepository, slugService *SlugService) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+		slugService:  slugService,
+	}
+}
+
+// ListCategories lists all categories in display order
+func (s *CategoryService) ListCategories() ([]models.Category, error) {
+	return s.categoryRepo.List()
+}
+
+// GetCategory retrieves a category with its direct subcategories
+func (s *CategoryService) GetCategory(id uint) (*models.Category, error) {
+	return s.categoryRepo.FindByID(id)
+}
+
+// CreateCategory creates a new category
+func (s *CategoryService) CreateCategory(category *models.Category) error {
+	category.ID = 0
+	if err := s.checkCategory(category); err != nil {
+		return err
+	}
+	slug, _, err := s.slugService.Pick(models.SlugCategory, 0, category.Slug, category.Name)
+	if err != nil {
+		return err
+	}
+	category.Slug = slug
+	return s.categoryRepo.Create(category)
+}
+
+// UpdateCategory updates a category if it is still at the version it was read at
+func (s *CategoryService) UpdateCategory(category *models.Category) error {
+	if err := s.checkCategory(category); err != nil {
+		return err
+	}
+	slug, previous, err := s.slugService.Pick(models.SlugCategory, category.ID, category.Slug, category.Name)
+	if err != nil {
+		return err
+	}
+	category.Slug = slug
+
+	if err := s.categoryRepo.Update(category); err != nil {
+		return err
+	}
+	s.slugService.Moved(models.SlugCategory, category.ID, previous, slug)
+	return nil
+}
+
+// DeleteCategory deletes a category no title belongs to; its subcategories move
+// up to its parent
+func (s *CategoryService) DeleteCategory(id uint) error {
+	// Titles would lose their category, even those in the trash; move them first
+	inUse, err := s.categoryRepo.CountContents(id)
+	if err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return &CategoryInUseError{Titles: inUse}
+	}
+	return s.categoryRepo.Delete(id)
+}
+
+// checkCategory validates the kind and parent of a category before it is saved
+func (s *CategoryService) checkCategory(category *models.Category) error {
+	category.Parent, category.Children, category.Contents = nil, nil, nil
+	if category.Kind == "" {
+		category.Kind = models.CategoryKindOther
+	}
+	if !category.Kind.IsValid() {
+		return fmt.Errorf("%w kind: %s", ErrInvalidCategory, category.Kind)
+	}
+	if category.ParentID == nil {
+		return nil
+	}
+	if _, err := s.categoryRepo.FindByID(*category.ParentID); err != nil {
+		return fmt.Errorf("%w: parent category not found", ErrInvalidCategory)
+	}
+	if category.ID == 0 {
+		return nil
+	}
+	// A category cannot be nested under itself or one of its subcategories
+	subtree, err := s.categoryRepo.DescendantIDs(category.ID)
+	if err != nil {
+		return err
+	}
+	for _, id := range subtree {
+		if id == *category.ParentID {
+			return fmt.Errorf("%w: a category cannot be nested under itself or its subcategories", ErrInvalidCategory)
+		}
+	}
+	return nil
+}
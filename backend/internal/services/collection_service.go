@@ -0,0 +1,259 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+// railPreload lists the content relations loaded for rail items: category and genres for
+// the cards and the alternative titles for localization
+var railPreload = []string{"Category", "Genres", "Titles", "Descriptions"}
+
+// defaultHomeRails is the homepage shown until an editor configures one
+func defaultHomeRails() []models.HomeRail {
+	return []models.HomeRail{
+		{Title: "New Episodes", Type: models.RailNewEpisodes, Limit: models.RailLimitDefault},
+		{Title: "This Season", Type: models.RailCurrentSeason, Limit: models.RailLimitDefault},
+		{Title: "Top Rated", Type: models.RailTopRated, Limit: models.RailLimitDefault},
+		{Title: "Recently Added", Type: models.RailRecentlyAdded, Limit: models.RailLimitDefault},
+	}
+}
+
+// CollectionService handles curated collections and the homepage layout
+type CollectionService struct {
+	collectionRepo *repository.CollectionRepository
+	contentRepo    *repository.ContentRepository
+	episodeRepo    *repository.EpisodeRepository
+	genreRepo      *repository.GenreRepository
+	seasonRepo     *repository.SeasonRepository
+}
+
+// NewCollectionService creates a new CollectionService
+func NewCollectionService(
+	collectionRepo *repository.CollectionRepository,
+	contentRepo *repository.ContentRepository,
+	episodeRepo *repository.EpisodeRepository,
+	genreRepo *repository.GenreRepository,
+	seasonRepo *repository.SeasonRepository,
+) *CollectionService {
+	return &CollectionService{
+		collectionRepo: collectionRepo,
+		contentRepo:    contentRepo,
+		episodeRepo:    episodeRepo,
+		genreRepo:      genreRepo,
+		seasonRepo:     seasonRepo,
+	}
+}
+
+// CreateCollection creates a new, empty collection
+func (s *CollectionService) CreateCollection(collection *models.Collection) error {
+	if err := validateCollection(collection); err != nil {
+		return err
+	}
+	return s.collectionRepo.Create(collection)
+}
+
+// GetCollection retrieves a collection with all of its items
+func (s *CollectionService) GetCollection(id uint) (*models.Collection, error) {
+	return s.collectionRepo.FindByID(id)
+}
+
+// GetVisibleCollection retrieves a collection inside its visibility window
+// together with the titles the viewer may see. Staff see it at any time.
+func (s *CollectionService) GetVisibleCollection(viewer *models.Viewer, id uint) (*models.Collection, []models.Content, error) {
+	collection, err := s.collectionRepo.FindByID(id)
+	if err != nil || (!viewer.IsStaff() && !collection.IsVisibleAt(time.Now())) {
+		return nil, nil, errors.New("collection not found")
+	}
+	contents, err := s.collectionRepo.ListContents(viewer, id, -1, railPreload...)
+	if err != nil {
+		return nil, nil, err
+	}
+	collection.Items = nil
+	return collection, contents, nil
+}
+
+// ListCollections lists all collections
+func (s *CollectionService) ListCollections() ([]models.Collection, error) {
+	return s.collectionRepo.List()
+}
+
+// UpdateCollection updates the title, description and visibility window of a collection
+func (s *CollectionService) UpdateCollection(collection *models.Collection) error {
+	if err := validateCollection(collection); err != nil {
+		return err
+	}
+	return s.collectionRepo.Update(collection)
+}
+
+// DeleteCollection deletes a collection and the rails showing it
+func (s *CollectionService) DeleteCollection(id uint) error {
+	if _, err := s.collectionRepo.FindByID(id); err != nil {
+		return errors.New("collection not found")
+	}
+	return s.collectionRepo.Delete(id)
+}
+
+// SetItems replaces the titles of a collection; the order of the IDs is the display order
+func (s *CollectionService) SetItems(collectionID uint, contentIDs []uint) (*models.Collection, error) {
+	if _, err := s.collectionRepo.FindByID(collectionID); err != nil {
+		return nil, errors.New("collection not found")
+	}
+
+	seen := make(map[uint]bool, len(contentIDs))
+	for _, contentID := range contentIDs {
+		if seen[contentID] {
+			return nil, fmt.Errorf("content %d is listed twice", contentID)
+		}
+		seen[contentID] = true
+		if _, err := s.contentRepo.FindByID(contentID); err != nil {
+			return nil, fmt.Errorf("content %d not found", contentID)
+		}
+	}
+
+	if err := s.collectionRepo.ReplaceItems(collectionID, contentIDs); err != nil {
+		return nil, fmt.Errorf("failed to update collection items: %v", err)
+	}
+	return s.collectionRepo.FindByID(collectionID)
+}
+
+// GetLayout lists the configured homepage rails
+func (s *CollectionService) GetLayout() ([]models.HomeRail, error) {
+	return s.collectionRepo.ListRails()
+}
+
+// SetLayout replaces the homepage layout; rails are shown in the given order
+func (s *CollectionService) SetLayout(rails []models.HomeRail) ([]models.HomeRail, error) {
+	for i := range rails {
+		rail := &rails[i]
+		rail.ID = 0
+		rail.Position = i
+		rail.Title = strings.TrimSpace(rail.Title)
+
+		if !rail.Type.IsValid() {
+			return nil, fmt.Errorf("invalid rail type: %s", rail.Type)
+		}
+		if rail.Limit == 0 {
+			rail.Limit = models.RailLimitDefault
+		} else if rail.Limit < 1 || rail.Limit > models.RailLimitMax {
+			return nil, fmt.Errorf("rail limit must be between 1 and %d", models.RailLimitMax)
+		}
+
+		switch rail.Type {
+		case models.RailCollection:
+			if rail.CollectionID == nil {
+				return nil, errors.New("collection rails need a collection")
+			}
+			if _, err := s.collectionRepo.FindByID(*rail.CollectionID); err != nil {
+				return nil, fmt.Errorf("collection %d not found", *rail.CollectionID)
+			}
+			rail.GenreID = nil
+		case models.RailTopRated:
+			if rail.GenreID != nil {
+				if _, err := s.genreRepo.FindByID(*rail.GenreID); err != nil {
+					return nil, fmt.Errorf("genre %d not found", *rail.GenreID)
+				}
+			}
+			rail.CollectionID = nil
+		default:
+			rail.CollectionID = nil
+			rail.GenreID = nil
+		}
+
+		// Collection rails fall back to the collection title
+		if rail.Title == "" && rail.Type != models.RailCollection {
+			return nil, errors.New("rail title is required")
+		}
+	}
+
+	if err := s.collectionRepo.ReplaceRails(rails); err != nil {
+		return nil, fmt.Errorf("failed to save home layout: %v", err)
+	}
+	return s.collectionRepo.ListRails()
+}
+
+// ResolveHome fills every rail of the homepage layout for the viewer. Rails
+// that are out of their window or have nothing to show are left out, and a
+// rail that fails to load does not take the rest of the page down with it.
+func (s *CollectionService) ResolveHome(viewer *models.Viewer) (*models.HomeLayout, error) {
+	rails, err := s.collectionRepo.ListRails()
+	if err != nil {
+		return nil, err
+	}
+	if len(rails) == 0 {
+		rails = defaultHomeRails()
+	}
+
+	now := time.Now()
+	layout := &models.HomeLayout{Rails: []models.ResolvedRail{}}
+	for _, rail := range rails {
+		resolved, err := s.resolveRail(viewer, &rail, now)
+		if err != nil {
+			log.Printf("Failed to resolve home rail %d (%s): %v", rail.ID, rail.Type, err)
+			continue
+		}
+		if resolved == nil || (len(resolved.Contents) == 0 && len(resolved.Episodes) == 0) {
+			continue
+		}
+		layout.Rails = append(layout.Rails, *resolved)
+	}
+	return layout, nil
+}
+
+// resolveRail loads the items of a single rail; nil means the rail is not shown
+func (s *CollectionService) resolveRail(viewer *models.Viewer, rail *models.HomeRail, now time.Time) (*models.ResolvedRail, error) {
+	resolved := &models.ResolvedRail{
+		ID:    rail.ID,
+		Title: rail.Title,
+		Type:  rail.Type,
+	}
+
+	var err error
+	switch rail.Type {
+	case models.RailCollection:
+		if rail.Collection == nil || !rail.Collection.IsVisibleAt(now) {
+			return nil, nil
+		}
+		if resolved.Title == "" {
+			resolved.Title = rail.Collection.Title
+		}
+		resolved.Description = rail.Collection.Description
+		resolved.Contents, err = s.collectionRepo.ListContents(viewer, rail.Collection.ID, rail.Limit, railPreload...)
+	case models.RailNewEpisodes:
+		resolved.Episodes, err = s.episodeRepo.ListRecentlyReleased(viewer, rail.Limit)
+	case models.RailTopRated:
+		resolved.Contents, err = s.contentRepo.ListTopRated(viewer, rail.GenreID, nil, rail.Limit, railPreload...)
+	case models.RailCurrentSeason:
+		season, seasonErr := s.seasonRepo.GetCurrentSeason(now.UTC())
+		if seasonErr != nil {
+			return nil, nil
+		}
+		resolved.Contents, err = s.contentRepo.ListTopRated(viewer, nil, &season.ID, rail.Limit, railPreload...)
+	case models.RailRecentlyAdded:
+		resolved.Contents, err = s.contentRepo.ListRecentlyAdded(viewer, rail.Limit, railPreload...)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// validateCollection checks the title and visibility window of a collection
+func validateCollection(collection *models.Collection) error {
+	collection.Title = strings.TrimSpace(collection.Title)
+	if collection.Title == "" {
+		return errors.New("collection title is required")
+	}
+	if collection.VisibleFrom != nil && collection.VisibleUntil != nil && !collection.VisibleUntil.After(*collection.VisibleFrom) {
+		return errors.New("visible_until must be after visible_from")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+// maxFranchiseSize bounds the graph walk so a badly linked catalog cannot blow up a request
+const maxFranchiseSize = 200
+
+// ContentRelationService handles business logic for franchise relations
+type ContentRelationService struct {
+	relationRepo *repository.ContentRelationRepository
+	contentRepo  *repository.ContentRepository
+}
+
+// NewContentRelationService creates a new ContentRelationService
+func NewContentRelationService(relationRepo *repository.ContentRelationRepository, contentRepo *repository.ContentRepository) *ContentRelationService {
+	return &ContentRelationService{
+		relationRepo: relationRepo,
+		contentRepo:  contentRepo,
+	}
+}
+
+// AddRelation links two titles; the inverse edge is stored automatically
+func (s *ContentRelationService) AddRelation(contentID, relatedContentID uint, relationType models.RelationType) (*models.ContentRelation, error) {
+	if !relationType.IsValid() {
+		return nil, fmt.Errorf("invalid relation type: %s", relationType)
+	}
+	if contentID == relatedContentID {
+		return nil, errors.New("a title cannot be related to itself")
+	}
+	if _, err := s.contentRepo.FindByID(contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+	if _, err := s.contentRepo.FindByID(relatedContentID); err != nil {
+		return nil, errors.New("related content not found")
+	}
+
+	relation := &models.ContentRelation{
+		ContentID:        contentID,
+		RelatedContentID: relatedContentID,
+		Type:             relationType,
+	}
+	inverse := &models.ContentRelation{
+		ContentID:        relatedContentID,
+		RelatedContentID: contentID,
+		Type:             relationType.Inverse(),
+	}
+	if err := s.relationRepo.CreatePair(relation, inverse); err != nil {
+		return nil, fmt.Errorf("failed to create relation: %v", err)
+	}
+
+	return relation, nil
+}
+
+// RemoveRelation removes a relation of a title together with its inverse
+func (s *ContentRelationService) RemoveRelation(contentID, relationID uint) error {
+	relation, err := s.relationRepo.FindByID(relationID)
+	if err != nil || relation.ContentID != contentID {
+		return errors.New("relation not found")
+	}
+	return s.relationRepo.DeletePair(relation.ContentID, relation.RelatedContentID)
+}
+
+// ListRelations lists the titles related to a title
+func (s *ContentRelationService) ListRelations(viewer *models.Viewer, contentID uint) ([]models.ContentRelation, error) {
+	if _, err := s.contentRepo.FindVisibleByID(viewer, contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+	return s.relationRepo.ListByContentID(viewer, contentID)
+}
+
+// GetWatchOrder walks the franchise graph from a title and returns every
+// reachable title ordered by release date. Titles without a date come last.
+func (s *ContentRelationService) GetWatchOrder(viewer *models.Viewer, contentID uint) ([]models.Content, error) {
+	if _, err := s.contentRepo.FindVisibleByID(viewer, contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+
+	seen := map[uint]bool{contentID: true}
+	frontier := []uint{contentID}
+	for len(frontier) > 0 && len(seen) < maxFranchiseSize {
+		edges, err := s.relationRepo.ListByContentIDs(frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		frontier = nil
+		for _, edge := range edges {
+			if !seen[edge.RelatedContentID] {
+				seen[edge.RelatedContentID] = true
+				frontier = append(frontier, edge.RelatedContentID)
+			}
+		}
+	}
+
+	ids := make([]uint, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+
+	contents, err := s.contentRepo.FindVisibleByIDs(viewer, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(contents, func(i, j int) bool {
+		a, b := contents[i].ReleaseDate, contents[j].ReleaseDate
+		switch {
+		case a == nil && b == nil:
+			return contents[i].ID < contents[j].ID
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		case a.Equal(*b):
+			return contents[i].ID < contents[j].ID
+		}
+		return a.Before(*b)
+	})
+
+	return contents, nil
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/username/anime-streaming/internal/models"
 	"github.com/username/anime-streaming/internal/repository"
@@ -14,11 +15,14 @@ import (
 
 // ContentService handles business logic for content
 type ContentService struct {
-	contentRepo       *repository.ContentRepository
-	genreRepo         *repository.GenreRepository
-	categoryRepo      *repository.CategoryRepository
-	mediaPath         string
-	contentTypeHelper *models.ContentTypeHelper
+	contentRepo     *repository.ContentRepository
+	genreRepo       *repository.GenreRepository
+	categoryRepo    *repository.CategoryRepository
+	slugService     *SlugService
+	revisionService *RevisionService
+	mediaPath       string
+	maturityScheme  models.MaturityScheme
+	unratedRating   models.MaturityRating
 }
 
 // NewContentService creates a new ContentService
@@ -26,27 +30,41 @@ func NewContentService(
 	contentRepo *repository.ContentRepository,
 	genreRepo *repository.GenreRepository,
 	categoryRepo *repository.CategoryRepository,
+	slugService *SlugService,
+	revisionService *RevisionService,
 	mediaPath string,
+	maturityScheme models.MaturityScheme,
+	unratedRating models.MaturityRating,
 ) *ContentService {
 	return &ContentService{
-		contentRepo:       contentRepo,
-		genreRepo:         genreRepo,
-		categoryRepo:      categoryRepo,
-		mediaPath:         mediaPath,
-		contentTypeHelper: models.NewContentTypeHelper(),
+		contentRepo:     contentRepo,
+		genreRepo:       genreRepo,
+		categoryRepo:    categoryRepo,
+		slugService:     slugService,
+		revisionService: revisionService,
+		mediaPath:       mediaPath,
+		maturityScheme:  maturityScheme,
+		unratedRating:   unratedRating,
 	}
 }
 
-// CreateContent creates a new content entry
-func (s *ContentService) CreateContent(content *models.Content) error {
-	// Validate content type against existing categories
-	categories, err := s.categoryRepo.List()
-	if err != nil {
-		return fmt.Errorf("failed to fetch categories: %v", err)
+// CreateContent creates a new content entry on behalf of a user
+func (s *ContentService) CreateContent(userID uint, content *models.Content) error {
+	if err := s.resolveCategory(content); err != nil {
+		return err
+	}
+
+	if err := s.resolveMaturityRating(content); err != nil {
+		return err
 	}
 
-	if !s.contentTypeHelper.IsValidType(content.Type, categories) {
-		return errors.New("invalid content type: must match an existing category")
+	if err := validateBroadcast(content); err != nil {
+		return err
+	}
+
+	var err error
+	if content.Status, content.PublishAt, err = models.ResolvePublishState(content.Status, content.PublishAt, time.Now()); err != nil {
+		return err
 	}
 
 	// Set cover image path if provided
@@ -57,25 +75,70 @@ func (s *ContentService) CreateContent(content *models.Content) error {
 		content.CoverImage = filepath.Join("thumbnails", "content", content.CoverImage)
 	}
 
-	return s.contentRepo.Create(content)
+	if content.Slug, _, err = s.slugService.Pick(models.SlugContent, 0, content.Slug, content.Title); err != nil {
+		return err
+	}
+
+	return s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		if err := w.Contents.Create(content); err != nil {
+			return err
+		}
+		return w.RecordContent(content.ID, models.RevisionCreate)
+	})
+}
+
+// resolveCategory points the content at its category. Clients send either
+// category_id or, as before, the category name as type.
+func (s *ContentService) resolveCategory(content *models.Content) error {
+	var category *models.Category
+	var err error
+	switch {
+	case content.CategoryID != nil:
+		category, err = s.categoryRepo.FindByID(*content.CategoryID)
+	case content.Type != "":
+		category, err = s.categoryRepo.FindByName(content.Type)
+	default:
+		return errors.New("category is required")
+	}
+	if err != nil {
+		return errors.New("invalid category: must match an existing category")
+	}
+
+	category.Children = nil
+	content.CategoryID = &category.ID
+	content.Category = category
+	content.Type = category.Name
+	return nil
 }
 
 // GetContentByID retrieves a content by its ID
 func (s *ContentService) GetContentByID(id uint) (*models.Content, error) {
 	// Preload all relationships
-	return s.contentRepo.FindByID(id, "Episodes", "Genres", "Categories", "StreamLinks", "DownloadLinks")
+	return s.contentRepo.FindByID(id, "Episodes", "Genres", "Category", "Categories", "StreamLinks", "DownloadLinks", "Titles", "Descriptions", "Tags")
 }
 
-// UpdateContent updates content information
-func (s *ContentService) UpdateContent(content *models.Content) error {
-	// Validate content type against existing categories
-	categories, err := s.categoryRepo.List()
-	if err != nil {
-		return fmt.Errorf("failed to fetch categories: %v", err)
+// GetVisibleContent retrieves a content by its ID if the viewer is allowed to see it
+func (s *ContentService) GetVisibleContent(viewer *models.Viewer, id uint) (*models.Content, error) {
+	return s.contentRepo.FindVisibleByID(viewer, id, "Episodes", "Genres", "Category", "Categories", "StreamLinks", "DownloadLinks", "Titles", "Descriptions", "Tags")
+}
+
+// UpdateContent updates content information on behalf of a user
+func (s *ContentService) UpdateContent(userID uint, content *models.Content) error {
+	if err := s.resolveCategory(content); err != nil {
+		return err
 	}
 
-	if !s.contentTypeHelper.IsValidType(content.Type, categories) {
-		return errors.New("invalid content type: must match an existing category")
+	if err := s.resolveMaturityRating(content); err != nil {
+		return err
+	}
+
+	if err := validateBroadcast(content); err != nil {
+		return err
+	}
+
+	var err error
+	if content.Status, content.PublishAt, err = models.ResolvePublishState(content.Status, content.PublishAt, time.Now()); err != nil {
+		return err
 	}
 
 	fmt.Println("check content", content)
@@ -91,11 +154,27 @@ func (s *ContentService) UpdateContent(content *models.Content) error {
 		content.CoverImage = filepath.Join("thumbnails", "content", filepath.Base(content.CoverImage))
 	}
 
-	return s.contentRepo.Update(content)
+	slug, previous, err := s.slugService.Pick(models.SlugContent, content.ID, content.Slug, content.Title)
+	if err != nil {
+		return err
+	}
+	content.Slug = slug
+
+	err = s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		if err := w.Contents.Update(content); err != nil {
+			return err
+		}
+		return w.RecordContent(content.ID, models.RevisionUpdate)
+	})
+	if err != nil {
+		return err
+	}
+	s.slugService.Moved(models.SlugContent, content.ID, previous, slug)
+	return nil
 }
 
-// DeleteContent deletes content
-func (s *ContentService) DeleteContent(id uint) error {
+// DeleteContent deletes content on behalf of a user
+func (s *ContentService) DeleteContent(userID, id uint) error {
 	log.Printf("Service: Attempting to delete content with ID: %d", id)
 
 	// Verify content exists first
@@ -105,17 +184,14 @@ func (s *ContentService) DeleteContent(id uint) error {
 		return fmt.Errorf("content not found: %v", err)
 	}
 
-	// Delete related records first (if any)
-	// Note: This might not be necessary if you've set up CASCADE DELETE in your database
-	if err := s.contentRepo.DeleteStreamLinks(id); err != nil {
-		log.Printf("Service: Failed to delete stream links: %v", err)
-	}
-	if err := s.contentRepo.DeleteDownloadLinks(id); err != nil {
-		log.Printf("Service: Failed to delete download links: %v", err)
-	}
-
-	// Delete the content
-	if err := s.contentRepo.Delete(content.ID); err != nil {
+	// Delete the content; episodes and links go to the trash with it
+	err = s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		if err := w.Contents.Delete(content.ID); err != nil {
+			return err
+		}
+		return w.RecordContent(content.ID, models.RevisionDelete)
+	})
+	if err != nil {
 		log.Printf("Service: Failed to delete content: %v", err)
 		return fmt.Errorf("failed to delete content: %v", err)
 	}
@@ -124,24 +200,27 @@ func (s *ContentService) DeleteContent(id uint) error {
 	return nil
 }
 
-// ListContent lists all content with pagination and filtering
-func (s *ContentService) ListContent(page, pageSize int, filters map[string]interface{}) ([]models.Content, int64, error) {
-	return s.contentRepo.List(page, pageSize, filters, "Episodes", "Genres", "Categories", "StreamLinks", "DownloadLinks")
+// ListContent lists all content with pagination, filtering and sorting
+func (s *ContentService) ListContent(viewer *models.Viewer, page, pageSize int, filters map[string]interface{}, sort models.ContentSort) ([]models.Content, int64, error) {
+	if !sort.IsValid() {
+		return nil, 0, fmt.Errorf("invalid sort: %s", sort)
+	}
+	return s.contentRepo.List(viewer, page, pageSize, filters, sort, "Episodes", "Genres", "Category", "Categories", "StreamLinks", "DownloadLinks", "Titles", "Descriptions", "Tags")
 }
 
-// SearchContent searches content by title
-func (s *ContentService) SearchContent(term string, page, pageSize int) ([]models.Content, int64, error) {
-	return s.contentRepo.Search(term, page, pageSize, "Episodes", "Genres", "Categories", "StreamLinks", "DownloadLinks")
+// SearchContent searches content by title, optionally narrowed by tags
+func (s *ContentService) SearchContent(viewer *models.Viewer, term string, tags models.TagFilter, page, pageSize int) ([]models.Content, int64, error) {
+	return s.contentRepo.Search(viewer, term, tags, page, pageSize, "Episodes", "Genres", "Category", "Categories", "StreamLinks", "DownloadLinks", "Titles", "Descriptions", "Tags")
 }
 
 // GetContentByGenre gets content by genre
-func (s *ContentService) GetContentByGenre(genreID uint, page, pageSize int) ([]models.Content, int64, error) {
-	return s.contentRepo.FindByGenre(genreID, page, pageSize, "Episodes", "Genres", "Categories", "StreamLinks", "DownloadLinks")
+func (s *ContentService) GetContentByGenre(viewer *models.Viewer, genreID uint, page, pageSize int) ([]models.Content, int64, error) {
+	return s.contentRepo.FindByGenre(viewer, genreID, page, pageSize, "Episodes", "Genres", "Category", "Categories", "StreamLinks", "DownloadLinks", "Titles", "Descriptions", "Tags")
 }
 
 // GetContentByCategory gets content by category
-func (s *ContentService) GetContentByCategory(categoryID uint, page, pageSize int) ([]models.Content, int64, error) {
-	return s.contentRepo.FindByCategory(categoryID, page, pageSize, "Episodes", "Genres", "Categories", "Season", "StreamLinks", "DownloadLinks")
+func (s *ContentService) GetContentByCategory(viewer *models.Viewer, categoryID uint, page, pageSize int) ([]models.Content, int64, error) {
+	return s.contentRepo.FindByCategory(viewer, categoryID, page, pageSize, "Episodes", "Genres", "Category", "Categories", "Season", "StreamLinks", "DownloadLinks", "Titles", "Descriptions", "Tags")
 }
 
 // AddGenreToContent adds a genre to content
@@ -186,11 +265,17 @@ func (s *ContentService) RemoveCategoryFromContent(contentID, categoryID uint) e
 
 // AddStreamLink adds a stream link to content
 func (s *ContentService) AddStreamLink(contentID uint, streamLink *models.StreamLink) error {
+	if err := checkStreamURL(streamLink); err != nil {
+		return err
+	}
 	return s.contentRepo.AddStreamLink(contentID, streamLink)
 }
 
 // AddDownloadLink adds a download link to content
 func (s *ContentService) AddDownloadLink(contentID uint, downloadLink *models.DownloadLink) error {
+	if err := checkDownloadURL(downloadLink); err != nil {
+		return err
+	}
 	return s.contentRepo.AddDownloadLink(contentID, downloadLink)
 }
 
@@ -199,32 +284,116 @@ func (s *ContentService) GetDB() *gorm.DB {
 	return s.contentRepo.GetDB()
 }
 
-// DeleteContentEpisodes deletes all episodes for a given content ID
-func (s *ContentService) DeleteContentEpisodes(contentID uint) error {
-	// Delete all episodes for this content
-	result := s.contentRepo.GetDB().Where("content_id = ?", contentID).Delete(&models.Episode{})
-	if result.Error != nil {
-		return result.Error
-	}
+// NumberEpisodes fills in the absolute numbers missing on the regular episodes of a content
+func (s *ContentService) NumberEpisodes(contentID uint) error {
+	return s.contentRepo.NumberEpisodes(contentID)
+}
+
+// ListEpisodes lists the live episodes of a content of every kind and status
+func (s *ContentService) ListEpisodes(contentID uint) ([]models.Episode, error) {
+	return s.contentRepo.ListEpisodes(contentID)
+}
+
+// DeleteEpisodes moves episodes of a content to the trash together with their
+// links on behalf of a user, recording the deletion of each
+func (s *ContentService) DeleteEpisodes(userID uint, ids []uint) error {
+	return s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		for _, id := range ids {
+			if err := w.Episodes.Delete(id); err != nil {
+				return err
+			}
+			if err := w.RecordEpisode(id, models.RevisionDelete); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
 
-	// Delete associated stream links and download links
-	if err := s.contentRepo.DeleteStreamLinks(contentID); err != nil {
+// SaveEpisode creates or updates an episode sent along with its content's form
+// on behalf of a user. Updates are refused if the episode changed since it was read.
+func (s *ContentService) SaveEpisode(userID uint, episode *models.Episode) error {
+	if err := normalizeEpisodeKind(episode); err != nil {
 		return err
 	}
+	return s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		if episode.ID == 0 {
+			if err := w.Episodes.Create(episode); err != nil {
+				return err
+			}
+			return w.RecordEpisode(episode.ID, models.RevisionCreate)
+		}
+		if err := w.Episodes.Update(episode); err != nil {
+			return err
+		}
+		return w.RecordEpisode(episode.ID, models.RevisionUpdate)
+	})
+}
 
-	if err := s.contentRepo.DeleteDownloadLinks(contentID); err != nil {
-		return err
+// ReplaceLinks makes the given links the links of an episode, or of the title
+// itself when episodeID is nil. Links sent again keep their ID and health.
+func (s *ContentService) ReplaceLinks(contentID uint, episodeID *uint, streamLinks []models.StreamLink, downloadLinks []models.DownloadLink) error {
+	for i := range streamLinks {
+		if err := checkStreamURL(&streamLinks[i]); err != nil {
+			return err
+		}
 	}
+	for i := range downloadLinks {
+		if err := checkDownloadURL(&downloadLinks[i]); err != nil {
+			return err
+		}
+	}
+	return s.contentRepo.ReplaceLinks(contentID, episodeID, streamLinks, downloadLinks)
+}
 
-	return nil
+// SetGenres replaces the genres of a content on behalf of a user
+func (s *ContentService) SetGenres(userID, contentID uint, genreIDs []uint) error {
+	return s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		if err := w.Contents.ReplaceGenres(contentID, genreIDs); err != nil {
+			return err
+		}
+		return w.RecordContent(contentID, models.RevisionUpdate)
+	})
 }
 
-// GetContentByTitle retrieves content by title
+// GetContentByTitle retrieves content by its main title or any alternative title
 func (s *ContentService) GetContentByTitle(title string) (*models.Content, error) {
-	var content models.Content
-	err := s.contentRepo.GetDB().Where("title = ?", title).First(&content).Error
-	if err != nil {
-		return nil, err
+	return s.contentRepo.FindByTitleOrAlias(title)
+}
+
+// SetTitles replaces the alternative titles of a content
+func (s *ContentService) SetTitles(contentID uint, titles []models.ContentTitle) error {
+	for i, title := range titles {
+		if title.Language == "" || strings.TrimSpace(title.Title) == "" {
+			return errors.New("alternative titles need a language and a title")
+		}
+		if title.Kind == "" {
+			titles[i].Kind = models.TitleKindOfficial
+		} else if !title.Kind.IsValid() {
+			return fmt.Errorf("invalid title kind: %s", title.Kind)
+		}
 	}
-	return &content, nil
+	return s.contentRepo.ReplaceTitles(contentID, titles)
+}
+
+// SetDescriptions replaces the localized descriptions of a content
+func (s *ContentService) SetDescriptions(contentID uint, descriptions []models.ContentDescription) error {
+	for _, description := range descriptions {
+		if description.Language == "" {
+			return errors.New("localized descriptions need a language")
+		}
+	}
+	return s.contentRepo.ReplaceDescriptions(contentID, descriptions)
+}
+
+// resolveMaturityRating checks the rating against the configured scheme. Titles
+// without one get the unrated rating, so parental limits never see an empty rating.
+func (s *ContentService) resolveMaturityRating(content *models.Content) error {
+	if content.MaturityRating == "" {
+		content.MaturityRating = s.unratedRating
+	}
+	if !s.maturityScheme.IsValid(content.MaturityRating) {
+		return fmt.Errorf("invalid maturity rating: %s", content.MaturityRating)
+	}
+	return nil
 }
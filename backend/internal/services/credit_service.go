@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+// CreditService handles studios, people, characters and their credits on content
+type CreditService struct {
+	studioRepo    *repository.StudioRepository
+	personRepo    *repository.PersonRepository
+	characterRepo *repository.CharacterRepository
+	creditRepo    *repository.ContentCreditRepository
+	contentRepo   *repository.ContentRepository
+}
+
+// NewCreditService creates a new CreditService
+func NewCreditService(
+	studioRepo *repository.StudioRepository,
+	personRepo *repository.PersonRepository,
+	characterRepo *repository.CharacterRepository,
+	creditRepo *repository.ContentCreditRepository,
+	contentRepo *repository.ContentRepository,
+) *CreditService {
+	return &CreditService{
+		studioRepo:    studioRepo,
+		personRepo:    personRepo,
+		characterRepo: characterRepo,
+		creditRepo:    creditRepo,
+		contentRepo:   contentRepo,
+	}
+}
+
+// CreateStudio creates a new studio
+func (s *CreditService) CreateStudio(studio *models.Studio) error {
+	if strings.TrimSpace(studio.Name) == "" {
+		return errors.New("studio name is required")
+	}
+	return s.studioRepo.Create(studio)
+}
+
+// GetStudio retrieves a studio by ID
+func (s *CreditService) GetStudio(id uint) (*models.Studio, error) {
+	return s.studioRepo.FindByID(id)
+}
+
+// UpdateStudio updates a studio
+func (s *CreditService) UpdateStudio(studio *models.Studio) error {
+	if _, err := s.studioRepo.FindByID(studio.ID); err != nil {
+		return errors.New("studio not found")
+	}
+	if strings.TrimSpace(studio.Name) == "" {
+		return errors.New("studio name is required")
+	}
+	return s.studioRepo.Update(studio)
+}
+
+// DeleteStudio deletes a studio
+func (s *CreditService) DeleteStudio(id uint) error {
+	return s.studioRepo.Delete(id)
+}
+
+// ListStudios lists studios, optionally filtered by name
+func (s *CreditService) ListStudios(term string, page, pageSize int) ([]models.Studio, int64, error) {
+	return s.studioRepo.List(term, page, pageSize)
+}
+
+// CreatePerson creates a new person
+func (s *CreditService) CreatePerson(person *models.Person) error {
+	if strings.TrimSpace(person.Name) == "" {
+		return errors.New("person name is required")
+	}
+	return s.personRepo.Create(person)
+}
+
+// GetPerson retrieves a person by ID
+func (s *CreditService) GetPerson(id uint) (*models.Person, error) {
+	return s.personRepo.FindByID(id)
+}
+
+// UpdatePerson updates a person
+func (s *CreditService) UpdatePerson(person *models.Person) error {
+	if _, err := s.personRepo.FindByID(person.ID); err != nil {
+		return errors.New("person not found")
+	}
+	if strings.TrimSpace(person.Name) == "" {
+		return errors.New("person name is required")
+	}
+	return s.personRepo.Update(person)
+}
+
+// DeletePerson deletes a person
+func (s *CreditService) DeletePerson(id uint) error {
+	return s.personRepo.Delete(id)
+}
+
+// ListPeople lists people, optionally filtered by name
+func (s *CreditService) ListPeople(term string, page, pageSize int) ([]models.Person, int64, error) {
+	return s.personRepo.List(term, page, pageSize)
+}
+
+// CreateCharacter creates a new character
+func (s *CreditService) CreateCharacter(character *models.Character) error {
+	if strings.TrimSpace(character.Name) == "" {
+		return errors.New("character name is required")
+	}
+	return s.characterRepo.Create(character)
+}
+
+// GetCharacter retrieves a character by ID
+func (s *CreditService) GetCharacter(id uint) (*models.Character, error) {
+	return s.characterRepo.FindByID(id)
+}
+
+// UpdateCharacter updates a character
+func (s *CreditService) UpdateCharacter(character *models.Character) error {
+	if _, err := s.characterRepo.FindByID(character.ID); err != nil {
+		return errors.New("character not found")
+	}
+	if strings.TrimSpace(character.Name) == "" {
+		return errors.New("character name is required")
+	}
+	return s.characterRepo.Update(character)
+}
+
+// DeleteCharacter deletes a character
+func (s *CreditService) DeleteCharacter(id uint) error {
+	return s.characterRepo.Delete(id)
+}
+
+// ListCharacters lists characters, optionally filtered by name
+func (s *CreditService) ListCharacters(term string, page, pageSize int) ([]models.Character, int64, error) {
+	return s.characterRepo.List(term, page, pageSize)
+}
+
+// GetContentCredits retrieves the studios, staff and cast of a content
+func (s *CreditService) GetContentCredits(viewer *models.Viewer, contentID uint) (*models.ContentCredits, error) {
+	if _, err := s.contentRepo.FindVisibleByID(viewer, contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+	return s.creditRepo.FindByContentID(contentID)
+}
+
+// SetContentCredits replaces the studios, staff and cast of a content
+func (s *CreditService) SetContentCredits(contentID uint, credits *models.ContentCredits) error {
+	if _, err := s.contentRepo.FindByID(contentID); err != nil {
+		return errors.New("content not found")
+	}
+
+	for _, credit := range credits.Studios {
+		if credit.Role == "" {
+			return errors.New("studio credits need a role")
+		}
+		if _, err := s.studioRepo.FindByID(credit.StudioID); err != nil {
+			return errors.New("studio not found")
+		}
+	}
+	for _, credit := range credits.Staff {
+		if credit.Role == "" {
+			return errors.New("staff credits need a role")
+		}
+		if _, err := s.personRepo.FindByID(credit.PersonID); err != nil {
+			return errors.New("person not found")
+		}
+	}
+	for i, credit := range credits.Characters {
+		if _, err := s.characterRepo.FindByID(credit.CharacterID); err != nil {
+			return errors.New("character not found")
+		}
+		if credit.PersonID != nil {
+			if _, err := s.personRepo.FindByID(*credit.PersonID); err != nil {
+				return errors.New("voice actor not found")
+			}
+			if credit.Language == "" {
+				return errors.New("voice roles need a language")
+			}
+		}
+		if credit.Role == "" {
+			credits.Characters[i].Role = "supporting"
+		}
+	}
+
+	return s.creditRepo.Replace(contentID, credits)
+}
@@ -2,8 +2,12 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/username/anime-streaming/internal/models"
 	"github.com/username/anime-streaming/internal/repository"
@@ -11,26 +15,31 @@ import (
 
 // EpisodeService handles business logic for episodes
 type EpisodeService struct {
-	episodeRepo *repository.EpisodeRepository
-	contentRepo *repository.ContentRepository
-	mediaPath   string
+	episodeRepo       *repository.EpisodeRepository
+	contentRepo       *repository.ContentRepository
+	revisionService   *RevisionService
+	mediaPath         string
+	contentTypeHelper *models.ContentTypeHelper
 }
 
 // NewEpisodeService creates a new EpisodeService
 func NewEpisodeService(
 	episodeRepo *repository.EpisodeRepository,
 	contentRepo *repository.ContentRepository,
+	revisionService *RevisionService,
 	mediaPath string,
 ) *EpisodeService {
 	return &EpisodeService{
-		episodeRepo: episodeRepo,
-		contentRepo: contentRepo,
-		mediaPath:   mediaPath,
+		episodeRepo:       episodeRepo,
+		contentRepo:       contentRepo,
+		revisionService:   revisionService,
+		mediaPath:         mediaPath,
+		contentTypeHelper: models.NewContentTypeHelper(),
 	}
 }
 
-// CreateEpisode creates a new episode
-func (s *EpisodeService) CreateEpisode(episode *models.Episode) error {
+// CreateEpisode creates a new episode on behalf of a user
+func (s *EpisodeService) CreateEpisode(userID uint, episode *models.Episode) error {
 	// Verify content exists
 	_, err := s.contentRepo.FindByID(episode.ContentID)
 	if err != nil {
@@ -51,7 +60,26 @@ func (s *EpisodeService) CreateEpisode(episode *models.Episode) error {
 		episode.ThumbnailURL = filepath.Join("thumbnails", "episodes", episode.ThumbnailURL)
 	}
 
-	return s.episodeRepo.Create(episode)
+	if episode.Status, episode.PublishAt, err = models.ResolvePublishState(episode.Status, episode.PublishAt, time.Now()); err != nil {
+		return err
+	}
+
+	if err := normalizeEpisodeKind(episode); err != nil {
+		return err
+	}
+	if err := s.checkAbsoluteNumber(episode); err != nil {
+		return err
+	}
+
+	return s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		if err := w.Episodes.Create(episode); err != nil {
+			return err
+		}
+		if err := numberEpisode(w.Episodes, episode); err != nil {
+			return err
+		}
+		return w.RecordEpisode(episode.ID, models.RevisionCreate)
+	})
 }
 
 // GetEpisodeByID retrieves an episode by ID
@@ -59,14 +87,19 @@ func (s *EpisodeService) GetEpisodeByID(id uint) (*models.Episode, error) {
 	return s.episodeRepo.FindByID(id)
 }
 
-// UpdateEpisode updates episode information
-func (s *EpisodeService) UpdateEpisode(episode *models.Episode) error {
+// GetVisibleEpisode retrieves an episode by ID if the viewer is allowed to see its content
+func (s *EpisodeService) GetVisibleEpisode(viewer *models.Viewer, id uint) (*models.Episode, error) {
+	return s.episodeRepo.FindVisibleByID(viewer, id)
+}
+
+// UpdateEpisode updates episode information on behalf of a user
+func (s *EpisodeService) UpdateEpisode(userID uint, episode *models.Episode) error {
 	// Verify content exists and is a series
-	content, err := s.contentRepo.FindByID(episode.ContentID)
+	content, err := s.contentRepo.FindByID(episode.ContentID, "Category")
 	if err != nil {
 		return err
 	}
-	if content.Type != "Series" && content.Type != "Anime" {
+	if !s.contentTypeHelper.IsSeries(content) {
 		return errors.New("content must be a series or anime")
 	}
 
@@ -90,34 +123,383 @@ func (s *EpisodeService) UpdateEpisode(episode *models.Episode) error {
 		episode.ThumbnailURL = filepath.Join("thumbnails", "episodes", filepath.Base(episode.ThumbnailURL))
 	}
 
-	return s.episodeRepo.Update(episode)
+	if episode.Status, episode.PublishAt, err = models.ResolvePublishState(episode.Status, episode.PublishAt, time.Now()); err != nil {
+		return err
+	}
+
+	if err := normalizeEpisodeKind(episode); err != nil {
+		return err
+	}
+	if err := s.checkAbsoluteNumber(episode); err != nil {
+		return err
+	}
+
+	return s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		if err := w.Episodes.Update(episode); err != nil {
+			return err
+		}
+		if err := numberEpisode(w.Episodes, episode); err != nil {
+			return err
+		}
+		return w.RecordEpisode(episode.ID, models.RevisionUpdate)
+	})
 }
 
-// DeleteEpisode deletes an episode
-func (s *EpisodeService) DeleteEpisode(id uint) error {
-	return s.episodeRepo.Delete(id)
+// numberEpisode fills in the missing absolute numbers of the episode's title
+// and picks up the one given to the episode itself
+func numberEpisode(episodes *repository.EpisodeRepository, episode *models.Episode) error {
+	if episode.Type.IsSpecial() || episode.AbsoluteNumber != nil {
+		return nil
+	}
+	if err := episodes.NumberEpisodes(episode.ContentID); err != nil {
+		return err
+	}
+	numbered, err := episodes.FindByID(episode.ID)
+	if err != nil {
+		return err
+	}
+	episode.AbsoluteNumber = numbered.AbsoluteNumber
+	return nil
 }
 
-// ListEpisodes lists all episodes for a content with optional season filter
-func (s *EpisodeService) ListEpisodes(contentID uint, season *int) ([]models.Episode, error) {
-	return s.episodeRepo.ListByContentID(contentID, season)
+// checkAbsoluteNumber refuses an absolute number another live episode of the title already has
+func (s *EpisodeService) checkAbsoluteNumber(episode *models.Episode) error {
+	if episode.AbsoluteNumber == nil {
+		return nil
+	}
+	taken, err := s.episodeRepo.AbsoluteNumberTaken(episode.ContentID, *episode.AbsoluteNumber, episode.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fmt.Errorf("absolute number %d is already used by another episode", *episode.AbsoluteNumber)
+	}
+	return nil
 }
 
-// GetNextEpisode gets the next episode in a series
-func (s *EpisodeService) GetNextEpisode(contentID uint, currentSeason, currentEpisode int) (*models.Episode, error) {
-	return s.episodeRepo.GetNextEpisode(contentID, currentSeason, currentEpisode)
+// DeleteEpisode deletes an episode on behalf of a user
+func (s *EpisodeService) DeleteEpisode(userID, id uint) error {
+	return s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		if err := w.Episodes.Delete(id); err != nil {
+			return err
+		}
+		return w.RecordEpisode(id, models.RevisionDelete)
+	})
+}
+
+// normalizeEpisodeKind defaults the kind to a regular canon episode and makes
+// sure only regular episodes carry an absolute number
+func normalizeEpisodeKind(episode *models.Episode) error {
+	if episode.Type == "" {
+		episode.Type = models.EpisodeKindRegular
+	}
+	if !episode.Type.IsValid() {
+		return fmt.Errorf("invalid episode type: %s", episode.Type)
+	}
+	if episode.Canon == "" {
+		episode.Canon = models.EpisodeCanonCanon
+	}
+	if !episode.Canon.IsValid() {
+		return fmt.Errorf("invalid canon flag: %s", episode.Canon)
+	}
+	if episode.Type.IsSpecial() {
+		episode.AbsoluteNumber = nil
+	} else if episode.AbsoluteNumber != nil && *episode.AbsoluteNumber < 1 {
+		return errors.New("absolute number must be positive")
+	}
+	return nil
+}
+
+// ListEpisodes lists the episodes of a content, optionally narrowed down to a season or cour
+func (s *EpisodeService) ListEpisodes(viewer *models.Viewer, contentID uint, filter models.EpisodeFilter) ([]models.Episode, error) {
+	if filter.Cour != nil && filter.Season == nil {
+		return nil, errors.New("a cour can only be picked within a season")
+	}
+	return s.episodeRepo.ListByContentID(viewer, contentID, filter)
+}
+
+// GetEpisodeByAbsoluteNumber gets a regular episode of a content by its absolute number
+func (s *EpisodeService) GetEpisodeByAbsoluteNumber(viewer *models.Viewer, contentID uint, number int) (*models.Episode, error) {
+	return s.episodeRepo.FindByAbsoluteNumber(viewer, contentID, number)
+}
+
+// GetNextEpisode gets the next episode in a series, skipping specials and filler as the filter says
+func (s *EpisodeService) GetNextEpisode(viewer *models.Viewer, contentID uint, currentSeason, currentEpisode int, filter models.EpisodeFilter) (*models.Episode, error) {
+	return s.episodeRepo.GetNextEpisode(viewer, contentID, currentSeason, currentEpisode, filter)
+}
+
+// GetPreviousEpisode gets the previous episode in a series, skipping specials and filler as the filter says
+func (s *EpisodeService) GetPreviousEpisode(viewer *models.Viewer, contentID uint, currentSeason, currentEpisode int, filter models.EpisodeFilter) (*models.Episode, error) {
+	return s.episodeRepo.GetPreviousEpisode(viewer, contentID, currentSeason, currentEpisode, filter)
+}
+
+// ListCours lists how the split-cour seasons of a content are divided
+func (s *EpisodeService) ListCours(contentID uint) ([]models.EpisodeCour, error) {
+	return s.episodeRepo.ListCours(contentID)
+}
+
+// SetCours replaces the split-cour mapping of a content. The cours of a season
+// must cover episode ranges that do not overlap.
+func (s *EpisodeService) SetCours(userID, contentID uint, cours []models.EpisodeCour) ([]models.EpisodeCour, error) {
+	if _, err := s.contentRepo.FindByID(contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+
+	sort.Slice(cours, func(i, j int) bool {
+		if cours[i].SeasonNumber != cours[j].SeasonNumber {
+			return cours[i].SeasonNumber < cours[j].SeasonNumber
+		}
+		return cours[i].Number < cours[j].Number
+	})
+	for i := range cours {
+		cour := &cours[i]
+		cour.ID = 0
+		cour.ContentID = contentID
+		if cour.Number < 1 {
+			return nil, fmt.Errorf("cour %d of season %d: number must be positive", cour.Number, cour.SeasonNumber)
+		}
+		if cour.FirstEpisode < 1 || cour.LastEpisode < cour.FirstEpisode {
+			return nil, fmt.Errorf("cour %d of season %d: invalid episode range %d-%d", cour.Number, cour.SeasonNumber, cour.FirstEpisode, cour.LastEpisode)
+		}
+		if i > 0 && cours[i-1].SeasonNumber == cour.SeasonNumber {
+			previous := cours[i-1]
+			if previous.Number == cour.Number {
+				return nil, fmt.Errorf("cour %d of season %d is listed twice", cour.Number, cour.SeasonNumber)
+			}
+			if cour.FirstEpisode <= previous.LastEpisode {
+				return nil, fmt.Errorf("cour %d of season %d overlaps the cour before it", cour.Number, cour.SeasonNumber)
+			}
+		}
+	}
+
+	err := s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		if err := w.Episodes.ReplaceCours(contentID, cours); err != nil {
+			return err
+		}
+		return w.RecordContent(contentID, models.RevisionUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.episodeRepo.ListCours(contentID)
 }
 
 // GetLatestEpisode gets the latest episode for a series
-func (s *EpisodeService) GetLatestEpisode(contentID uint) (*models.Episode, error) {
-	return s.episodeRepo.GetLatestEpisode(contentID)
+func (s *EpisodeService) GetLatestEpisode(viewer *models.Viewer, contentID uint) (*models.Episode, error) {
+	return s.episodeRepo.GetLatestEpisode(viewer, contentID)
 }
 
-// GetContent gets content by ID
-func (s *EpisodeService) GetContent(contentID uint) (*models.Content, error) {
-	content, err := s.contentRepo.FindByID(contentID)
+// GetContent gets content by ID if the viewer is allowed to see it
+func (s *EpisodeService) GetContent(viewer *models.Viewer, contentID uint) (*models.Content, error) {
+	content, err := s.contentRepo.FindVisibleByID(viewer, contentID)
 	if err != nil {
 		return nil, err
 	}
 	return content, nil
 }
+
+// ListArcs lists the story arcs of a content in episode order
+func (s *EpisodeService) ListArcs(contentID uint) ([]models.StoryArc, error) {
+	return s.episodeRepo.ListArcs(contentID)
+}
+
+// SetArcs replaces the story arcs of a content. Arcs span absolute episode
+// numbers and may not overlap; an arc with a canon flag applies it to all of
+// its episodes. The episodes it reflags get a revision of their own.
+func (s *EpisodeService) SetArcs(userID, contentID uint, arcs []models.StoryArc) ([]models.StoryArc, error) {
+	if _, err := s.contentRepo.FindByID(contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+
+	sort.Slice(arcs, func(i, j int) bool {
+		return arcs[i].FirstEpisode < arcs[j].FirstEpisode
+	})
+	for i := range arcs {
+		arc := &arcs[i]
+		arc.ID = 0
+		arc.ContentID = contentID
+		arc.Title = strings.TrimSpace(arc.Title)
+		if arc.Title == "" {
+			return nil, errors.New("every arc needs a title")
+		}
+		if arc.FirstEpisode < 1 || arc.LastEpisode < arc.FirstEpisode {
+			return nil, fmt.Errorf("arc %q: invalid episode range %d-%d", arc.Title, arc.FirstEpisode, arc.LastEpisode)
+		}
+		if arc.Canon != "" && !arc.Canon.IsValid() {
+			return nil, fmt.Errorf("arc %q: invalid canon flag: %s", arc.Title, arc.Canon)
+		}
+		if i > 0 && arc.FirstEpisode <= arcs[i-1].LastEpisode {
+			return nil, fmt.Errorf("arc %q overlaps arc %q", arc.Title, arcs[i-1].Title)
+		}
+	}
+
+	err := s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		reflagged, err := w.Episodes.ReplaceArcs(contentID, arcs)
+		if err != nil {
+			return err
+		}
+		for _, id := range reflagged {
+			if err := w.RecordEpisode(id, models.RevisionUpdate); err != nil {
+				return err
+			}
+		}
+		return w.RecordContent(contentID, models.RevisionUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.episodeRepo.ListArcs(contentID)
+}
+
+// GetGuide builds the episode guide of a content: the episodes the viewer may
+// see grouped by story arc, with their canon and filler counts
+func (s *EpisodeService) GetGuide(viewer *models.Viewer, contentID uint) (*models.EpisodeGuide, error) {
+	episodes, err := s.episodeRepo.ListByContentID(viewer, contentID, models.EpisodeFilter{IncludeSpecials: true})
+	if err != nil {
+		return nil, err
+	}
+	arcs, err := s.episodeRepo.ListArcs(contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	guide := &models.EpisodeGuide{
+		Arcs:      make([]models.GuideArc, len(arcs)),
+		Ungrouped: []models.Episode{},
+		Specials:  []models.Episode{},
+	}
+	for i, arc := range arcs {
+		guide.Arcs[i] = models.GuideArc{StoryArc: arc, Episodes: []models.Episode{}}
+	}
+
+	for _, episode := range episodes {
+		if episode.Type.IsSpecial() {
+			guide.Specials = append(guide.Specials, episode)
+			continue
+		}
+
+		switch episode.Canon {
+		case models.EpisodeCanonFiller:
+			guide.FillerCount++
+		case models.EpisodeCanonMixed:
+			guide.MixedCount++
+		default:
+			guide.CanonCount++
+		}
+
+		// Arcs are sorted and do not overlap, so the first one ending at or
+		// after the episode is the only one that can hold it
+		var arc *models.GuideArc
+		if episode.AbsoluteNumber != nil {
+			number := *episode.AbsoluteNumber
+			i := sort.Search(len(guide.Arcs), func(i int) bool {
+				return guide.Arcs[i].LastEpisode >= number
+			})
+			if i < len(guide.Arcs) && guide.Arcs[i].FirstEpisode <= number {
+				arc = &guide.Arcs[i]
+			}
+		}
+		if arc == nil {
+			guide.Ungrouped = append(guide.Ungrouped, episode)
+			continue
+		}
+		arc.Episodes = append(arc.Episodes, episode)
+		if episode.Canon == models.EpisodeCanonFiller {
+			arc.FillerCount++
+		}
+	}
+
+	// Arcs list episodes in absolute order, which can differ from season order
+	for i := range guide.Arcs {
+		episodes := guide.Arcs[i].Episodes
+		sort.SliceStable(episodes, func(a, b int) bool {
+			return *episodes[a].AbsoluteNumber < *episodes[b].AbsoluteNumber
+		})
+	}
+	return guide, nil
+}
+
+// ListChapters lists the chapters of an episode in playback order
+func (s *EpisodeService) ListChapters(episodeID uint) ([]models.Chapter, error) {
+	return s.episodeRepo.ListChapters(episodeID)
+}
+
+// SetChapters replaces the chapters of an episode. Chapters may not overlap
+// and must end within the video when its duration is known. Titles are kept
+// to a single line and may not contain the WebVTT cue arrow.
+func (s *EpisodeService) SetChapters(userID uint, episode *models.Episode, chapters []models.Chapter) ([]models.Chapter, error) {
+	return s.setChapters(userID, episode, chapters, nil)
+}
+
+// setChapters replaces the chapters of an episode, running also, if given,
+// in the same write
+func (s *EpisodeService) setChapters(userID uint, episode *models.Episode, chapters []models.Chapter, also func(w *RevisionWriter) error) ([]models.Chapter, error) {
+	sort.Slice(chapters, func(i, j int) bool {
+		return chapters[i].Start < chapters[j].Start
+	})
+	for i := range chapters {
+		chapter := &chapters[i]
+		chapter.ID = 0
+		chapter.EpisodeID = episode.ID
+		chapter.ContentID = episode.ContentID
+		chapter.Title = strings.Join(strings.Fields(chapter.Title), " ")
+		if strings.Contains(chapter.Title, "-->") {
+			return nil, fmt.Errorf("%s chapter: title may not contain \"-->\"", chapter.Kind)
+		}
+		if !chapter.Kind.IsValid() {
+			return nil, fmt.Errorf("invalid chapter kind: %s", chapter.Kind)
+		}
+		if chapter.Start < 0 || chapter.End <= chapter.Start {
+			return nil, fmt.Errorf("%s chapter: end must come after start", chapter.Kind)
+		}
+		if episode.Duration > 0 && chapter.End > float64(episode.Duration) {
+			return nil, fmt.Errorf("%s chapter ends after the video", chapter.Kind)
+		}
+		if i > 0 && chapter.Start < chapters[i-1].End {
+			return nil, fmt.Errorf("%s chapter overlaps the %s chapter before it", chapter.Kind, chapters[i-1].Kind)
+		}
+	}
+
+	err := s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		if also != nil {
+			if err := also(w); err != nil {
+				return err
+			}
+		}
+		if err := w.Episodes.ReplaceChapters(episode.ID, chapters); err != nil {
+			return err
+		}
+		return w.RecordEpisode(episode.ID, models.RevisionUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.episodeRepo.ListChapters(episode.ID)
+}
+
+// ChaptersWebVTT renders chapters as a WebVTT chapters track
+func ChaptersWebVTT(chapters []models.Chapter) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for i, chapter := range chapters {
+		title := chapter.Title
+		if title == "" {
+			title = chapter.Kind.Label()
+		}
+		fmt.Fprintf(&b, "\n%d\n%s --> %s\n%s\n", i+1, vttTimestamp(chapter.Start), vttTimestamp(chapter.End), vttCueText(title))
+	}
+	return b.String()
+}
+
+// vttCueText keeps a title on one line of cue text. Titles stored before
+// SetChapters checked them may still hold line breaks or markup.
+var vttCueText = strings.NewReplacer(
+	"\r\n", " ", "\n", " ", "\r", " ",
+	"&", "&amp;", "<", "&lt;", ">", "&gt;",
+).Replace
+
+// vttTimestamp formats seconds as a WebVTT timestamp, e.g. 00:01:30.500
+func vttTimestamp(seconds float64) string {
+	millis := int64(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", millis/3600000, millis/60000%60, millis/1000%60, millis%1000)
+}
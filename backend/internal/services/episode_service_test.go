@@ -0,0 +1,33 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+func TestChaptersWebVTT(t *testing.T) {
+	vtt := ChaptersWebVTT([]models.Chapter{
+		{Kind: models.ChapterIntro, Start: 0, End: 90.5},
+		{Kind: models.ChapterMain, Title: "Part A", Start: 90.5, End: 3725},
+		{Kind: models.ChapterCredits, Title: "Ending\n2\n00:00:00.000 --> 00:00:01.000\n<b>Injected</b> & more", Start: 3725, End: 3800},
+	})
+
+	assert.Equal(t, "WEBVTT\n"+
+		"\n1\n00:00:00.000 --> 00:01:30.500\nIntro\n"+
+		"\n2\n00:01:30.500 --> 01:02:05.000\nPart A\n"+
+		"\n3\n01:02:05.000 --> 01:03:20.000\nEnding 2 00:00:00.000 --&gt; 00:00:01.000 &lt;b&gt;Injected&lt;/b&gt; &amp; more\n", vtt)
+}
+
+func TestSetChaptersChecksTitles(t *testing.T) {
+	s := &EpisodeService{}
+	episode := &models.Episode{ID: 1, ContentID: 2}
+
+	_, err := s.SetChapters(1, episode, []models.Chapter{
+		{Kind: models.ChapterMain, Title: "A --> B", Start: 0, End: 10},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "-->")
+}
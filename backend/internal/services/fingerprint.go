@@ -0,0 +1,288 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/cmplx"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Audio fingerprints in the spirit of chromaprint: the audio is cut into
+// overlapping frames, each frame is reduced to the energy of the twelve pitch
+// classes, and every frame becomes a 32-bit word describing how that chroma
+// changes across pitch and time. The same music gives nearly the same words
+// whatever its volume, so an intro shared by two episodes shows up as a run of
+// matching words.
+const (
+	fingerprintSampleRate = 11025
+	fingerprintFrameSize  = 4096
+	fingerprintHop        = 1365 // two thirds overlap, about 8 frames a second
+
+	chromaMinFreq = 28.0
+	chromaMaxFreq = 3520.0
+
+	// Chroma energy below which a frame counts as silence, roughly 60 dB
+	// under a full-scale tone
+	fingerprintSilence = 1.0
+
+	// Frames match when their words differ in at most this many bits;
+	// unrelated audio differs in 16 on average
+	fingerprintMatchBits = 10
+)
+
+// fingerprintFrameSeconds is the time between two fingerprint frames
+const fingerprintFrameSeconds = float64(fingerprintHop) / fingerprintSampleRate
+
+// audioPrint is the fingerprint of a stretch of audio. Silent frames carry no
+// information and never match, or every fade to black would look like an intro.
+type audioPrint struct {
+	words  []uint32
+	silent []bool
+}
+
+// segmentMatch is a stretch of audio found in two fingerprints, in frames
+type segmentMatch struct {
+	startA     int
+	startB     int
+	length     int
+	similarity float64 // share of frames in the stretch that match
+}
+
+// probeDuration reads the length of a video in seconds with ffprobe
+func probeDuration(path string) (float64, error) {
+	out, err := exec.Command(
+		"ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		path,
+	).Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %v", err)
+	}
+	return strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+}
+
+// extractAudio decodes a window of a video's audio track with ffmpeg into
+// mono samples between -1 and 1
+func extractAudio(path string, start, length float64) ([]float64, error) {
+	cmd := exec.Command(
+		"ffmpeg",
+		"-v", "error",
+		"-ss", strconv.FormatFloat(start, 'f', 3, 64),
+		"-t", strconv.FormatFloat(length, 'f', 3, 64),
+		"-i", path,
+		"-vn",
+		"-ac", "1",
+		"-ar", strconv.Itoa(fingerprintSampleRate),
+		"-f", "s16le",
+		"-",
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	samples := make([]float64, len(out)/2)
+	for i := range samples {
+		samples[i] = float64(int16(binary.LittleEndian.Uint16(out[2*i:]))) / 32768
+	}
+	return samples, nil
+}
+
+// fingerprint computes the fingerprint of mono samples at fingerprintSampleRate
+func fingerprint(samples []float64) audioPrint {
+	if len(samples) < fingerprintFrameSize {
+		return audioPrint{}
+	}
+
+	window := make([]float64, fingerprintFrameSize)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(fingerprintFrameSize-1))
+	}
+	pitchClasses := chromaBins(fingerprintFrameSize, fingerprintSampleRate)
+
+	frames := (len(samples)-fingerprintFrameSize)/fingerprintHop + 1
+	chroma := make([][12]float64, frames)
+	silent := make([]bool, frames)
+	buf := make([]complex128, fingerprintFrameSize)
+	for f := 0; f < frames; f++ {
+		offset := f * fingerprintHop
+		for i := range buf {
+			buf[i] = complex(samples[offset+i]*window[i], 0)
+		}
+		fft(buf)
+
+		var energy float64
+		for i := 1; i < fingerprintFrameSize/2; i++ {
+			if pc := pitchClasses[i]; pc >= 0 {
+				power := real(buf[i])*real(buf[i]) + imag(buf[i])*imag(buf[i])
+				chroma[f][pc] += power
+				energy += power
+			}
+		}
+
+		if energy < fingerprintSilence {
+			silent[f] = true
+			continue
+		}
+		norm := 0.0
+		for _, v := range chroma[f] {
+			norm += v * v
+		}
+		norm = math.Sqrt(norm)
+		for i := range chroma[f] {
+			chroma[f][i] /= norm
+		}
+	}
+
+	// Every word compares a frame with the one before it
+	result := audioPrint{
+		words:  make([]uint32, frames-1),
+		silent: make([]bool, frames-1),
+	}
+	for f := 1; f < frames; f++ {
+		result.words[f-1] = chromaWord(chroma[f-1], chroma[f])
+		result.silent[f-1] = silent[f] || silent[f-1]
+	}
+	return result
+}
+
+// chromaBins maps each FFT bin to its pitch class, or -1 outside the range
+// of musical notes the fingerprint looks at
+func chromaBins(size, sampleRate int) []int {
+	bins := make([]int, size/2)
+	for i := range bins {
+		freq := float64(i) * float64(sampleRate) / float64(size)
+		if freq < chromaMinFreq || freq > chromaMaxFreq {
+			bins[i] = -1
+			continue
+		}
+		// Semitones above A440, folded into one octave
+		note := int(math.Round(12 * math.Log2(freq/440)))
+		bins[i] = ((note % 12) + 12) % 12
+	}
+	return bins
+}
+
+// chromaWord packs how the chroma of a frame relates to the previous frame and
+// to itself into 32 bits: 12 bits for the rise of every pitch class, 12 for
+// every pitch class being louder than the next one, and 8 for the same two
+// comparisons on four groups of three pitch classes
+func chromaWord(prev, cur [12]float64) uint32 {
+	var word uint32
+	for b := 0; b < 12; b++ {
+		if cur[b] > prev[b] {
+			word |= 1 << b
+		}
+		if cur[b] > cur[(b+1)%12] {
+			word |= 1 << (12 + b)
+		}
+	}
+
+	var curGroups, prevGroups [4]float64
+	for b := 0; b < 12; b++ {
+		curGroups[b/3] += cur[b]
+		prevGroups[b/3] += prev[b]
+	}
+	for g := 0; g < 4; g++ {
+		if curGroups[g] > prevGroups[g] {
+			word |= 1 << (24 + g)
+		}
+		if curGroups[g] > curGroups[(g+1)%4] {
+			word |= 1 << (28 + g)
+		}
+	}
+	return word
+}
+
+// fft computes the discrete Fourier transform in place; len(x) must be a power of two
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := x[start+k], w*x[start+k+size/2]
+				x[start+k] = even + odd
+				x[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
+
+// longestSharedSegment slides one fingerprint along the other and returns the
+// longest stretch where they match. Short gaps of up to maxGap frames are
+// bridged so a single noisy frame does not split an intro in two.
+func longestSharedSegment(a, b audioPrint, minFrames, maxFrames, maxGap int) (segmentMatch, bool) {
+	var best segmentMatch
+	found := false
+
+	consider := func(offset, start, end, matched int) {
+		length := end - start + 1
+		if length < minFrames || length > maxFrames {
+			return
+		}
+		similarity := float64(matched) / float64(length)
+		if similarity < 0.5 {
+			return
+		}
+		if !found || length > best.length || (length == best.length && similarity > best.similarity) {
+			best = segmentMatch{startA: start, startB: start - offset, length: length, similarity: similarity}
+			found = true
+		}
+	}
+
+	// Frame i of a lines up with frame i-offset of b
+	for offset := -(len(b.words) - 1); offset < len(a.words); offset++ {
+		from := offset
+		if from < 0 {
+			from = 0
+		}
+		to := len(b.words) + offset
+		if to > len(a.words) {
+			to = len(a.words)
+		}
+
+		runStart, lastMatch, matched := -1, 0, 0
+		for i := from; i < to; i++ {
+			j := i - offset
+			match := !a.silent[i] && !b.silent[j] &&
+				bits.OnesCount32(a.words[i]^b.words[j]) <= fingerprintMatchBits
+			if match {
+				if runStart < 0 {
+					runStart, matched = i, 0
+				}
+				lastMatch = i
+				matched++
+			} else if runStart >= 0 && i-lastMatch > maxGap {
+				consider(offset, runStart, lastMatch, matched)
+				runStart = -1
+			}
+		}
+		if runStart >= 0 {
+			consider(offset, runStart, lastMatch, matched)
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// melody renders a tune of pure tones, a new note every 0.3 seconds
+func melody(seconds, volume float64) []float64 {
+	notes := []float64{440, 523.25, 659.25, 392, 587.33, 493.88, 349.23, 783.99, 329.63, 698.46}
+	samples := make([]float64, int(seconds*fingerprintSampleRate))
+	noteLength := fingerprintSampleRate * 3 / 10
+	for i := range samples {
+		freq := notes[(i/noteLength)%len(notes)]
+		samples[i] = volume * math.Sin(2*math.Pi*freq*float64(i)/fingerprintSampleRate)
+	}
+	return samples
+}
+
+// noise renders seconds of white noise that differs for every seed
+func noise(seconds float64, seed int64) []float64 {
+	r := rand.New(rand.NewSource(seed))
+	samples := make([]float64, int(seconds*fingerprintSampleRate))
+	for i := range samples {
+		samples[i] = 0.3 * (2*r.Float64() - 1)
+	}
+	return samples
+}
+
+func concat(parts ...[]float64) []float64 {
+	var samples []float64
+	for _, part := range parts {
+		samples = append(samples, part...)
+	}
+	return samples
+}
+
+// hops returns a stretch of noise lasting a whole number of fingerprint hops, so
+// that what follows it lines up with the frames
+func hops(n int, seed int64) []float64 {
+	return noise(float64(n*fingerprintHop)/fingerprintSampleRate, seed)[:n*fingerprintHop]
+}
+
+func TestFFT(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 8, 64} {
+		input := make([]complex128, n)
+		for i := range input {
+			input[i] = complex(r.Float64()-0.5, r.Float64()-0.5)
+		}
+
+		// The naive transform the FFT has to agree with
+		want := make([]complex128, n)
+		for k := range want {
+			for i, x := range input {
+				want[k] += x * cmplx.Exp(complex(0, -2*math.Pi*float64(k*i)/float64(n)))
+			}
+		}
+
+		got := append([]complex128(nil), input...)
+		fft(got)
+		for k := range want {
+			assert.InDelta(t, real(want[k]), real(got[k]), 1e-9, "n=%d bin %d", n, k)
+			assert.InDelta(t, imag(want[k]), imag(got[k]), 1e-9, "n=%d bin %d", n, k)
+		}
+	}
+
+	// A tone that fits the frame exactly lands in its bin and the mirrored one
+	n, bin := 256, 10
+	tone := make([]complex128, n)
+	for i := range tone {
+		tone[i] = complex(math.Cos(2*math.Pi*float64(bin*i)/float64(n)), 0)
+	}
+	fft(tone)
+	for k, v := range tone {
+		if k == bin || k == n-bin {
+			assert.InDelta(t, float64(n)/2, cmplx.Abs(v), 1e-9)
+		} else {
+			assert.InDelta(t, 0, cmplx.Abs(v), 1e-9, "bin %d", k)
+		}
+	}
+}
+
+func TestChromaWord(t *testing.T) {
+	flat := [12]float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	a := [12]float64{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+
+	tests := []struct {
+		name      string
+		prev, cur [12]float64
+		want      uint32
+	}{
+		{name: "nothing changes", prev: flat, cur: flat, want: 0},
+		{
+			name: "one pitch class rises",
+			prev: [12]float64{}, cur: a,
+			// A rose, A is louder than A#, the first group rose and is louder than the second
+			want: 1<<0 | 1<<12 | 1<<24 | 1<<28,
+		},
+		{
+			name: "one pitch class falls",
+			prev: a, cur: [12]float64{},
+			want: 0,
+		},
+		{
+			name: "every pitch class is louder than the next but the last",
+			prev: [12]float64{12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, cur: [12]float64{12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			// Groups 12+11+10 > 9+8+7 > 6+5+4 > 3+2+1, and the last group is quieter than the first
+			want: 0x7ff<<12 | 0x7<<28,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, chromaWord(tt.prev, tt.cur))
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Run("too short for a frame", func(t *testing.T) {
+		print := fingerprint(make([]float64, fingerprintFrameSize-1))
+		assert.Empty(t, print.words)
+	})
+
+	t.Run("one word per pair of frames", func(t *testing.T) {
+		print := fingerprint(melody(3, 0.5))
+		frames := (int(3*fingerprintSampleRate)-fingerprintFrameSize)/fingerprintHop + 1
+		assert.Len(t, print.words, frames-1)
+		assert.Len(t, print.silent, frames-1)
+	})
+
+	t.Run("silence", func(t *testing.T) {
+		print := fingerprint(make([]float64, 5*fingerprintSampleRate))
+		require.NotEmpty(t, print.silent)
+		for i, silent := range print.silent {
+			assert.True(t, silent, "frame %d", i)
+		}
+	})
+
+	t.Run("volume does not matter", func(t *testing.T) {
+		loud := fingerprint(melody(5, 0.8))
+		quiet := fingerprint(melody(5, 0.05))
+		require.Equal(t, len(loud.words), len(quiet.words))
+		for i := range loud.words {
+			assert.False(t, loud.silent[i] || quiet.silent[i], "frame %d", i)
+			assert.LessOrEqual(t, bits.OnesCount32(loud.words[i]^quiet.words[i]), fingerprintMatchBits, "frame %d", i)
+		}
+	})
+}
+
+// prints builds fingerprints straight from words; zero words are silent
+func prints(words ...uint32) audioPrint {
+	print := audioPrint{words: words, silent: make([]bool, len(words))}
+	for i, word := range words {
+		print.silent[i] = word == 0
+	}
+	return print
+}
+
+// sequence returns n distinct words far apart from each other and from those of other seeds
+func sequence(n int, seed int64) []uint32 {
+	r := rand.New(rand.NewSource(seed))
+	words := make([]uint32, n)
+	for i := range words {
+		words[i] = r.Uint32() | 1
+	}
+	return words
+}
+
+func join(parts ...[]uint32) []uint32 {
+	var words []uint32
+	for _, part := range parts {
+		words = append(words, part...)
+	}
+	return words
+}
+
+func TestLongestSharedSegment(t *testing.T) {
+	shared := sequence(40, 1)
+
+	tests := []struct {
+		name      string
+		a, b      audioPrint
+		min, max  int
+		gap       int
+		wantFound bool
+		want      segmentMatch
+	}{
+		{
+			name: "shared segment at different offsets",
+			a:    prints(join(sequence(5, 2), shared, sequence(30, 3))...),
+			b:    prints(join(sequence(22, 4), shared, sequence(3, 5))...),
+			min:  10, max: 100,
+			wantFound: true,
+			want:      segmentMatch{startA: 5, startB: 22, length: 40, similarity: 1},
+		},
+		{
+			name: "shared segment at the same offset",
+			a:    prints(join(shared, sequence(10, 6))...),
+			b:    prints(join(shared, sequence(10, 7))...),
+			min:  10, max: 100,
+			wantFound: true,
+			want:      segmentMatch{startA: 0, startB: 0, length: 40, similarity: 1},
+		},
+		{
+			name: "a noisy frame is bridged",
+			a:    prints(join(sequence(3, 8), shared[:20], sequence(1, 9), shared[21:])...),
+			b:    prints(join(shared, sequence(3, 10))...),
+			min:  10, max: 100, gap: 2,
+			wantFound: true,
+			want:      segmentMatch{startA: 3, startB: 0, length: 40, similarity: 39.0 / 40},
+		},
+		{
+			name: "without a gap the noisy frame splits the segment",
+			a:    prints(join(sequence(3, 8), shared[:20], sequence(1, 9), shared[21:])...),
+			b:    prints(join(shared, sequence(3, 10))...),
+			min:  10, max: 100,
+			wantFound: true,
+			want:      segmentMatch{startA: 3, startB: 0, length: 20, similarity: 1},
+		},
+		{
+			name: "silence never matches",
+			a:    prints(make([]uint32, 60)...),
+			b:    prints(make([]uint32, 60)...),
+			min:  10, max: 100,
+		},
+		{
+			name: "silence inside a segment is not matched",
+			a:    prints(join(shared[:12], make([]uint32, 10), shared[22:])...),
+			b:    prints(join(shared[:12], make([]uint32, 10), shared[22:])...),
+			min:  10, max: 100,
+			wantFound: true,
+			want:      segmentMatch{startA: 22, startB: 22, length: 18, similarity: 1},
+		},
+		{
+			name: "shorter than the minimum",
+			a:    prints(join(sequence(5, 11), shared[:9], sequence(5, 12))...),
+			b:    prints(join(shared[:9], sequence(8, 13))...),
+			min:  10, max: 100,
+		},
+		{
+			name: "exactly the minimum",
+			a:    prints(join(sequence(5, 11), shared[:10], sequence(5, 12))...),
+			b:    prints(join(shared[:10], sequence(8, 13))...),
+			min:  10, max: 100,
+			wantFound: true,
+			want:      segmentMatch{startA: 5, startB: 0, length: 10, similarity: 1},
+		},
+		{
+			name: "longer than the maximum",
+			a:    prints(join(sequence(2, 14), shared)...),
+			b:    prints(join(shared, sequence(2, 15))...),
+			min:  10, max: 39,
+		},
+		{
+			name: "unrelated audio",
+			a:    prints(sequence(60, 16)...),
+			b:    prints(sequence(60, 17)...),
+			min:  10, max: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, found := longestSharedSegment(tt.a, tt.b, tt.min, tt.max, tt.gap)
+			assert.Equal(t, tt.wantFound, found)
+			if tt.wantFound {
+				assert.Equal(t, tt.want.startA, match.startA)
+				assert.Equal(t, tt.want.startB, match.startB)
+				assert.Equal(t, tt.want.length, match.length)
+				assert.InDelta(t, tt.want.similarity, match.similarity, 1e-9)
+			}
+		})
+	}
+}
+
+func TestLongestSharedSegmentOfAudio(t *testing.T) {
+	// The same intro 10 and 25 hops into two episodes that otherwise differ
+	intro := melody(6, 0.5)
+	a := fingerprint(concat(hops(10, 1), intro, hops(40, 2)))
+	b := fingerprint(concat(hops(25, 3), intro, hops(20, 4)))
+
+	introFrames := secondsToFrames(6)
+	match, found := longestSharedSegment(a, b, introFrames/2, 2*introFrames, 2)
+	require.True(t, found)
+	assert.Equal(t, 15, match.startB-match.startA, "the offset between the episodes")
+	assert.InDelta(t, 10, match.startA, 3)
+	assert.InDelta(t, introFrames, match.length, float64(fingerprintFrameSize/fingerprintHop)+2)
+	assert.GreaterOrEqual(t, match.similarity, 0.9)
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{name: "one value", values: []float64{4}, want: 4},
+		{name: "odd count, unsorted", values: []float64{9, 1, 5}, want: 5},
+		{name: "even count takes the mean of the middle two", values: []float64{10, 2, 4, 8}, want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := append([]float64(nil), tt.values...)
+			assert.Equal(t, tt.want, median(values))
+			assert.Equal(t, tt.values, values, "the input is left unsorted")
+		})
+	}
+}
+
+func TestConsensus(t *testing.T) {
+	tests := []struct {
+		name           string
+		segments       []detectedSegment
+		peers          int
+		wantFound      bool
+		want           detectedSegment
+		wantConfidence float64
+	}{
+		{name: "no segments", peers: 4},
+		{name: "no peers", segments: []detectedSegment{{start: 1, end: 90, similarity: 1}}},
+		{
+			name: "the neighbours that agree win over an outlier",
+			segments: []detectedSegment{
+				{start: 10, end: 100, similarity: 0.9},
+				{start: 12, end: 101, similarity: 0.7},
+				{start: 60, end: 150, similarity: 1},
+			},
+			peers:          4,
+			wantFound:      true,
+			want:           detectedSegment{start: 11, end: 100.5, similarity: 0.8},
+			wantConfidence: 0.4,
+		},
+		{
+			name: "every neighbour agrees",
+			segments: []detectedSegment{
+				{start: 30, end: 120, similarity: 1},
+				{start: 31, end: 121, similarity: 1},
+			},
+			peers:          2,
+			wantFound:      true,
+			want:           detectedSegment{start: 30.5, end: 120.5, similarity: 1},
+			wantConfidence: 1,
+		},
+		{
+			name:     "too little support",
+			segments: []detectedSegment{{start: 10, end: 100, similarity: 0.6}},
+			peers:    4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segment, confidence, found := consensus(tt.segments, tt.peers)
+			assert.Equal(t, tt.wantFound, found)
+			if tt.wantFound {
+				assert.InDelta(t, tt.want.start, segment.start, 1e-9)
+				assert.InDelta(t, tt.want.end, segment.end, 1e-9)
+				assert.InDelta(t, tt.want.similarity, segment.similarity, 1e-9)
+				assert.InDelta(t, tt.wantConfidence, confidence, 1e-9)
+			}
+		})
+	}
+}
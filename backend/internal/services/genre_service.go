@@ -10,19 +10,27 @@ import (
 
 // GenreService handles genre related operations
 type GenreService struct {
-	db *gorm.DB
+	db          *gorm.DB
+	slugService *SlugService
 }
 
 // NewGenreService creates a new GenreService
-func NewGenreService(db *gorm.DB) *GenreService {
+func NewGenreService(db *gorm.DB, slugService *SlugService) *GenreService {
 	return &GenreService{
-		db: db,
+		db:          db,
+		slugService: slugService,
 	}
 }
 
 // CreateGenre creates a new genre
 func (s *GenreService) CreateGenre(genre *models.Genre) error {
 	log.Printf("Creating new genre: %s", genre.Name)
+	slug, _, err := s.slugService.Pick(models.SlugGenre, 0, genre.Slug, genre.Name)
+	if err != nil {
+		return err
+	}
+	genre.Slug = slug
+
 	result := s.db.Create(genre)
 	if result.Error != nil {
 		log.Printf("Failed to create genre: %v", result.Error)
@@ -54,15 +62,31 @@ func (s *GenreService) ListGenres() ([]models.Genre, error) {
 	return genres, nil
 }
 
-// UpdateGenre updates an existing genre
+// UpdateGenre updates an existing genre if it is still at the version it was read at
 func (s *GenreService) UpdateGenre(genre *models.Genre) error {
-	result := s.db.Save(genre)
+	slug, previous, err := s.slugService.Pick(models.SlugGenre, genre.ID, genre.Slug, genre.Name)
+	if err != nil {
+		return err
+	}
+	genre.Slug = slug
+
+	expected := genre.Version
+	genre.Version++
+
+	result := s.db.Model(genre).
+		Where("version = ?", expected).
+		Select("*").
+		Omit("Contents", "created_at").
+		Updates(genre)
 	if result.Error != nil {
+		genre.Version = expected
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("genre not found")
+		genre.Version = expected
+		return models.ErrVersionConflict
 	}
+	s.slugService.Moved(models.SlugGenre, genre.ID, previous, slug)
 	return nil
 }
 
@@ -0,0 +1,429 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"math"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+// Intro detection settings. Intros are looked for in the first minutes of an
+// episode and credits in the last ones; a chapter needs a shared stretch of
+// audio between the given lengths.
+const (
+	introSearchWindow   = 300.0
+	creditsSearchWindow = 300.0
+	minDetectedChapter  = 15.0
+	maxDetectedIntro    = 150.0
+	maxDetectedCredits  = 180.0
+	detectionMaxGap     = 1.0
+
+	// Every episode is compared with this many episodes on either side
+	detectionPeers = 2
+	// Matches whose start and end lie this close together agree with each other
+	detectionTolerance = 3.0
+	// Suggestions below this confidence are not worth an admin's time
+	minSuggestionConfidence = 0.25
+)
+
+var (
+	errSeasonAnalyzing    = errors.New("this season is already being analyzed")
+	errSuggestionReviewed = errors.New("suggestion has already been reviewed")
+)
+
+// IntroDetectionService finds the intro and credits shared by the episodes of
+// a season by fingerprinting their audio, and proposes them as chapters
+type IntroDetectionService struct {
+	analysisRepo   *repository.IntroAnalysisRepository
+	episodeRepo    *repository.EpisodeRepository
+	contentRepo    *repository.ContentRepository
+	episodeService *EpisodeService
+	mediaPath      string
+
+	// Decoding audio is heavy, so analyses run one at a time
+	slots chan struct{}
+}
+
+// NewIntroDetectionService creates a new IntroDetectionService
+func NewIntroDetectionService(
+	analysisRepo *repository.IntroAnalysisRepository,
+	episodeRepo *repository.EpisodeRepository,
+	contentRepo *repository.ContentRepository,
+	episodeService *EpisodeService,
+	mediaPath string,
+) *IntroDetectionService {
+	return &IntroDetectionService{
+		analysisRepo:   analysisRepo,
+		episodeRepo:    episodeRepo,
+		contentRepo:    contentRepo,
+		episodeService: episodeService,
+		mediaPath:      mediaPath,
+		slots:          make(chan struct{}, 1),
+	}
+}
+
+// episodeAudio holds the fingerprints of the start and end of an episode
+type episodeAudio struct {
+	episode      models.Episode
+	duration     float64
+	intro        audioPrint
+	credits      audioPrint
+	creditsStart float64
+}
+
+// detectedSegment is a shared stretch of audio on one episode's timeline, in seconds
+type detectedSegment struct {
+	start      float64
+	end        float64
+	similarity float64
+}
+
+// RequestAnalysis queues an analysis of a season of a content; it runs in the background
+func (s *IntroDetectionService) RequestAnalysis(contentID uint, seasonNumber int) (*models.IntroAnalysis, error) {
+	if _, err := s.contentRepo.FindByID(contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+
+	analysis := &models.IntroAnalysis{
+		ContentID:    contentID,
+		SeasonNumber: seasonNumber,
+		Status:       models.IntroAnalysisPending,
+	}
+	created, err := s.analysisRepo.CreateUnlessRunning(analysis)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, errSeasonAnalyzing
+	}
+
+	go s.runAnalysis(analysis.ID)
+
+	return analysis, nil
+}
+
+// FailInterrupted marks the analyses cut off by a restart as failed so their
+// seasons can be analyzed again
+func (s *IntroDetectionService) FailInterrupted() {
+	if err := s.analysisRepo.FailUnfinished("interrupted by a restart"); err != nil {
+		log.Printf("Failed to clean up intro analyses: %v", err)
+	}
+}
+
+// GetAnalysis retrieves an analysis with its suggestions
+func (s *IntroDetectionService) GetAnalysis(id uint) (*models.IntroAnalysis, error) {
+	return s.analysisRepo.FindByID(id)
+}
+
+// ListAnalyses lists the latest analyses, optionally for one content
+func (s *IntroDetectionService) ListAnalyses(contentID *uint) ([]models.IntroAnalysis, error) {
+	return s.analysisRepo.List(contentID)
+}
+
+// ListSuggestions lists detected chapters, optionally for one content and in one review state
+func (s *IntroDetectionService) ListSuggestions(contentID *uint, status models.ChapterSuggestionStatus, page, pageSize int) ([]models.ChapterSuggestion, int64, error) {
+	return s.analysisRepo.ListSuggestions(contentID, status, page, pageSize)
+}
+
+// AcceptSuggestion turns a suggestion into a chapter of its episode, replacing
+// any chapter of the same kind, and returns the episode's chapters. The
+// chapters and the review are written together, and the reviewing user is
+// credited with the episode revision.
+func (s *IntroDetectionService) AcceptSuggestion(userID, id uint) ([]models.Chapter, error) {
+	suggestion, err := s.analysisRepo.FindSuggestion(id)
+	if err != nil {
+		return nil, errors.New("suggestion not found")
+	}
+	if suggestion.Status != models.ChapterSuggestionPending {
+		return nil, errSuggestionReviewed
+	}
+
+	episode, err := s.episodeRepo.FindByID(suggestion.EpisodeID)
+	if err != nil {
+		return nil, errors.New("episode not found")
+	}
+	current, err := s.episodeRepo.ListChapters(episode.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	chapters := make([]models.Chapter, 0, len(current)+1)
+	for _, chapter := range current {
+		if chapter.Kind != suggestion.Kind {
+			chapters = append(chapters, chapter)
+		}
+	}
+	end := suggestion.End
+	if episode.Duration > 0 && end > float64(episode.Duration) {
+		end = float64(episode.Duration)
+	}
+	chapters = append(chapters, models.Chapter{Kind: suggestion.Kind, Start: suggestion.Start, End: end})
+
+	return s.episodeService.setChapters(userID, episode, chapters, func(w *RevisionWriter) error {
+		reviewed, err := w.Analyses.ReviewSuggestion(id, models.ChapterSuggestionAccepted)
+		if err != nil {
+			return err
+		}
+		if !reviewed {
+			return errSuggestionReviewed
+		}
+		return nil
+	})
+}
+
+// RejectSuggestion dismisses a suggestion
+func (s *IntroDetectionService) RejectSuggestion(id uint) error {
+	suggestion, err := s.analysisRepo.FindSuggestion(id)
+	if err != nil {
+		return errors.New("suggestion not found")
+	}
+	if suggestion.Status != models.ChapterSuggestionPending {
+		return errSuggestionReviewed
+	}
+	reviewed, err := s.analysisRepo.ReviewSuggestion(id, models.ChapterSuggestionRejected)
+	if err != nil {
+		return err
+	}
+	if !reviewed {
+		return errSuggestionReviewed
+	}
+	return nil
+}
+
+// runAnalysis fingerprints the episodes of an analysis job and stores the
+// chapters they share as suggestions
+func (s *IntroDetectionService) runAnalysis(analysisID uint) {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	analysis, err := s.analysisRepo.FindByID(analysisID)
+	if err != nil {
+		log.Printf("Intro analysis %d not found: %v", analysisID, err)
+		return
+	}
+
+	analysis.Status = models.IntroAnalysisProcessing
+	if err := s.analysisRepo.Update(analysis); err != nil {
+		log.Printf("Failed to update intro analysis %d: %v", analysisID, err)
+		return
+	}
+
+	suggested, episodeCount, err := s.analyze(analysis)
+	now := time.Now()
+	analysis.EpisodeCount = episodeCount
+	if err != nil {
+		log.Printf("Intro analysis %d failed: %v", analysisID, err)
+		analysis.Status = models.IntroAnalysisFailed
+		analysis.Error = err.Error()
+	} else {
+		log.Printf("Intro analysis %d suggested %d chapters for %d episodes", analysisID, suggested, episodeCount)
+		analysis.Status = models.IntroAnalysisCompleted
+		analysis.Suggested = suggested
+		analysis.CompletedAt = &now
+	}
+
+	if err := s.analysisRepo.Update(analysis); err != nil {
+		log.Printf("Failed to update intro analysis %d: %v", analysisID, err)
+	}
+}
+
+// analyze runs the detection for one season and stores the suggestions. It
+// returns how many chapters were suggested and how many episodes were compared.
+func (s *IntroDetectionService) analyze(analysis *models.IntroAnalysis) (int, int, error) {
+	season := analysis.SeasonNumber
+	episodes, err := s.episodeRepo.ListByContentID(nil, analysis.ContentID, models.EpisodeFilter{Season: &season})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	audio := make([]episodeAudio, 0, len(episodes))
+	for _, episode := range episodes {
+		if episode.VideoPath == "" {
+			continue
+		}
+		fingerprinted, err := s.fingerprintEpisode(episode)
+		if err != nil {
+			log.Printf("Skipping episode %d in intro analysis %d: %v", episode.ID, analysis.ID, err)
+			continue
+		}
+		audio = append(audio, fingerprinted)
+	}
+	if len(audio) < 2 {
+		return 0, len(audio), errors.New("at least two episodes with a video are needed")
+	}
+
+	introMatches := matchEpisodes(audio, func(a episodeAudio) audioPrint { return a.intro }, maxDetectedIntro)
+	creditsMatches := matchEpisodes(audio, func(a episodeAudio) audioPrint { return a.credits }, maxDetectedCredits)
+
+	var suggestions []models.ChapterSuggestion
+	episodeIDs := make([]uint, 0, len(audio))
+	for i, a := range audio {
+		episodeIDs = append(episodeIDs, a.episode.ID)
+		peers := len(detectionNeighbours(i, len(audio)))
+
+		if segment, confidence, ok := consensus(introMatches[i], peers); ok {
+			// An intro that starts within a second and a half is meant to open the episode
+			if segment.start < 1.5 {
+				segment.start = 0
+			}
+			suggestions = append(suggestions, newSuggestion(analysis, a.episode, models.ChapterIntro, segment, confidence))
+		}
+
+		if segment, confidence, ok := consensus(creditsMatches[i], peers); ok {
+			segment.start += a.creditsStart
+			segment.end += a.creditsStart
+			// Credits that end within two seconds of the video run to its end
+			if a.duration-segment.end < 2 {
+				segment.end = a.duration
+			}
+			suggestions = append(suggestions, newSuggestion(analysis, a.episode, models.ChapterCredits, segment, confidence))
+		}
+	}
+
+	if err := s.analysisRepo.ReplacePendingSuggestions(episodeIDs, suggestions); err != nil {
+		return 0, len(audio), err
+	}
+	return len(suggestions), len(audio), nil
+}
+
+// fingerprintEpisode decodes and fingerprints the start and end of an episode
+func (s *IntroDetectionService) fingerprintEpisode(episode models.Episode) (episodeAudio, error) {
+	path := filepath.Join(s.mediaPath, episode.VideoPath)
+	duration, err := probeDuration(path)
+	if err != nil {
+		if episode.Duration <= 0 {
+			return episodeAudio{}, err
+		}
+		duration = float64(episode.Duration)
+	}
+
+	intro, err := extractAudio(path, 0, math.Min(introSearchWindow, duration))
+	if err != nil {
+		return episodeAudio{}, err
+	}
+	creditsStart := math.Max(0, duration-creditsSearchWindow)
+	credits, err := extractAudio(path, creditsStart, duration-creditsStart)
+	if err != nil {
+		return episodeAudio{}, err
+	}
+
+	return episodeAudio{
+		episode:      episode,
+		duration:     duration,
+		intro:        fingerprint(intro),
+		credits:      fingerprint(credits),
+		creditsStart: creditsStart,
+	}, nil
+}
+
+// detectionNeighbours lists the episodes an episode is compared with
+func detectionNeighbours(i, count int) []int {
+	var neighbours []int
+	for j := i - detectionPeers; j <= i+detectionPeers; j++ {
+		if j != i && j >= 0 && j < count {
+			neighbours = append(neighbours, j)
+		}
+	}
+	return neighbours
+}
+
+// matchEpisodes compares every episode with its neighbours and returns, per
+// episode, the shared segments found on its own timeline in seconds from the
+// start of the fingerprinted window. Every pair is only compared once.
+func matchEpisodes(audio []episodeAudio, print func(episodeAudio) audioPrint, maxSeconds float64) [][]detectedSegment {
+	minFrames := secondsToFrames(minDetectedChapter)
+	maxFrames := secondsToFrames(maxSeconds)
+	maxGap := secondsToFrames(detectionMaxGap)
+
+	segments := make([][]detectedSegment, len(audio))
+	for i := range audio {
+		for _, j := range detectionNeighbours(i, len(audio)) {
+			if j < i {
+				continue
+			}
+			match, ok := longestSharedSegment(print(audio[i]), print(audio[j]), minFrames, maxFrames, maxGap)
+			if !ok {
+				continue
+			}
+			length := float64(match.length) * fingerprintFrameSeconds
+			startI := float64(match.startA) * fingerprintFrameSeconds
+			startJ := float64(match.startB) * fingerprintFrameSeconds
+			segments[i] = append(segments[i], detectedSegment{start: startI, end: startI + length, similarity: match.similarity})
+			segments[j] = append(segments[j], detectedSegment{start: startJ, end: startJ + length, similarity: match.similarity})
+		}
+	}
+	return segments
+}
+
+// consensus picks the segment most of an episode's neighbours agree on. The
+// confidence is the share of neighbours that agree times how well they match.
+func consensus(segments []detectedSegment, peers int) (detectedSegment, float64, bool) {
+	if len(segments) == 0 || peers == 0 {
+		return detectedSegment{}, 0, false
+	}
+
+	var support []detectedSegment
+	for _, candidate := range segments {
+		var agreeing []detectedSegment
+		for _, other := range segments {
+			if math.Abs(other.start-candidate.start) <= detectionTolerance && math.Abs(other.end-candidate.end) <= detectionTolerance {
+				agreeing = append(agreeing, other)
+			}
+		}
+		if len(agreeing) > len(support) {
+			support = agreeing
+		}
+	}
+
+	starts := make([]float64, len(support))
+	ends := make([]float64, len(support))
+	similarity := 0.0
+	for i, segment := range support {
+		starts[i] = segment.start
+		ends[i] = segment.end
+		similarity += segment.similarity
+	}
+	similarity /= float64(len(support))
+
+	confidence := float64(len(support)) / float64(peers) * similarity
+	confidence = math.Round(confidence*100) / 100
+	if confidence < minSuggestionConfidence {
+		return detectedSegment{}, 0, false
+	}
+	return detectedSegment{start: median(starts), end: median(ends), similarity: similarity}, confidence, true
+}
+
+// newSuggestion builds a suggestion for an episode, rounded to tenths of a second
+func newSuggestion(analysis *models.IntroAnalysis, episode models.Episode, kind models.ChapterKind, segment detectedSegment, confidence float64) models.ChapterSuggestion {
+	return models.ChapterSuggestion{
+		AnalysisID: analysis.ID,
+		ContentID:  analysis.ContentID,
+		EpisodeID:  episode.ID,
+		Kind:       kind,
+		Start:      math.Round(segment.start*10) / 10,
+		End:        math.Round(segment.end*10) / 10,
+		Confidence: confidence,
+		Status:     models.ChapterSuggestionPending,
+	}
+}
+
+// secondsToFrames converts a duration to a number of fingerprint frames
+func secondsToFrames(seconds float64) int {
+	return int(math.Round(seconds / fingerprintFrameSeconds))
+}
+
+// median returns the middle value of a non-empty list
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,460 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+const (
+	// Links of each kind checked per round; the rest wait for the next one
+	linkCheckBatch = 500
+	// Hosts probed at the same time
+	linkCheckHosts = 8
+	// Sent with every probe so site owners can tell what is knocking
+	linkCheckUserAgent = "anime-streaming-link-checker/1.0"
+	// How long a host that throttles without saying for how long is left alone
+	linkRetryAfterDefault = 5 * time.Minute
+	// Longest Retry-After that is honored
+	linkRetryAfterMax = 24 * time.Hour
+)
+
+// errNotCheckable is returned for links without an http(s) URL, e.g. local files
+var errNotCheckable = errors.New("not an http(s) URL, not checked")
+
+// iframeSrc finds the URL of an embed code
+var iframeSrc = regexp.MustCompile(`(?i)\bsrc\s*=\s*["']?([^"'\s>]+)`)
+
+// LinkHealthService probes the URLs of external stream and download links in
+// the background and keeps their health up to date. Links that are reported
+// missing by too many checks in a row count as dead and are hidden from
+// viewers; throttling, server trouble and timeouts do not count.
+type LinkHealthService struct {
+	linkRepo     *repository.LinkRepository
+	client       *http.Client
+	limiter      *hostLimiter
+	deadAfter    int
+	recheckAfter time.Duration
+
+	// Held for the duration of a round so rounds never overlap
+	running sync.Mutex
+}
+
+// NewLinkHealthService creates a new LinkHealthService. Links are checked again
+// once their last check is older than recheckAfter, and requests to the same
+// host are at least hostDelay apart.
+func NewLinkHealthService(
+	linkRepo *repository.LinkRepository,
+	client *http.Client,
+	deadAfter int,
+	recheckAfter time.Duration,
+	hostDelay time.Duration,
+) *LinkHealthService {
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	if deadAfter < 1 {
+		deadAfter = 1
+	}
+	return &LinkHealthService{
+		linkRepo:     linkRepo,
+		client:       client,
+		limiter:      newHostLimiter(hostDelay),
+		deadAfter:    deadAfter,
+		recheckAfter: recheckAfter,
+	}
+}
+
+// linkCheck is a link being checked, with its health before and after the check
+type linkCheck struct {
+	kind   models.LinkKind
+	id     uint
+	url    string
+	health models.LinkHealth
+	// The host asked to be left alone; the link waits for a later round
+	deferred bool
+}
+
+// linkProbe is the answer to a single request
+type linkProbe struct {
+	status     int
+	latency    time.Duration
+	retryAfter time.Duration
+	err        error
+}
+
+// StartScheduler checks the links that are due at the given interval
+func (s *LinkHealthService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.CheckDue(); err != nil {
+				log.Printf("Link health check failed: %v", err)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+// CheckDue checks the links whose last check is older than the recheck interval
+func (s *LinkHealthService) CheckDue() error {
+	if !s.running.TryLock() {
+		return nil
+	}
+	defer s.running.Unlock()
+
+	checkedBefore := time.Now().Add(-s.recheckAfter)
+	streamLinks, err := s.linkRepo.DueStreamLinks(checkedBefore, linkCheckBatch)
+	if err != nil {
+		return err
+	}
+	downloadLinks, err := s.linkRepo.DueDownloadLinks(checkedBefore, linkCheckBatch)
+	if err != nil {
+		return err
+	}
+
+	checks := make([]linkCheck, 0, len(streamLinks)+len(downloadLinks))
+	for _, link := range streamLinks {
+		checks = append(checks, linkCheck{kind: models.LinkKindStream, id: link.ID, url: link.URL, health: link.LinkHealth})
+	}
+	for _, link := range downloadLinks {
+		checks = append(checks, linkCheck{kind: models.LinkKindDownload, id: link.ID, url: link.URL, health: link.LinkHealth})
+	}
+	if len(checks) == 0 {
+		return nil
+	}
+
+	dead, deferred := 0, 0
+	for _, check := range s.probeAll(checks) {
+		if check.deferred {
+			deferred++
+			continue
+		}
+		if err := s.linkRepo.SaveHealth(check.kind, check.id, check.url, check.health); err != nil {
+			log.Printf("Failed to save health of %s link %d: %v", check.kind, check.id, err)
+		}
+		if check.health.HealthStatus == models.LinkHealthDead {
+			dead++
+		}
+	}
+	log.Printf("Checked %d links, %d dead, %d deferred", len(checks)-deferred, dead, deferred)
+	return nil
+}
+
+// CheckLink checks a single link right away, e.g. after its URL was fixed
+func (s *LinkHealthService) CheckLink(kind models.LinkKind, id uint) (*models.LinkHealth, error) {
+	check := linkCheck{kind: kind, id: id}
+	switch kind {
+	case models.LinkKindStream:
+		link, err := s.linkRepo.FindStreamLink(id)
+		if err != nil {
+			return nil, errors.New("link not found")
+		}
+		check.url, check.health = link.URL, link.LinkHealth
+	case models.LinkKindDownload:
+		link, err := s.linkRepo.FindDownloadLink(id)
+		if err != nil {
+			return nil, errors.New("link not found")
+		}
+		check.url, check.health = link.URL, link.LinkHealth
+	default:
+		return nil, fmt.Errorf("invalid link kind: %s", kind)
+	}
+
+	check = s.probeAll([]linkCheck{check})[0]
+	if check.deferred {
+		return nil, errors.New("the host asked to be left alone for now, try again later")
+	}
+	if err := s.linkRepo.SaveHealth(kind, id, check.url, check.health); err != nil {
+		return nil, err
+	}
+	return &check.health, nil
+}
+
+// GetReport counts the links by health status and lists those of one kind
+func (s *LinkHealthService) GetReport(kind models.LinkKind, status models.LinkHealthStatus, page, pageSize int) (*models.LinkHealthReport, error) {
+	if !kind.IsValid() {
+		return nil, fmt.Errorf("invalid link kind: %s", kind)
+	}
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("invalid health status: %s", status)
+	}
+
+	summary, err := s.linkRepo.HealthSummary()
+	if err != nil {
+		return nil, err
+	}
+	entries, total, err := s.linkRepo.HealthReport(kind, status, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LinkHealthReport{
+		Summary:  summary,
+		Data:     entries,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// probeAll probes the links and records the outcome in their health. Every
+// host gets a worker of its own that goes through its links one by one, so
+// the host limiter only ever holds back requests to the same host.
+func (s *LinkHealthService) probeAll(checks []linkCheck) []linkCheck {
+	byHost := map[string][]int{}
+	targets := make([]string, len(checks))
+	for i := range checks {
+		target, err := checkURL(checks[i].url)
+		if err != nil {
+			s.skip(&checks[i], err)
+			continue
+		}
+		byHost[target.Hostname()] = append(byHost[target.Hostname()], i)
+		targets[i] = target.String()
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, linkCheckHosts)
+	for host, indexes := range byHost {
+		wg.Add(1)
+		go func(host string, indexes []int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			for _, i := range indexes {
+				if s.limiter.paused(host) {
+					checks[i].deferred = true
+					continue
+				}
+				s.limiter.wait(host)
+				probe := s.probe(targets[i])
+				s.record(&checks[i], probe)
+				if probe.retryAfter > 0 {
+					s.limiter.pause(host, time.Now().Add(probe.retryAfter))
+				}
+			}
+		}(host, indexes)
+	}
+	wg.Wait()
+
+	return checks
+}
+
+// probe asks for a URL with HEAD, and with a one-byte GET when the server
+// does not answer HEAD properly
+func (s *LinkHealthService) probe(target string) linkProbe {
+	probe := s.request(http.MethodHead, target)
+	// Plenty of servers refuse HEAD or get it wrong; a missing page stays
+	// missing and a host that asks to slow down gets no second request
+	if probe.err == nil && probe.status >= 400 && probe.status != http.StatusNotFound && probe.status != http.StatusGone && probe.retryAfter == 0 {
+		probe = s.request(http.MethodGet, target)
+	}
+	return probe
+}
+
+// request sends a single probe and times it
+func (s *LinkHealthService) request(method, target string) linkProbe {
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return linkProbe{err: err}
+	}
+	req.Header.Set("User-Agent", linkCheckUserAgent)
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return linkProbe{latency: latency, err: err}
+	}
+	// Servers that ignore the range would send the whole video; read a little for keep-alive and stop
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	probe := linkProbe{status: resp.StatusCode, latency: latency}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		probe.retryAfter = retryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+	return probe
+}
+
+// retryAfter reads a Retry-After header, given in seconds or as a date
+func retryAfter(value string, now time.Time) time.Duration {
+	wait := linkRetryAfterDefault
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		wait = at.Sub(now)
+	}
+	if wait <= 0 {
+		// Zero means "never" to the callers; ask again on the next round
+		return time.Second
+	}
+	if wait > linkRetryAfterMax {
+		return linkRetryAfterMax
+	}
+	return wait
+}
+
+// record updates the health of a link with the outcome of a probe
+func (s *LinkHealthService) record(check *linkCheck, probe linkProbe) {
+	now := time.Now()
+	health := &check.health
+	health.LastCheckedAt = &now
+	health.HTTPStatus = probe.status
+	health.LatencyMs = int(probe.latency / time.Millisecond)
+
+	if probe.err == nil && probe.status < 400 {
+		health.HealthStatus = models.LinkHealthAlive
+		health.FailureCount = 0
+		health.CheckError = ""
+		return
+	}
+
+	if probe.err != nil {
+		health.CheckError = truncateCheckError(probe.err.Error())
+	} else {
+		health.CheckError = fmt.Sprintf("HTTP %d %s", probe.status, http.StatusText(probe.status))
+	}
+	// Only a definite answer that the link is gone counts; the status stays as it was
+	if !probe.definiteFailure() {
+		if health.HealthStatus == "" {
+			health.HealthStatus = models.LinkHealthUnknown
+		}
+		return
+	}
+
+	health.FailureCount++
+	health.HealthStatus = models.LinkHealthFailing
+	if health.FailureCount >= s.deadAfter {
+		health.HealthStatus = models.LinkHealthDead
+	}
+}
+
+// definiteFailure reports whether a probe says the link is gone, as opposed to
+// the host being throttled, down for a while or out of reach
+func (p linkProbe) definiteFailure() bool {
+	if p.err != nil || p.status < 400 || p.status >= 500 {
+		return false
+	}
+	switch p.status {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return false
+	}
+	return true
+}
+
+// skip records that a link could not be checked; its health stays as it was
+func (s *LinkHealthService) skip(check *linkCheck, reason error) {
+	now := time.Now()
+	check.health.LastCheckedAt = &now
+	check.health.CheckError = reason.Error()
+	if check.health.HealthStatus == "" {
+		check.health.HealthStatus = models.LinkHealthUnknown
+	}
+}
+
+// checkURL finds the URL to probe in a link, which may be an embed code
+func checkURL(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "<") {
+		match := iframeSrc.FindStringSubmatch(raw)
+		if match == nil {
+			return nil, errNotCheckable
+		}
+		raw = match[1]
+	}
+	if strings.HasPrefix(raw, "//") {
+		raw = "https:" + raw
+	}
+
+	target, err := url.Parse(raw)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, errNotCheckable
+	}
+	return target, nil
+}
+
+// truncateCheckError keeps an error message within its column
+func truncateCheckError(message string) string {
+	if len(message) > 255 {
+		return message[:255]
+	}
+	return message
+}
+
+// hostLimiter spaces out requests to the same host
+type hostLimiter struct {
+	delay time.Duration
+
+	mu      sync.Mutex
+	next    map[string]time.Time // earliest time of the next request to each host
+	pausing map[string]time.Time // hosts that asked to be left alone, until when
+}
+
+// newHostLimiter creates a hostLimiter that keeps requests to a host delay apart
+func newHostLimiter(delay time.Duration) *hostLimiter {
+	return &hostLimiter{delay: delay, next: map[string]time.Time{}, pausing: map[string]time.Time{}}
+}
+
+// pause leaves a host alone until the given time
+func (l *hostLimiter) pause(host string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until.After(l.pausing[host]) {
+		l.pausing[host] = until
+	}
+}
+
+// paused reports whether a host is still to be left alone
+func (l *hostLimiter) paused(host string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until, ok := l.pausing[host]
+	if ok && !time.Now().Before(until) {
+		delete(l.pausing, host)
+		return false
+	}
+	return ok
+}
+
+// wait blocks until a request to the host is allowed and books the slot
+func (l *hostLimiter) wait(host string) {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[host] = at.Add(l.delay)
+
+	// Forget hosts that are free again so the map does not grow forever
+	if len(l.next) > 1000 {
+		for h, t := range l.next {
+			if t.Before(now) {
+				delete(l.next, h)
+			}
+		}
+	}
+	l.mu.Unlock()
+
+	time.Sleep(time.Until(at))
+}
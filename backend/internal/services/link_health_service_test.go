@@ -0,0 +1,258 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+// linkHost serves the pages the link checker tests probe and records when
+// every request came in
+type linkHost struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []time.Time
+}
+
+func newLinkHost(t *testing.T) *linkHost {
+	h := &linkHost{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Range") != "bytes=0-0" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte{0})
+	})
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/throttled", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	h.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		h.requests = append(h.requests, time.Now())
+		h.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(h.server.Close)
+	return h
+}
+
+func (h *linkHost) requestTimes() []time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]time.Time(nil), h.requests...)
+}
+
+func newTestLinkHealthService(client *http.Client, deadAfter int, hostDelay time.Duration) *LinkHealthService {
+	return NewLinkHealthService(nil, client, deadAfter, time.Hour, hostDelay)
+}
+
+func TestLinkHealthProbe(t *testing.T) {
+	host := newLinkHost(t)
+
+	client := host.server.Client()
+	client.Timeout = 100 * time.Millisecond
+	s := newTestLinkHealthService(client, 3, 0)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		failed bool
+	}{
+		{name: "answers HEAD", path: "/ok", status: http.StatusOK},
+		{name: "falls back to a ranged GET", path: "/no-head", status: http.StatusPartialContent},
+		{name: "missing page", path: "/gone", status: http.StatusNotFound, failed: true},
+		{name: "server error", path: "/broken", status: http.StatusInternalServerError, failed: true},
+		{name: "throttled", path: "/throttled", status: http.StatusTooManyRequests, failed: true},
+		{name: "times out", path: "/slow", failed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := s.probe(host.server.URL + tt.path)
+			assert.Equal(t, tt.status, probe.status)
+			assert.Equal(t, tt.failed, probe.err != nil || probe.status >= 400)
+		})
+	}
+
+	// A throttled HEAD is not followed by a GET
+	before := len(host.requestTimes())
+	probe := s.probe(host.server.URL + "/throttled")
+	assert.Equal(t, 2*time.Minute, probe.retryAfter)
+	assert.Len(t, host.requestTimes(), before+1)
+}
+
+func TestLinkHealthDeadAfterFailures(t *testing.T) {
+	s := newTestLinkHealthService(nil, 2, 0)
+	check := linkCheck{kind: models.LinkKindStream, id: 1}
+
+	s.record(&check, linkProbe{status: http.StatusNotFound, latency: 30 * time.Millisecond})
+	assert.Equal(t, models.LinkHealthFailing, check.health.HealthStatus)
+	assert.Equal(t, 1, check.health.FailureCount)
+	assert.Equal(t, http.StatusNotFound, check.health.HTTPStatus)
+	assert.Equal(t, 30, check.health.LatencyMs)
+	assert.Equal(t, "HTTP 404 Not Found", check.health.CheckError)
+	require.NotNil(t, check.health.LastCheckedAt)
+
+	s.record(&check, linkProbe{status: http.StatusNotFound})
+	assert.Equal(t, models.LinkHealthDead, check.health.HealthStatus)
+	assert.Equal(t, 2, check.health.FailureCount)
+
+	// A dead link that comes back is alive again straight away
+	s.record(&check, linkProbe{status: http.StatusOK})
+	assert.Equal(t, models.LinkHealthAlive, check.health.HealthStatus)
+	assert.Equal(t, 0, check.health.FailureCount)
+	assert.Empty(t, check.health.CheckError)
+}
+
+func TestLinkHealthTransientFailures(t *testing.T) {
+	s := newTestLinkHealthService(nil, 1, 0)
+
+	tests := []struct {
+		name  string
+		probe linkProbe
+	}{
+		{name: "throttled", probe: linkProbe{status: http.StatusTooManyRequests, retryAfter: time.Minute}},
+		{name: "unavailable", probe: linkProbe{status: http.StatusServiceUnavailable}},
+		{name: "server error", probe: linkProbe{status: http.StatusInternalServerError}},
+		{name: "request timeout", probe: linkProbe{status: http.StatusRequestTimeout}},
+		{name: "unreachable", probe: linkProbe{err: errors.New("dial tcp: i/o timeout")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := linkCheck{health: models.LinkHealth{HealthStatus: models.LinkHealthAlive}}
+			s.record(&check, tt.probe)
+			assert.Equal(t, models.LinkHealthAlive, check.health.HealthStatus)
+			assert.Equal(t, 0, check.health.FailureCount)
+			assert.NotEmpty(t, check.health.CheckError)
+			require.NotNil(t, check.health.LastCheckedAt)
+		})
+	}
+
+	check := linkCheck{}
+	s.record(&check, linkProbe{err: errors.New("dial tcp: i/o timeout")})
+	assert.Equal(t, models.LinkHealthUnknown, check.health.HealthStatus)
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 90*time.Second, retryAfter("90", now))
+	assert.Equal(t, 10*time.Minute, retryAfter("Wed, 01 May 2024 12:10:00 GMT", now))
+	assert.Equal(t, linkRetryAfterDefault, retryAfter("", now))
+	assert.Equal(t, linkRetryAfterDefault, retryAfter("soon", now))
+	assert.Equal(t, time.Second, retryAfter("0", now))
+	assert.Equal(t, linkRetryAfterMax, retryAfter("9999999", now))
+}
+
+func TestLinkHealthProbeAllThrottled(t *testing.T) {
+	host := newLinkHost(t)
+	s := newTestLinkHealthService(host.server.Client(), 1, 0)
+
+	checks := s.probeAll([]linkCheck{
+		{kind: models.LinkKindStream, id: 1, url: host.server.URL + "/throttled"},
+		{kind: models.LinkKindStream, id: 2, url: host.server.URL + "/gone"},
+	})
+
+	// The host asked to wait, so the second link waits for a later round
+	assert.False(t, checks[0].deferred)
+	assert.Equal(t, models.LinkHealthUnknown, checks[0].health.HealthStatus)
+	assert.True(t, checks[1].deferred)
+	assert.Nil(t, checks[1].health.LastCheckedAt)
+	assert.Len(t, host.requestTimes(), 1)
+}
+
+func TestLinkHealthProbeAll(t *testing.T) {
+	host := newLinkHost(t)
+	delay := 100 * time.Millisecond
+	s := newTestLinkHealthService(host.server.Client(), 1, delay)
+
+	checks := s.probeAll([]linkCheck{
+		{kind: models.LinkKindStream, id: 1, url: host.server.URL + "/ok"},
+		{kind: models.LinkKindStream, id: 2, url: `<IFRAME SRC="` + host.server.URL + `/ok" FRAMEBORDER=0 allowfullscreen></IFRAME>`},
+		{kind: models.LinkKindDownload, id: 3, url: host.server.URL + "/gone"},
+		{kind: models.LinkKindDownload, id: 4, url: "/media/videos/1/episode.mp4"},
+	})
+
+	assert.Equal(t, models.LinkHealthAlive, checks[0].health.HealthStatus)
+	assert.Equal(t, models.LinkHealthAlive, checks[1].health.HealthStatus)
+	assert.Equal(t, models.LinkHealthDead, checks[2].health.HealthStatus)
+
+	// Links that are not web addresses are left alone
+	assert.Equal(t, models.LinkHealthUnknown, checks[3].health.HealthStatus)
+	assert.Equal(t, 0, checks[3].health.FailureCount)
+	assert.Equal(t, errNotCheckable.Error(), checks[3].health.CheckError)
+	require.NotNil(t, checks[3].health.LastCheckedAt)
+
+	// All three probes went to the same host, so they were spaced out
+	times := host.requestTimes()
+	require.Len(t, times, 3)
+	for i := 1; i < len(times); i++ {
+		assert.GreaterOrEqual(t, times[i].Sub(times[i-1]), delay-10*time.Millisecond)
+	}
+}
+
+func TestHostLimiter(t *testing.T) {
+	delay := 100 * time.Millisecond
+	limiter := newHostLimiter(delay)
+
+	start := time.Now()
+	limiter.wait("a.example")
+	limiter.wait("b.example")
+	assert.Less(t, time.Since(start), delay/2, "different hosts do not hold each other up")
+
+	limiter.wait("a.example")
+	assert.GreaterOrEqual(t, time.Since(start), delay-10*time.Millisecond, "the same host waits its turn")
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "https://cdn.example.com/video.mp4", want: "https://cdn.example.com/video.mp4"},
+		{raw: "  http://example.com/a  ", want: "http://example.com/a"},
+		{raw: `<IFRAME SRC="https://www.mp4upload.com/embed-abc.html" WIDTH=1280></IFRAME>`, want: "https://www.mp4upload.com/embed-abc.html"},
+		{raw: `<iframe src='//player.example.com/e/1' allowfullscreen></iframe>`, want: "https://player.example.com/e/1"},
+		{raw: "/media/videos/1/episode.mp4"},
+		{raw: "ftp://files.example.com/episode.mkv"},
+		{raw: "<iframe></iframe>"},
+	}
+	for _, tt := range tests {
+		target, err := checkURL(tt.raw)
+		if tt.want == "" {
+			assert.ErrorIs(t, err, errNotCheckable, tt.raw)
+			continue
+		}
+		require.NoError(t, err, tt.raw)
+		assert.Equal(t, tt.want, target.String())
+	}
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+// LinkService handles business logic for the stream and download links of episodes
+type LinkService struct {
+	linkRepo    *repository.LinkRepository
+	episodeRepo *repository.EpisodeRepository
+}
+
+// NewLinkService creates a new LinkService
+func NewLinkService(linkRepo *repository.LinkRepository, episodeRepo *repository.EpisodeRepository) *LinkService {
+	return &LinkService{
+		linkRepo:    linkRepo,
+		episodeRepo: episodeRepo,
+	}
+}
+
+// GetVisibleLinks returns the links of an episode the viewer may use, in priority order
+func (s *LinkService) GetVisibleLinks(viewer *models.Viewer, contentID, episodeID uint) (*models.EpisodeLinks, error) {
+	episode, err := s.episodeRepo.FindVisibleByID(viewer, episodeID)
+	if err != nil || episode.ContentID != contentID {
+		return nil, errors.New("episode not found")
+	}
+
+	links := &models.EpisodeLinks{
+		StreamLinks:   episode.StreamLinks,
+		DownloadLinks: episode.DownloadLinks,
+	}
+	if links.StreamLinks == nil {
+		links.StreamLinks = []models.StreamLink{}
+	}
+	if links.DownloadLinks == nil {
+		links.DownloadLinks = []models.DownloadLink{}
+	}
+	return links, nil
+}
+
+// GetEpisode finds an episode of a content for link editing
+func (s *LinkService) GetEpisode(contentID, episodeID uint) (*models.Episode, error) {
+	episode, err := s.episodeRepo.FindByID(episodeID)
+	if err != nil || episode.ContentID != contentID {
+		return nil, errors.New("episode not found")
+	}
+	return episode, nil
+}
+
+// GetStreamLink finds a stream link of an episode
+func (s *LinkService) GetStreamLink(episode *models.Episode, id uint) (*models.StreamLink, error) {
+	link, err := s.linkRepo.FindStreamLink(id)
+	if err != nil || link.EpisodeID == nil || *link.EpisodeID != episode.ID {
+		return nil, errors.New("link not found")
+	}
+	return link, nil
+}
+
+// GetDownloadLink finds a download link of an episode
+func (s *LinkService) GetDownloadLink(episode *models.Episode, id uint) (*models.DownloadLink, error) {
+	link, err := s.linkRepo.FindDownloadLink(id)
+	if err != nil || link.EpisodeID == nil || *link.EpisodeID != episode.ID {
+		return nil, errors.New("link not found")
+	}
+	return link, nil
+}
+
+// AddStreamLink adds a stream link to an episode
+func (s *LinkService) AddStreamLink(episode *models.Episode, link *models.StreamLink) error {
+	link.ID = 0
+	link.ContentID = episode.ContentID
+	link.EpisodeID = &episode.ID
+	if err := validateStreamLink(link); err != nil {
+		return err
+	}
+	return s.linkRepo.CreateStreamLink(link)
+}
+
+// AddDownloadLink adds a download link to an episode
+func (s *LinkService) AddDownloadLink(episode *models.Episode, link *models.DownloadLink) error {
+	link.ID = 0
+	link.ContentID = episode.ContentID
+	link.EpisodeID = &episode.ID
+	if err := validateDownloadLink(link); err != nil {
+		return err
+	}
+	return s.linkRepo.CreateDownloadLink(link)
+}
+
+// UpdateStreamLink saves changes to a stream link
+func (s *LinkService) UpdateStreamLink(link *models.StreamLink) error {
+	if err := validateStreamLink(link); err != nil {
+		return err
+	}
+	return s.linkRepo.UpdateStreamLink(link)
+}
+
+// UpdateDownloadLink saves changes to a download link
+func (s *LinkService) UpdateDownloadLink(link *models.DownloadLink) error {
+	if err := validateDownloadLink(link); err != nil {
+		return err
+	}
+	return s.linkRepo.UpdateDownloadLink(link)
+}
+
+// DeleteStreamLink deletes a stream link of an episode
+func (s *LinkService) DeleteStreamLink(episode *models.Episode, id uint) error {
+	if _, err := s.GetStreamLink(episode, id); err != nil {
+		return err
+	}
+	return s.linkRepo.DeleteStreamLink(id)
+}
+
+// DeleteDownloadLink deletes a download link of an episode
+func (s *LinkService) DeleteDownloadLink(episode *models.Episode, id uint) error {
+	if _, err := s.GetDownloadLink(episode, id); err != nil {
+		return err
+	}
+	return s.linkRepo.DeleteDownloadLink(id)
+}
+
+// validateStreamLink checks a stream link and fills in its type and server
+func validateStreamLink(link *models.StreamLink) error {
+	link.Name = strings.TrimSpace(link.Name)
+	link.URL = strings.TrimSpace(link.URL)
+	if link.Name == "" || link.URL == "" {
+		return errors.New("a link needs a name and a URL")
+	}
+	if link.Priority < 0 {
+		return errors.New("priority cannot be negative")
+	}
+
+	switch link.Type {
+	case "":
+		link.Type = "embed"
+	case "embed", "self-hosted":
+	default:
+		return fmt.Errorf("invalid stream type: %s", link.Type)
+	}
+	if err := checkStreamURL(link); err != nil {
+		return err
+	}
+	if link.Server == "" {
+		link.Server = "external"
+		if link.Type == "self-hosted" {
+			link.Server = "local"
+		}
+	}
+	return nil
+}
+
+// validateDownloadLink checks a download link and fills in its server
+func validateDownloadLink(link *models.DownloadLink) error {
+	link.Name = strings.TrimSpace(link.Name)
+	link.URL = strings.TrimSpace(link.URL)
+	if link.Name == "" || link.URL == "" {
+		return errors.New("a link needs a name and a URL")
+	}
+	if link.Priority < 0 {
+		return errors.New("priority cannot be negative")
+	}
+	if err := checkDownloadURL(link); err != nil {
+		return err
+	}
+	if link.Server == "" {
+		link.Server = link.Name
+	}
+	return nil
+}
+
+// checkStreamURL makes sure a stream link is something the player can open: an
+// http(s) URL, embed code around one, or for self-hosted links a media path
+func checkStreamURL(link *models.StreamLink) error {
+	if link.Type == "self-hosted" && isMediaPath(link.URL) {
+		return nil
+	}
+	if _, err := checkURL(link.URL); err != nil {
+		return errors.New("stream links need an http(s) URL or embed code")
+	}
+	return nil
+}
+
+// checkDownloadURL makes sure a download link is a plain http(s) URL; it ends up
+// as the href of a download button. Addresses without a scheme get https, as
+// the watch page has always done for them.
+func checkDownloadURL(link *models.DownloadLink) error {
+	link.URL = strings.TrimSpace(link.URL)
+	if link.URL != "" && !strings.Contains(link.URL, "://") && !strings.HasPrefix(link.URL, "/") && !strings.HasPrefix(link.URL, "<") {
+		link.URL = "https://" + link.URL
+	}
+	if _, err := checkURL(link.URL); err != nil || strings.HasPrefix(link.URL, "<") {
+		return errors.New("download links need an http(s) URL")
+	}
+	return nil
+}
+
+// isMediaPath checks for a path on this server, such as an uploaded video
+func isMediaPath(raw string) bool {
+	if strings.HasPrefix(raw, "//") || strings.HasPrefix(raw, "<") {
+		return false
+	}
+	parsed, err := url.Parse(raw)
+	return err == nil && parsed.Scheme == "" && parsed.Host == ""
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+func TestValidateStreamLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		link    models.StreamLink
+		wantErr bool
+	}{
+		{name: "web address", link: models.StreamLink{Name: "CDN", URL: "https://cdn.example.com/1.m3u8"}},
+		{name: "embed code", link: models.StreamLink{Name: "Mp4upload", Type: "embed", URL: `<IFRAME SRC="https://www.mp4upload.com/embed-abc.html"></IFRAME>`}},
+		{name: "uploaded video", link: models.StreamLink{Name: "Local", Type: "self-hosted", URL: "videos/original/12_40.mp4"}},
+		{name: "script", link: models.StreamLink{Name: "x", URL: "javascript:alert(1)"}, wantErr: true},
+		{name: "embed code running a script", link: models.StreamLink{Name: "x", Type: "embed", URL: `<iframe src="javascript:alert(1)"></iframe>`}, wantErr: true},
+		{name: "embed with a media path", link: models.StreamLink{Name: "x", Type: "embed", URL: "videos/original/12_40.mp4"}, wantErr: true},
+		{name: "self-hosted with a script", link: models.StreamLink{Name: "x", Type: "self-hosted", URL: "javascript:alert(1)"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStreamLink(&tt.link)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestValidateDownloadLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{name: "web address", url: "https://files.example.com/ep1.mkv", want: "https://files.example.com/ep1.mkv"},
+		{name: "without a scheme", url: " drive.example.com/file/1 ", want: "https://drive.example.com/file/1"},
+		{name: "script", url: "javascript:alert(1)", wantErr: true},
+		{name: "data URL", url: "data:text/html,<script>alert(1)</script>", wantErr: true},
+		{name: "embed code", url: `<iframe src="https://example.com"></iframe>`, wantErr: true},
+		{name: "ftp", url: "ftp://files.example.com/ep1.mkv", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := models.DownloadLink{Name: "Mirror", URL: tt.url}
+			err := validateDownloadLink(&link)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, link.URL)
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 type MediaService struct {
 	contentRepo       *repository.ContentRepository
 	episodeRepo       *repository.EpisodeRepository
+	revisionService   *RevisionService
 	mediaPath         string
 	contentTypeHelper *models.ContentTypeHelper
 }
@@ -45,18 +46,20 @@ var (
 func NewMediaService(
 	contentRepo *repository.ContentRepository,
 	episodeRepo *repository.EpisodeRepository,
+	revisionService *RevisionService,
 	mediaPath string,
 ) *MediaService {
 	return &MediaService{
 		contentRepo:       contentRepo,
 		episodeRepo:       episodeRepo,
+		revisionService:   revisionService,
 		mediaPath:         mediaPath,
 		contentTypeHelper: models.NewContentTypeHelper(),
 	}
 }
 
-// UploadContentCover handles cover image upload for content
-func (s *MediaService) UploadContentCover(contentID uint, file *multipart.FileHeader) error {
+// UploadContentCover handles cover image upload for content on behalf of a user
+func (s *MediaService) UploadContentCover(userID, contentID uint, file *multipart.FileHeader) error {
 	log.Printf("Uploading cover image for content %d", contentID)
 
 	// Ensure directory exists
@@ -85,14 +88,20 @@ func (s *MediaService) UploadContentCover(contentID uint, file *multipart.FileHe
 	log.Printf("Updating content record with cover image path: %s", relativePath)
 
 	// Update the content record in the database
-	content, err := s.contentRepo.FindByID(contentID)
-	if err != nil {
-		return fmt.Errorf("failed to find content: %v", err)
-	}
+	err := s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		content, err := w.Contents.FindByID(contentID)
+		if err != nil {
+			return fmt.Errorf("failed to find content: %v", err)
+		}
 
-	content.CoverImage = relativePath
-	if err := s.contentRepo.Update(content); err != nil {
-		return fmt.Errorf("failed to update content with cover image: %v", err)
+		content.CoverImage = relativePath
+		if err := w.Contents.Update(content); err != nil {
+			return fmt.Errorf("failed to update content with cover image: %v", err)
+		}
+		return w.RecordContent(contentID, models.RevisionUpdate)
+	})
+	if err != nil {
+		return err
 	}
 
 	log.Printf("Successfully updated content %d with cover image path", contentID)
@@ -103,11 +112,10 @@ func (s *MediaService) GetMediaPath() string {
 	return s.mediaPath
 }
 
-// UploadEpisodeThumbnail uploads a thumbnail for an episode
-func (s *MediaService) UploadEpisodeThumbnail(episodeID uint, file *multipart.FileHeader) error {
+// UploadEpisodeThumbnail uploads a thumbnail for an episode on behalf of a user
+func (s *MediaService) UploadEpisodeThumbnail(userID, episodeID uint, file *multipart.FileHeader) error {
 	// Verify episode exists
-	episode, err := s.episodeRepo.FindByID(episodeID)
-	if err != nil {
+	if _, err := s.episodeRepo.FindByID(episodeID); err != nil {
 		return err
 	}
 
@@ -140,8 +148,17 @@ func (s *MediaService) UploadEpisodeThumbnail(episodeID uint, file *multipart.Fi
 	}
 
 	// Update episode thumbnail path
-	episode.ThumbnailURL = filename
-	return s.episodeRepo.Update(episode)
+	return s.revisionService.Write(userID, func(w *RevisionWriter) error {
+		episode, err := w.Episodes.FindByID(episodeID)
+		if err != nil {
+			return err
+		}
+		episode.ThumbnailURL = filename
+		if err := w.Episodes.Update(episode); err != nil {
+			return err
+		}
+		return w.RecordEpisode(episodeID, models.RevisionUpdate)
+	})
 }
 
 // UploadVideo handles video file upload and processing
@@ -280,12 +297,17 @@ func (s *MediaService) transcodeVideo(filename string) error {
 }
 
 // GetVideoPath gets the video file path for streaming
-func (s *MediaService) GetVideoPath(contentID uint, episodeID *uint, quality string) (string, error) {
+func (s *MediaService) GetVideoPath(viewer *models.Viewer, contentID uint, episodeID *uint, quality string) (string, error) {
 	log.Printf("Getting video path for content %d, episode %v, quality %s", contentID, episodeID, quality)
 
+	// Parental controls apply to streaming as well as browsing
+	if _, err := s.contentRepo.FindVisibleByID(viewer, contentID); err != nil {
+		return "", fmt.Errorf("failed to find content: %v", err)
+	}
+
 	if episodeID != nil {
-		// Get episode
-		episode, err := s.episodeRepo.FindByID(*episodeID)
+		// Drafts and scheduled episodes of a live title stay hidden from non-staff
+		episode, err := s.episodeRepo.FindVisibleByID(viewer, *episodeID)
 		if err != nil {
 			return "", fmt.Errorf("failed to find episode: %v", err)
 		}
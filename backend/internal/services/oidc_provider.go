@@ -0,0 +1,322 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/username/anime-streaming/internal/config"
+)
+
+// OIDCProvider is an OpenID Connect relying-party client for a single provider
+type OIDCProvider struct {
+	cfg        config.OIDCProviderConfig
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]interface{}
+}
+
+// OIDCClaims holds the ID token claims used for account linking
+type OIDCClaims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Nonce             string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// NewOIDCProvider creates a new OIDCProvider
+func NewOIDCProvider(cfg config.OIDCProviderConfig, httpClient *http.Client) *OIDCProvider {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OIDCProvider{
+		cfg:        cfg,
+		httpClient: httpClient,
+	}
+}
+
+// Name returns the configured provider name
+func (p *OIDCProvider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthCodeURL builds the authorization URL for the PKCE authorization-code flow
+func (p *OIDCProvider) AuthCodeURL(state, nonce, codeVerifier string) (string, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange trades an authorization code for tokens and returns the raw ID token
+func (p *OIDCProvider) Exchange(code, codeVerifier string) (string, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	resp, err := p.httpClient.PostForm(discovery.TokenEndpoint, form)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %v", err)
+	}
+	if tokens.IDToken == "" {
+		return "", errors.New("token response did not include an id_token")
+	}
+
+	return tokens.IDToken, nil
+}
+
+// VerifyIDToken validates the ID token signature against the provider JWKS and
+// checks issuer, audience, expiry and nonce
+func (p *OIDCProvider) VerifyIDToken(rawIDToken, nonce string) (*OIDCClaims, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &OIDCClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %v", err)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+
+	return claims, nil
+}
+
+// getDiscovery fetches and caches the provider's OpenID configuration
+func (p *OIDCProvider) getDiscovery() (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	var discovery oidcDiscovery
+	if err := p.getJSON(wellKnown, &discovery); err != nil {
+		return nil, fmt.Errorf("failed to fetch provider configuration: %v", err)
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(p.cfg.IssuerURL, "/") {
+		return nil, fmt.Errorf("issuer mismatch: expected %s, got %s", p.cfg.IssuerURL, discovery.Issuer)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// getKey returns the verification key for kid, refreshing the JWKS once if the key is unknown
+func (p *OIDCProvider) getKey(kid string) (interface{}, error) {
+	p.mu.Lock()
+	key, ok := p.lookupKey(kid)
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	if err := p.refreshKeys(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("signing key %q not found", kid)
+}
+
+// lookupKey finds a cached key; callers must hold p.mu
+func (p *OIDCProvider) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// refreshKeys downloads the provider JWKS
+func (p *OIDCProvider) refreshKeys() error {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return err
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(discovery.JWKSURI, &jwks); err != nil {
+		return fmt.Errorf("failed to fetch jwks: %v", err)
+	}
+
+	keys := make(map[string]interface{})
+	for _, jwk := range jwks.Keys {
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+	return nil
+}
+
+// getJSON performs a GET request and decodes the JSON response into v
+func (p *OIDCProvider) getJSON(endpoint string, v interface{}) error {
+	resp, err := p.httpClient.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// publicKey converts a JWK into an *rsa.PublicKey or *ecdsa.PublicKey
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+// randomToken returns a URL-safe random string suitable for state, nonce and PKCE verifiers
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
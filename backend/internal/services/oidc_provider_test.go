@@ -0,0 +1,215 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/username/anime-streaming/internal/config"
+)
+
+// mockOIDCProvider is a minimal OpenID Connect provider for tests
+type mockOIDCProvider struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+
+	// Values the token endpoint expects and returns
+	code      string
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockOIDCProvider{key: key, clientID: "portal-anime"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test-key",
+				"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != m.code || base64.RawURLEncoding.EncodeToString(sum[:]) != m.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": m.sign(t, "test-key", m.claims)})
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockOIDCProvider) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(m.key)
+	require.NoError(t, err)
+	return signed
+}
+
+func (m *mockOIDCProvider) validClaims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            m.server.URL,
+		"aud":            m.clientID,
+		"sub":            "user-123",
+		"email":          "viewer@example.com",
+		"email_verified": true,
+		"nonce":          nonce,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+	}
+}
+
+func (m *mockOIDCProvider) client() *OIDCProvider {
+	return NewOIDCProvider(config.OIDCProviderConfig{
+		Name:        "mock",
+		IssuerURL:   m.server.URL,
+		ClientID:    m.clientID,
+		RedirectURL: "http://localhost:3000/auth/callback/mock",
+		Scopes:      []string{"openid", "email"},
+	}, m.server.Client())
+}
+
+func TestOIDCProvider_AuthorizationCodeFlow(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	provider := mock.client()
+
+	authURL, err := provider.AuthCodeURL("state-1", "nonce-1", "verifier-1")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, "/authorize", parsed.Path)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "nonce-1", query.Get("nonce"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	mock.code = "auth-code"
+	mock.challenge = query.Get("code_challenge")
+	mock.claims = mock.validClaims("nonce-1")
+
+	rawIDToken, err := provider.Exchange("auth-code", "verifier-1")
+	require.NoError(t, err)
+
+	claims, err := provider.VerifyIDToken(rawIDToken, "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", claims.Subject)
+	assert.Equal(t, "viewer@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+}
+
+func TestOIDCProvider_ExchangeRejectsWrongVerifier(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	provider := mock.client()
+
+	authURL, err := provider.AuthCodeURL("state-1", "nonce-1", "verifier-1")
+	require.NoError(t, err)
+	parsed, _ := url.Parse(authURL)
+
+	mock.code = "auth-code"
+	mock.challenge = parsed.Query().Get("code_challenge")
+	mock.claims = mock.validClaims("nonce-1")
+
+	_, err = provider.Exchange("auth-code", "another-verifier")
+	assert.Error(t, err)
+}
+
+func TestOIDCProvider_VerifyIDToken(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+
+	tests := []struct {
+		name    string
+		kid     string
+		claims  func(jwt.MapClaims)
+		nonce   string
+		wantErr bool
+	}{
+		{
+			name:   "Valid token",
+			kid:    "test-key",
+			claims: func(c jwt.MapClaims) {},
+			nonce:  "nonce-1",
+		},
+		{
+			name:    "Nonce mismatch",
+			kid:     "test-key",
+			claims:  func(c jwt.MapClaims) {},
+			nonce:   "other-nonce",
+			wantErr: true,
+		},
+		{
+			name:    "Wrong audience",
+			kid:     "test-key",
+			claims:  func(c jwt.MapClaims) { c["aud"] = "someone-else" },
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+		{
+			name:    "Wrong issuer",
+			kid:     "test-key",
+			claims:  func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+		{
+			name:    "Expired token",
+			kid:     "test-key",
+			claims:  func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown signing key",
+			kid:     "rotated-key",
+			claims:  func(c jwt.MapClaims) {},
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := mock.validClaims("nonce-1")
+			tt.claims(claims)
+
+			_, err := mock.client().VerifyIDToken(mock.sign(t, tt.kid, claims), tt.nonce)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/username/anime-streaming/internal/config"
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// oidcStateTTL is how long a pending social login may take before it expires
+const oidcStateTTL = 10 * time.Minute
+
+// ErrOIDCEmailTaken is returned when a first social login carries the email of
+// an existing local account. Local sign-ups do not verify their email, so the
+// account is only linked by its owner while signed in.
+var ErrOIDCEmailTaken = errors.New("an account with this email address already exists; sign in with your password and link the provider from your account")
+
+// OIDCService handles social login through OpenID Connect providers
+type OIDCService struct {
+	providers    map[string]*OIDCProvider
+	userRepo     *repository.UserRepository
+	identityRepo *repository.UserIdentityRepository
+	userService  *UserService
+
+	// Pending logins are kept in memory; the PKCE verifier must never leave the server
+	mu      sync.Mutex
+	pending map[string]oidcPendingLogin
+}
+
+type oidcPendingLogin struct {
+	provider     string
+	nonce        string
+	codeVerifier string
+	expiresAt    time.Time
+
+	// The signed-in user linking the provider to their account; zero for a login
+	linkUserID uint
+}
+
+// NewOIDCService creates a new OIDCService
+func NewOIDCService(
+	providers []config.OIDCProviderConfig,
+	userRepo *repository.UserRepository,
+	identityRepo *repository.UserIdentityRepository,
+	userService *UserService,
+) *OIDCService {
+	s := &OIDCService{
+		providers:    make(map[string]*OIDCProvider),
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		userService:  userService,
+		pending:      make(map[string]oidcPendingLogin),
+	}
+	for _, cfg := range providers {
+		s.providers[cfg.Name] = NewOIDCProvider(cfg, nil)
+	}
+	return s
+}
+
+// ListProviders returns the names of all configured providers
+func (s *OIDCService) ListProviders() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// BeginLogin starts the authorization-code flow and returns the provider URL to redirect to
+func (s *OIDCService) BeginLogin(providerName string) (string, error) {
+	return s.begin(providerName, 0)
+}
+
+// BeginLink starts the authorization-code flow for a signed-in user who links
+// the provider to their account, and returns the provider URL to redirect to
+func (s *OIDCService) BeginLink(providerName string, userID uint) (string, error) {
+	return s.begin(providerName, userID)
+}
+
+func (s *OIDCService) begin(providerName string, linkUserID uint) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", errors.New("unknown login provider")
+	}
+
+	state, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomToken(48)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(state, nonce, verifier)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpiredLocked()
+	s.pending[state] = oidcPendingLogin{
+		provider:     providerName,
+		nonce:        nonce,
+		codeVerifier: verifier,
+		expiresAt:    time.Now().Add(oidcStateTTL),
+		linkUserID:   linkUserID,
+	}
+
+	return authURL, nil
+}
+
+// CompleteLogin exchanges the authorization code, validates the ID token, links the
+// identity to a user and returns an application JWT for that user. A flow started
+// with BeginLink links the identity to the user who started it.
+func (s *OIDCService) CompleteLogin(providerName, code, state string) (string, *models.User, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", nil, errors.New("unknown login provider")
+	}
+
+	s.mu.Lock()
+	login, ok := s.pending[state]
+	delete(s.pending, state)
+	s.mu.Unlock()
+
+	if !ok || login.provider != providerName || time.Now().After(login.expiresAt) {
+		return "", nil, errors.New("invalid or expired login state")
+	}
+
+	rawIDToken, err := provider.Exchange(code, login.codeVerifier)
+	if err != nil {
+		return "", nil, err
+	}
+
+	claims, err := provider.VerifyIDToken(rawIDToken, login.nonce)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var user *models.User
+	if login.linkUserID != 0 {
+		user, err = s.linkToUser(providerName, claims, login.linkUserID)
+	} else {
+		user, err = s.linkIdentity(providerName, claims)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	token, err := s.userService.GenerateToken(user)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, user, nil
+}
+
+// linkIdentity finds the user for an external identity or creates a new account
+// for it. An existing account with the same email is not taken over.
+func (s *OIDCService) linkIdentity(providerName string, claims *OIDCClaims) (*models.User, error) {
+	if claims.Subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+
+	identity, err := s.identityRepo.FindByProviderSubject(providerName, claims.Subject)
+	if err == nil {
+		return s.userRepo.FindByID(identity.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, errors.New("provider did not return a verified email address")
+	}
+
+	if _, err := s.userRepo.FindByEmail(claims.Email); err == nil {
+		return nil, ErrOIDCEmailTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	user, err := s.createUser(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createIdentity(providerName, claims, user)
+}
+
+// linkToUser links an external identity to a signed-in user's account
+func (s *OIDCService) linkToUser(providerName string, claims *OIDCClaims, userID uint) (*models.User, error) {
+	if claims.Subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	identity, err := s.identityRepo.FindByProviderSubject(providerName, claims.Subject)
+	if err == nil {
+		if identity.UserID != user.ID {
+			return nil, fmt.Errorf("this %s account is already linked to another user", providerName)
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return s.createIdentity(providerName, claims, user)
+}
+
+// createIdentity records that an external identity signs in as user
+func (s *OIDCService) createIdentity(providerName string, claims *OIDCClaims, user *models.User) (*models.User, error) {
+	log.Printf("Linking %s identity %s to user %d", providerName, claims.Subject, user.ID)
+	if err := s.identityRepo.Create(&models.UserIdentity{
+		UserID:   user.ID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to link identity: %v", err)
+	}
+
+	return user, nil
+}
+
+var usernameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// createUser registers a new account for a first-time social login
+func (s *OIDCService) createUser(claims *OIDCClaims) (*models.User, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base = strings.Split(claims.Email, "@")[0]
+	}
+	base = usernameSanitizer.ReplaceAllString(base, "")
+	if base == "" {
+		base = "user"
+	}
+
+	username := base
+	for i := 1; ; i++ {
+		_, err := s.userRepo.FindByUsername(username)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		username = fmt.Sprintf("%s%d", base, i)
+	}
+
+	// Social accounts get an unusable random password until the user sets one
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username: username,
+		Email:    claims.Email,
+		Password: string(hashedPassword),
+		Role:     models.RoleUser,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// purgeExpiredLocked drops stale pending logins; callers must hold s.mu
+func (s *OIDCService) purgeExpiredLocked() {
+	now := time.Now()
+	for state, login := range s.pending {
+		if now.After(login.expiresAt) {
+			delete(s.pending, state)
+		}
+	}
+}
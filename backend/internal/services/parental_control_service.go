@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrParentalPINRequired is returned when an action needs the account's parental PIN
+var ErrParentalPINRequired = errors.New("parental PIN required")
+
+// ErrParentalPINLocked is returned while the parental PIN is locked after too many wrong guesses
+var ErrParentalPINLocked = errors.New("too many wrong parental PINs, try again later")
+
+const (
+	// parentalPINMaxFailures is the number of wrong PINs in a row that lock the PIN
+	parentalPINMaxFailures = 5
+	// parentalPINLockout is how long a locked PIN is refused, right or wrong
+	parentalPINLockout = 15 * time.Minute
+)
+
+// ParentalControlService handles maturity limits and the parental PIN
+type ParentalControlService struct {
+	userRepo    *repository.UserRepository
+	profileRepo *repository.ProfileRepository
+	scheme      models.MaturityScheme
+	guestLimit  models.MaturityRating
+}
+
+// NewParentalControlService creates a new ParentalControlService
+func NewParentalControlService(
+	userRepo *repository.UserRepository,
+	profileRepo *repository.ProfileRepository,
+	scheme models.MaturityScheme,
+	guestLimit models.MaturityRating,
+) *ParentalControlService {
+	return &ParentalControlService{
+		userRepo:    userRepo,
+		profileRepo: profileRepo,
+		scheme:      scheme,
+		guestLimit:  guestLimit,
+	}
+}
+
+// Scheme returns the configured maturity rating scheme
+func (s *ParentalControlService) Scheme() models.MaturityScheme {
+	return s.scheme
+}
+
+// ResolveViewer builds the viewer used to scope content queries.
+// A userID of zero means an anonymous visitor. A correct PIN lifts the limit for
+// the request; wrong PINs count towards the lockout like anywhere else.
+func (s *ParentalControlService) ResolveViewer(userID uint, role models.Role, profileID *uint, pin string) (*models.Viewer, error) {
+	viewer := &models.Viewer{UserID: userID, ProfileID: profileID, Role: role}
+
+	if userID == 0 {
+		viewer.AllowedRatings = s.allowed(s.guestLimit)
+		return viewer, nil
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	limit := user.MaxMaturity
+	if profileID != nil {
+		profile, err := s.profileRepo.FindByID(*profileID)
+		if err != nil || profile.UserID != userID {
+			return nil, errors.New("profile not found")
+		}
+		limit = s.scheme.Stricter(limit, s.profileLimit(profile))
+	} else if role == models.RoleAdmin {
+		// Admins browsing without a profile manage the whole catalog
+		limit = ""
+	}
+
+	if limit != "" && pin != "" {
+		if err := s.verifyPIN(user, pin); err == nil {
+			limit = ""
+		} else if !errors.Is(err, ErrParentalPINRequired) && !errors.Is(err, ErrParentalPINLocked) {
+			return nil, err
+		}
+	}
+
+	viewer.AllowedRatings = s.allowed(limit)
+	return viewer, nil
+}
+
+// UpdateSettings changes the account-wide maturity limit and/or parental PIN.
+// When a PIN is already set, currentPIN must match it.
+func (s *ParentalControlService) UpdateSettings(userID uint, maxMaturity *models.MaturityRating, newPIN *string, currentPIN string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ParentalPIN != "" {
+		if err := s.verifyPIN(user, currentPIN); err != nil {
+			return nil, err
+		}
+	}
+
+	if maxMaturity != nil {
+		if err := s.ValidateRating(*maxMaturity); err != nil {
+			return nil, err
+		}
+		user.MaxMaturity = *maxMaturity
+	}
+
+	if newPIN != nil {
+		if *newPIN == "" {
+			user.ParentalPIN = ""
+		} else {
+			if len(*newPIN) < 4 {
+				return nil, errors.New("PIN must be at least 4 characters")
+			}
+			hashed, err := bcrypt.GenerateFromPassword([]byte(*newPIN), bcrypt.DefaultCost)
+			if err != nil {
+				return nil, err
+			}
+			user.ParentalPIN = string(hashed)
+		}
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// RequirePIN returns ErrParentalPINRequired if the account has a PIN and pin
+// does not match it, or ErrParentalPINLocked while the PIN is locked
+func (s *ParentalControlService) RequirePIN(userID uint, pin string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.ParentalPIN == "" {
+		return nil
+	}
+	return s.verifyPIN(user, pin)
+}
+
+// ValidateRating checks a rating against the scheme; empty means unrestricted or unrated
+func (s *ParentalControlService) ValidateRating(rating models.MaturityRating) error {
+	if rating != "" && !s.scheme.IsValid(rating) {
+		return fmt.Errorf("invalid maturity rating: %s", rating)
+	}
+	return nil
+}
+
+// profileLimit returns a profile's effective limit; kids profiles default to the lowest rating
+func (s *ParentalControlService) profileLimit(profile *models.Profile) models.MaturityRating {
+	if profile.IsKids && profile.MaturityLimit == "" {
+		return s.scheme.Lowest()
+	}
+	return profile.MaturityLimit
+}
+
+// allowed converts a limit into the viewer's allowed ratings; nil means unrestricted
+func (s *ParentalControlService) allowed(limit models.MaturityRating) []models.MaturityRating {
+	if limit == "" {
+		return nil
+	}
+	return s.scheme.AllowedUpTo(limit)
+}
+
+// verifyPIN checks pin against the account's parental PIN. Wrong PINs are
+// counted, and once too many follow each other the PIN is refused until the
+// lockout has passed, so that it cannot be guessed one request at a time.
+func (s *ParentalControlService) verifyPIN(user *models.User, pin string) error {
+	if user.ParentalPIN == "" || pin == "" {
+		return ErrParentalPINRequired
+	}
+	now := time.Now()
+	if user.ParentalPINLockedUntil != nil && now.Before(*user.ParentalPINLockedUntil) {
+		return ErrParentalPINLocked
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.ParentalPIN), []byte(pin)) != nil {
+		if err := s.userRepo.RecordPINFailure(user.ID, parentalPINMaxFailures, now.Add(parentalPINLockout)); err != nil {
+			return err
+		}
+		return ErrParentalPINRequired
+	}
+
+	if user.ParentalPINFailures > 0 || user.ParentalPINLockedUntil != nil {
+		if err := s.userRepo.ResetPINFailures(user.ID); err != nil {
+			return err
+		}
+		user.ParentalPINFailures, user.ParentalPINLockedUntil = 0, nil
+	}
+	return nil
+}
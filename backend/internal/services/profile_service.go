@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+// ProfileService handles business logic for viewer profiles
+type ProfileService struct {
+	profileRepo     *repository.ProfileRepository
+	userService     *UserService
+	parentalService *ParentalControlService
+}
+
+// NewProfileService creates a new ProfileService
+func NewProfileService(
+	profileRepo *repository.ProfileRepository,
+	userService *UserService,
+	parentalService *ParentalControlService,
+) *ProfileService {
+	return &ProfileService{
+		profileRepo:     profileRepo,
+		userService:     userService,
+		parentalService: parentalService,
+	}
+}
+
+// ListProfiles lists the profiles of a user
+func (s *ProfileService) ListProfiles(userID uint) ([]models.Profile, error) {
+	return s.profileRepo.ListByUserID(userID)
+}
+
+// CreateProfile creates a new profile for a user; requires the parental PIN when one is set
+func (s *ProfileService) CreateProfile(userID uint, profile *models.Profile, pin string) error {
+	if err := s.parentalService.ValidateRating(profile.MaturityLimit); err != nil {
+		return err
+	}
+	if err := s.parentalService.RequirePIN(userID, pin); err != nil {
+		return err
+	}
+
+	count, err := s.profileRepo.CountByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if count >= models.MaxProfilesPerUser {
+		return fmt.Errorf("an account can have at most %d profiles", models.MaxProfilesPerUser)
+	}
+
+	profile.ID = 0
+	profile.UserID = userID
+	return s.profileRepo.Create(profile)
+}
+
+// GetProfile retrieves a profile owned by the user
+func (s *ProfileService) GetProfile(userID, profileID uint) (*models.Profile, error) {
+	profile, err := s.profileRepo.FindByID(profileID)
+	if err != nil || profile.UserID != userID {
+		return nil, errors.New("profile not found")
+	}
+	return profile, nil
+}
+
+// UpdateProfile updates a profile owned by the user.
+// Loosening its maturity restrictions requires the parental PIN when one is set.
+func (s *ProfileService) UpdateProfile(userID uint, profile *models.Profile, pin string) error {
+	existing, err := s.GetProfile(userID, profile.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.parentalService.ValidateRating(profile.MaturityLimit); err != nil {
+		return err
+	}
+	if existing.MaturityLimit != profile.MaturityLimit || existing.IsKids != profile.IsKids {
+		if err := s.parentalService.RequirePIN(userID, pin); err != nil {
+			return err
+		}
+	}
+
+	profile.UserID = userID
+	return s.profileRepo.Update(profile)
+}
+
+// DeleteProfile deletes a profile owned by the user along with its watch history
+func (s *ProfileService) DeleteProfile(userID, profileID uint, pin string) error {
+	if _, err := s.GetProfile(userID, profileID); err != nil {
+		return err
+	}
+	if err := s.parentalService.RequirePIN(userID, pin); err != nil {
+		return err
+	}
+	return s.profileRepo.Delete(profileID)
+}
+
+// SelectProfile issues a token scoped to the chosen profile.
+// Switching from a kids profile to a regular one requires the parental PIN when one is set.
+func (s *ProfileService) SelectProfile(userID, profileID uint, currentProfileID *uint, pin string) (string, *models.Profile, error) {
+	profile, err := s.GetProfile(userID, profileID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if currentProfileID != nil && !profile.IsKids {
+		current, err := s.GetProfile(userID, *currentProfileID)
+		if err == nil && current.IsKids {
+			if err := s.parentalService.RequirePIN(userID, pin); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+
+	user, err := s.userService.GetUserByID(userID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token, err := s.userService.GenerateProfileToken(user, profile.ID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, profile, nil
+}
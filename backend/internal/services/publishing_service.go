@@ -0,0 +1,70 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+)
+
+// PublishingService flips scheduled content and episodes live once their publish time has passed
+type PublishingService struct {
+	revisionService *RevisionService
+}
+
+// NewPublishingService creates a new PublishingService
+func NewPublishingService(revisionService *RevisionService) *PublishingService {
+	return &PublishingService{
+		revisionService: revisionService,
+	}
+}
+
+// PublishDue publishes every scheduled content and episode that is due and
+// records a revision of each, attributed to the system
+func (s *PublishingService) PublishDue() (int, int, error) {
+	now := time.Now()
+	var contents, episodes []uint
+	err := s.revisionService.Write(0, func(w *RevisionWriter) error {
+		var err error
+		if contents, err = w.Contents.PublishDue(now); err != nil {
+			return err
+		}
+		for _, id := range contents {
+			if err := w.RecordContent(id, models.RevisionUpdate); err != nil {
+				return err
+			}
+		}
+
+		if episodes, err = w.Episodes.PublishDue(now); err != nil {
+			return err
+		}
+		for _, id := range episodes {
+			if err := w.RecordEpisode(id, models.RevisionUpdate); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(contents), len(episodes), nil
+}
+
+// StartScheduler periodically publishes scheduled content and episodes
+func (s *PublishingService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if contents, episodes, err := s.PublishDue(); err != nil {
+				log.Printf("Scheduled publishing failed: %v", err)
+			} else if contents > 0 || episodes > 0 {
+				log.Printf("Published %d scheduled contents and %d scheduled episodes", contents, episodes)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,501 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+	"gorm.io/gorm"
+)
+
+// snapshotIgnored lists the JSON keys left out of snapshots: relations are either
+// recorded separately (genre and category IDs) or have their own history, and
+// timestamps and versions change on every save
+var snapshotIgnored = []string{
+	"created_at", "updated_at", "version", "slug", "display_title", "display_description",
+	"episodes", "genres", "categories", "category", "type", "season", "titles", "descriptions",
+	"stream_links", "download_links", "tags", "content", "chapters",
+}
+
+// RevisionService records content and episode revisions and rolls them back
+type RevisionService struct {
+	revisionRepo *repository.RevisionRepository
+	contentRepo  *repository.ContentRepository
+	episodeRepo  *repository.EpisodeRepository
+}
+
+// RevisionDiff is the difference between two revisions of the same record
+type RevisionDiff struct {
+	From    *models.ContentRevision `json:"from"`
+	To      *models.ContentRevision `json:"to"`
+	Changes []models.FieldChange    `json:"changes"`
+}
+
+// NewRevisionService creates a new RevisionService
+func NewRevisionService(
+	revisionRepo *repository.RevisionRepository,
+	contentRepo *repository.ContentRepository,
+	episodeRepo *repository.EpisodeRepository,
+) *RevisionService {
+	return &RevisionService{
+		revisionRepo: revisionRepo,
+		contentRepo:  contentRepo,
+		episodeRepo:  episodeRepo,
+	}
+}
+
+// RevisionWriter carries a write through RevisionService.Write. Its
+// repositories are bound to the write's transaction, so the revisions it
+// records are stored together with the change or not at all.
+type RevisionWriter struct {
+	Contents *repository.ContentRepository
+	Episodes *repository.EpisodeRepository
+	Trash    *repository.TrashRepository
+	Analyses *repository.IntroAnalysisRepository
+
+	revisions *RevisionService
+	userID    *uint
+}
+
+// Write runs a mutation in a transaction together with the revisions it
+// records. User ID 0 stands for changes made by the system, such as
+// scheduled publishing.
+func (s *RevisionService) Write(userID uint, write func(w *RevisionWriter) error) error {
+	return s.revisionRepo.Transaction(func(tx *gorm.DB) error {
+		revisions := NewRevisionService(
+			repository.NewRevisionRepository(tx),
+			repository.NewContentRepository(tx),
+			repository.NewEpisodeRepository(tx),
+		)
+		return write(&RevisionWriter{
+			Contents:  revisions.contentRepo,
+			Episodes:  revisions.episodeRepo,
+			Trash:     repository.NewTrashRepository(tx),
+			Analyses:  repository.NewIntroAnalysisRepository(tx),
+			revisions: revisions,
+			userID:    author(userID),
+		})
+	})
+}
+
+// RecordContent stores a snapshot of a content as the write left it
+func (w *RevisionWriter) RecordContent(contentID uint, action models.RevisionAction) error {
+	content, err := w.revisions.contentRepo.FindWithDeleted(contentID, "Genres", "Categories")
+	if err != nil {
+		return err
+	}
+	snapshot, err := w.revisions.contentSnapshot(content)
+	if err != nil {
+		return err
+	}
+	return w.revisions.record(w.userID, content.ID, models.RevisionEntityContent, content.ID, action, snapshot)
+}
+
+// RecordEpisode stores a snapshot of an episode as the write left it
+func (w *RevisionWriter) RecordEpisode(episodeID uint, action models.RevisionAction) error {
+	episode, err := w.revisions.episodeRepo.FindWithDeleted(episodeID)
+	if err != nil {
+		return err
+	}
+	snapshot, err := w.revisions.episodeSnapshot(episode)
+	if err != nil {
+		return err
+	}
+	return w.revisions.record(w.userID, episode.ContentID, models.RevisionEntityEpisode, episode.ID, action, snapshot)
+}
+
+// author is the user a revision is attributed to; nil for the system
+func author(userID uint) *uint {
+	if userID == 0 {
+		return nil
+	}
+	return &userID
+}
+
+func (s *RevisionService) record(userID *uint, contentID uint, entityType models.RevisionEntity, entityID uint, action models.RevisionAction, snapshot map[string]interface{}) error {
+	revision, err := s.newRevision(userID, contentID, entityType, entityID, action, snapshot)
+	if err != nil {
+		return err
+	}
+	// Saving a form without touching anything is not worth a revision
+	if action == models.RevisionUpdate && len(revision.Changes) == 0 {
+		return nil
+	}
+	return s.revisionRepo.Create(revision)
+}
+
+// newRevision builds a revision whose changes are relative to the latest one of the same record
+func (s *RevisionService) newRevision(userID *uint, contentID uint, entityType models.RevisionEntity, entityID uint, action models.RevisionAction, snapshot map[string]interface{}) (*models.ContentRevision, error) {
+	previous := map[string]interface{}{}
+	latest, err := s.revisionRepo.FindLatest(entityType, entityID)
+	if err == nil {
+		previous = latest.Snapshot
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return &models.ContentRevision{
+		ContentID:  contentID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		UserID:     userID,
+		Snapshot:   snapshot,
+		Changes:    diffSnapshots(previous, snapshot),
+	}, nil
+}
+
+// ListRevisions lists the revisions of a content and its episodes, newest first
+func (s *RevisionService) ListRevisions(contentID uint, page, pageSize int) ([]models.ContentRevision, int64, error) {
+	if _, err := s.contentRepo.FindWithDeleted(contentID); err != nil {
+		return nil, 0, errors.New("content not found")
+	}
+	return s.revisionRepo.ListByContentID(contentID, page, pageSize)
+}
+
+// DiffRevisions compares two revisions of the same content or episode
+func (s *RevisionService) DiffRevisions(contentID, fromID, toID uint) (*RevisionDiff, error) {
+	from, err := s.getRevision(contentID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.getRevision(contentID, toID)
+	if err != nil {
+		return nil, err
+	}
+	if from.EntityType != to.EntityType || from.EntityID != to.EntityID {
+		return nil, errors.New("revisions belong to different records")
+	}
+
+	return &RevisionDiff{
+		From:    from,
+		To:      to,
+		Changes: diffSnapshots(from.Snapshot, to.Snapshot),
+	}, nil
+}
+
+// ErrRestoreTrashed is returned when a revision of a record in the trash is restored
+var ErrRestoreTrashed = errors.New("record is in the trash; restore it from the trash first")
+
+// RestoreVersion returns the current version of the record a revision
+// belongs to, which a restore has to be based on
+func (s *RevisionService) RestoreVersion(contentID, revisionID uint) (uint, error) {
+	target, err := s.getRevision(contentID, revisionID)
+	if err != nil {
+		return 0, err
+	}
+	switch target.EntityType {
+	case models.RevisionEntityContent:
+		content, err := s.contentRepo.FindWithDeleted(target.EntityID)
+		if err != nil {
+			return 0, errors.New("content not found")
+		}
+		if content.DeletedAt.Valid {
+			return 0, ErrRestoreTrashed
+		}
+		return content.Version, nil
+	case models.RevisionEntityEpisode:
+		episode, err := s.episodeRepo.FindWithDeleted(target.EntityID)
+		if err != nil {
+			return 0, errors.New("episode not found")
+		}
+		if episode.DeletedAt.Valid {
+			return 0, ErrRestoreTrashed
+		}
+		return episode.Version, nil
+	}
+	return 0, errors.New("unknown revision type")
+}
+
+// Restore rolls a content or episode at the given version back to the state
+// of a revision. The write and the new "restore" revision are stored in a
+// single transaction. Records in the trash are refused, since bringing them
+// back goes through the trash with their episodes and links.
+func (s *RevisionService) Restore(userID, contentID, revisionID, version uint) (*models.ContentRevision, error) {
+	target, err := s.getRevision(contentID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(target.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var restored *models.ContentRevision
+	err = s.Write(userID, func(w *RevisionWriter) error {
+		switch target.EntityType {
+		case models.RevisionEntityContent:
+			if err := w.restoreContent(target, data, version); err != nil {
+				return err
+			}
+			if err := w.RecordContent(target.EntityID, models.RevisionRestore); err != nil {
+				return err
+			}
+		case models.RevisionEntityEpisode:
+			if err := w.restoreEpisode(target, data, version); err != nil {
+				return err
+			}
+			if err := w.RecordEpisode(target.EntityID, models.RevisionRestore); err != nil {
+				return err
+			}
+		default:
+			return errors.New("unknown revision type")
+		}
+		latest, err := w.revisions.revisionRepo.FindLatest(target.EntityType, target.EntityID)
+		restored = latest
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// restoreContent writes a content back from a snapshot together with its
+// genres, categories, cours and arcs
+func (w *RevisionWriter) restoreContent(target *models.ContentRevision, data []byte, version uint) error {
+	content, err := w.Contents.FindWithDeleted(target.EntityID)
+	if err != nil {
+		return errors.New("content not found")
+	}
+	if content.DeletedAt.Valid {
+		return ErrRestoreTrashed
+	}
+	if err := json.Unmarshal(data, content); err != nil {
+		return err
+	}
+	content.ID = target.EntityID
+	content.Version = version
+	if err := w.Contents.Update(content); err != nil {
+		return err
+	}
+
+	genreIDs := snapshotIDs(target.Snapshot["genre_ids"])
+	categoryIDs := snapshotIDs(target.Snapshot["category_ids"])
+	var cours []models.EpisodeCour
+	if err := snapshotRows(target.Snapshot, "cours", &cours); err != nil {
+		return err
+	}
+	for i := range cours {
+		cours[i].ContentID = content.ID
+	}
+	var arcs []models.StoryArc
+	if err := snapshotRows(target.Snapshot, "arcs", &arcs); err != nil {
+		return err
+	}
+	for i := range arcs {
+		arcs[i].ContentID = content.ID
+	}
+	return w.revisions.revisionRepo.RestoreContentRelations(content.ID, genreIDs, categoryIDs, cours, arcs)
+}
+
+// restoreEpisode writes an episode back from a snapshot together with its chapters
+func (w *RevisionWriter) restoreEpisode(target *models.ContentRevision, data []byte, version uint) error {
+	episode, err := w.Episodes.FindWithDeleted(target.EntityID)
+	if err != nil {
+		return errors.New("episode not found")
+	}
+	if episode.DeletedAt.Valid {
+		return ErrRestoreTrashed
+	}
+	if err := json.Unmarshal(data, episode); err != nil {
+		return err
+	}
+	episode.ID = target.EntityID
+	episode.ContentID = target.ContentID
+	episode.Version = version
+	episode.Chapters = nil
+	if err := w.Episodes.Update(episode); err != nil {
+		return err
+	}
+
+	var chapters []models.Chapter
+	if err := snapshotRows(target.Snapshot, "chapters", &chapters); err != nil {
+		return err
+	}
+	// Nil chapters come from snapshots taken before they were recorded
+	if chapters == nil {
+		return nil
+	}
+	for i := range chapters {
+		chapters[i].EpisodeID = episode.ID
+		chapters[i].ContentID = episode.ContentID
+	}
+	return w.Episodes.ReplaceChapters(episode.ID, chapters)
+}
+
+func (s *RevisionService) getRevision(contentID, revisionID uint) (*models.ContentRevision, error) {
+	revision, err := s.revisionRepo.FindByID(revisionID)
+	if err != nil || revision.ContentID != contentID {
+		return nil, errors.New("revision not found")
+	}
+	return revision, nil
+}
+
+// contentSnapshot captures the fields of a content plus its genre and category
+// IDs, its split cours and its story arcs
+func (s *RevisionService) contentSnapshot(content *models.Content) (map[string]interface{}, error) {
+	cours, err := s.episodeRepo.ListCours(content.ID)
+	if err != nil {
+		return nil, err
+	}
+	arcs, err := s.episodeRepo.ListArcs(content.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := contentSnapshot(content)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot["cours"], err = rowsSnapshot(cours); err != nil {
+		return nil, err
+	}
+	if snapshot["arcs"], err = rowsSnapshot(arcs); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// episodeSnapshot captures the fields of an episode plus its chapters
+func (s *RevisionService) episodeSnapshot(episode *models.Episode) (map[string]interface{}, error) {
+	chapters, err := s.episodeRepo.ListChapters(episode.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := toSnapshot(episode)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot["chapters"], err = rowsSnapshot(chapters); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// contentSnapshot captures the fields of a content plus its genre and category IDs
+func contentSnapshot(content *models.Content) (map[string]interface{}, error) {
+	genreIDs := make([]uint, 0, len(content.Genres))
+	for _, genre := range content.Genres {
+		genreIDs = append(genreIDs, genre.ID)
+	}
+	categoryIDs := make([]uint, 0, len(content.Categories))
+	for _, category := range content.Categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+
+	snapshot, err := toSnapshot(content)
+	if err != nil {
+		return nil, err
+	}
+	snapshot["genre_ids"] = genreIDs
+	snapshot["category_ids"] = categoryIDs
+
+	// Round-trip once more so the IDs have the same types as a snapshot read back from the database
+	return normalizeSnapshot(snapshot)
+}
+
+// toSnapshot turns a model into a map through its JSON representation
+func toSnapshot(value interface{}) (map[string]interface{}, error) {
+	snapshot, err := normalizeSnapshot(value)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range snapshotIgnored {
+		delete(snapshot, key)
+	}
+	return snapshot, nil
+}
+
+func normalizeSnapshot(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// diffSnapshots lists the fields that differ between two snapshots, sorted by name
+func diffSnapshots(old, new map[string]interface{}) []models.FieldChange {
+	fields := map[string]bool{}
+	for field := range old {
+		fields[field] = true
+	}
+	for field := range new {
+		fields[field] = true
+	}
+
+	changes := []models.FieldChange{}
+	for field := range fields {
+		if !reflect.DeepEqual(old[field], new[field]) {
+			changes = append(changes, models.FieldChange{
+				Field: field,
+				Old:   old[field],
+				New:   new[field],
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+// rowKeys are left out of the child rows in a snapshot; they change whenever
+// the rows are replaced, even if nothing else did
+var rowKeys = []string{"id", "content_id", "episode_id", "created_at", "updated_at"}
+
+// rowsSnapshot captures child rows, such as chapters, through their JSON representation
+func rowsSnapshot(rows interface{}) ([]interface{}, error) {
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	var items []map[string]interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	snapshot := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		for _, key := range rowKeys {
+			delete(item, key)
+		}
+		snapshot = append(snapshot, item)
+	}
+	return snapshot, nil
+}
+
+// snapshotRows reads child rows back from a decoded snapshot. Rows stays nil
+// when the snapshot was taken before the rows were recorded.
+func snapshotRows(snapshot map[string]interface{}, key string, rows interface{}) error {
+	value, ok := snapshot[key]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, rows)
+}
+
+// snapshotIDs reads a list of IDs back from a decoded snapshot
+func snapshotIDs(value interface{}) []uint {
+	items, _ := value.([]interface{})
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		if id, ok := item.(float64); ok && id > 0 {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
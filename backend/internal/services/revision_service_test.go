@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+func TestRowsSnapshot(t *testing.T) {
+	saved := []models.Chapter{
+		{ID: 3, EpisodeID: 9, ContentID: 2, Kind: models.ChapterIntro, Start: 0, End: 90, CreatedAt: time.Now()},
+		{ID: 4, EpisodeID: 9, ContentID: 2, Kind: models.ChapterCredits, Start: 1300, End: 1420, CreatedAt: time.Now()},
+	}
+	// The same chapters sent again are stored as new rows
+	replaced := []models.Chapter{
+		{ID: 7, EpisodeID: 9, ContentID: 2, Kind: models.ChapterIntro, Start: 0, End: 90, CreatedAt: time.Now().Add(time.Hour)},
+		{ID: 8, EpisodeID: 9, ContentID: 2, Kind: models.ChapterCredits, Start: 1300, End: 1420, CreatedAt: time.Now().Add(time.Hour)},
+	}
+
+	before, err := rowsSnapshot(saved)
+	require.NoError(t, err)
+	after, err := rowsSnapshot(replaced)
+	require.NoError(t, err)
+	assert.Empty(t, diffSnapshots(map[string]interface{}{"chapters": before}, map[string]interface{}{"chapters": after}),
+		"replacing chapters with the same ones is not a change")
+
+	moved, err := rowsSnapshot([]models.Chapter{{Kind: models.ChapterIntro, Start: 5, End: 95}})
+	require.NoError(t, err)
+	changes := diffSnapshots(map[string]interface{}{"chapters": before}, map[string]interface{}{"chapters": moved})
+	require.Len(t, changes, 1)
+	assert.Equal(t, "chapters", changes[0].Field)
+
+	var restored []models.Chapter
+	require.NoError(t, snapshotRows(map[string]interface{}{"chapters": before}, "chapters", &restored))
+	require.Len(t, restored, 2)
+	assert.Zero(t, restored[0].ID)
+	assert.Equal(t, models.ChapterCredits, restored[1].Kind)
+	assert.Equal(t, 1420.0, restored[1].End)
+
+	var empty []models.Chapter
+	require.NoError(t, snapshotRows(map[string]interface{}{"chapters": []interface{}{}}, "chapters", &empty))
+	assert.NotNil(t, empty, "an empty list clears the chapters on restore")
+
+	var untouched []models.Chapter
+	require.NoError(t, snapshotRows(map[string]interface{}{}, "chapters", &untouched))
+	assert.Nil(t, untouched, "snapshots from before chapters were recorded leave them alone")
+}
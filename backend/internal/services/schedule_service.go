@@ -0,0 +1,341 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+// Calendar feeds cover a week of history and the next three months
+const (
+	calendarPast   = 7 * 24 * time.Hour
+	calendarFuture = 90 * 24 * time.Hour
+)
+
+// defaultBroadcastLength is used for calendar events of titles without a duration
+const defaultBroadcastLength = 24 * time.Minute
+
+// ScheduleService handles airing schedules and calendar feeds
+type ScheduleService struct {
+	scheduleRepo     *repository.ScheduleRepository
+	contentRepo      *repository.ContentRepository
+	watchHistoryRepo *repository.WatchHistoryRepository
+	userRepo         *repository.UserRepository
+	parentalService  *ParentalControlService
+}
+
+// ScheduleDay holds the broadcasts of one day of the week
+type ScheduleDay struct {
+	Weekday string                   `json:"weekday"`
+	Date    string                   `json:"date"`
+	Entries []models.EpisodeSchedule `json:"entries"`
+}
+
+// WeeklySchedule holds the broadcasts of a week grouped by day in the caller's timezone
+type WeeklySchedule struct {
+	Week     string        `json:"week"`
+	Timezone string        `json:"timezone"`
+	Days     []ScheduleDay `json:"days"`
+}
+
+// NewScheduleService creates a new ScheduleService
+func NewScheduleService(
+	scheduleRepo *repository.ScheduleRepository,
+	contentRepo *repository.ContentRepository,
+	watchHistoryRepo *repository.WatchHistoryRepository,
+	userRepo *repository.UserRepository,
+	parentalService *ParentalControlService,
+) *ScheduleService {
+	return &ScheduleService{
+		scheduleRepo:     scheduleRepo,
+		contentRepo:      contentRepo,
+		watchHistoryRepo: watchHistoryRepo,
+		userRepo:         userRepo,
+		parentalService:  parentalService,
+	}
+}
+
+// AddEntries adds upcoming broadcasts to a content
+func (s *ScheduleService) AddEntries(contentID uint, entries []models.EpisodeSchedule) ([]models.EpisodeSchedule, error) {
+	if _, err := s.contentRepo.FindByID(contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no schedule entries given")
+	}
+
+	for i := range entries {
+		if entries[i].EpisodeNumber <= 0 {
+			return nil, errors.New("episode number must be positive")
+		}
+		if entries[i].AirsAt.IsZero() {
+			return nil, errors.New("airs_at is required")
+		}
+		if entries[i].SeasonNumber == 0 {
+			entries[i].SeasonNumber = 1
+		}
+		entries[i].ID = 0
+		entries[i].ContentID = contentID
+		entries[i].AirsAt = entries[i].AirsAt.UTC()
+	}
+
+	if err := s.scheduleRepo.Create(entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListContentSchedule lists the broadcasts of a content
+func (s *ScheduleService) ListContentSchedule(viewer *models.Viewer, contentID uint) ([]models.EpisodeSchedule, error) {
+	if _, err := s.contentRepo.FindVisibleByID(viewer, contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+	return s.scheduleRepo.ListByContentID(contentID)
+}
+
+// DeleteEntry removes a broadcast from a content's schedule
+func (s *ScheduleService) DeleteEntry(contentID, scheduleID uint) error {
+	schedule, err := s.scheduleRepo.FindByID(scheduleID)
+	if err != nil || schedule.ContentID != contentID {
+		return errors.New("schedule entry not found")
+	}
+	return s.scheduleRepo.Delete(scheduleID)
+}
+
+// GetWeek returns the broadcasts of an ISO week ("2024-W15", empty for the current week)
+// grouped by weekday in the given location
+func (s *ScheduleService) GetWeek(viewer *models.Viewer, week string, loc *time.Location) (*WeeklySchedule, error) {
+	start, err := weekStart(week, time.Now(), loc)
+	if err != nil {
+		return nil, err
+	}
+	end := start.AddDate(0, 0, 7)
+
+	schedules, err := s.scheduleRepo.ListBetween(viewer, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	year, number := start.ISOWeek()
+	result := &WeeklySchedule{
+		Week:     fmt.Sprintf("%d-W%02d", year, number),
+		Timezone: loc.String(),
+		Days:     make([]ScheduleDay, 7),
+	}
+	for i := range result.Days {
+		day := start.AddDate(0, 0, i)
+		result.Days[i] = ScheduleDay{
+			Weekday: strings.ToLower(day.Weekday().String()),
+			Date:    day.Format("2006-01-02"),
+			Entries: []models.EpisodeSchedule{},
+		}
+	}
+
+	for _, schedule := range schedules {
+		local := schedule.AirsAt.In(loc)
+		index := int(local.Sub(start) / (24 * time.Hour))
+		// DST changes can make a day 23 or 25 hours long; fall back to comparing dates
+		for index > 0 && local.Before(start.AddDate(0, 0, index)) {
+			index--
+		}
+		for index < 6 && !local.Before(start.AddDate(0, 0, index+1)) {
+			index++
+		}
+		schedule.AirsAt = local
+		result.Days[index].Entries = append(result.Days[index].Entries, schedule)
+	}
+
+	return result, nil
+}
+
+// CalendarToken returns the secret for the user's calendar feed, creating one on first use
+func (s *ScheduleService) CalendarToken(userID uint) (string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", err
+	}
+	if user.CalendarToken != "" {
+		return user.CalendarToken, nil
+	}
+	return s.rotateCalendarToken(user)
+}
+
+// ResetCalendarToken invalidates the current calendar feed URL and returns a new secret
+func (s *ScheduleService) ResetCalendarToken(userID uint) (string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", err
+	}
+	return s.rotateCalendarToken(user)
+}
+
+func (s *ScheduleService) rotateCalendarToken(user *models.User) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	user.CalendarToken = token
+	if err := s.userRepo.Update(user); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// BuildCalendar renders an iCalendar feed with the upcoming broadcasts of the titles
+// the owner of the token has been watching and may still see
+func (s *ScheduleService) BuildCalendar(token string) ([]byte, error) {
+	if token == "" {
+		return nil, errors.New("invalid calendar token")
+	}
+	user, err := s.userRepo.FindByCalendarToken(token)
+	if err != nil {
+		return nil, errors.New("invalid calendar token")
+	}
+
+	// The feed is read without a profile, so the account-wide limit applies
+	viewer, err := s.parentalService.ResolveViewer(user.ID, user.Role, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	contentIDs, err := s.watchHistoryRepo.ListContentIDsByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	schedules, err := s.scheduleRepo.ListBetweenForContents(viewer, contentIDs, now.Add(-calendarPast), now.Add(calendarFuture))
+	if err != nil {
+		return nil, err
+	}
+
+	return renderCalendar(schedules, now), nil
+}
+
+// renderCalendar writes schedules as an RFC 5545 calendar
+func renderCalendar(schedules []models.EpisodeSchedule, now time.Time) []byte {
+	var buf bytes.Buffer
+	writeLine := func(line string) {
+		buf.WriteString(foldICalLine(line))
+		buf.WriteString("\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//PortalAnime//Schedule//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("X-WR-CALNAME:PortalAnime")
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, schedule := range schedules {
+		length := defaultBroadcastLength
+		summary := fmt.Sprintf("Episode %d", schedule.EpisodeNumber)
+		if schedule.Content != nil {
+			summary = fmt.Sprintf("%s - Episode %d", schedule.Content.Title, schedule.EpisodeNumber)
+			if schedule.Content.Duration != nil && *schedule.Content.Duration > 0 {
+				length = time.Duration(*schedule.Content.Duration) * time.Minute
+			}
+		}
+		if schedule.Title != "" {
+			summary += ": " + schedule.Title
+		}
+
+		writeLine("BEGIN:VEVENT")
+		writeLine("UID:episode-schedule-" + strconv.FormatUint(uint64(schedule.ID), 10) + "@portalanime")
+		writeLine("DTSTAMP:" + stamp)
+		writeLine("DTSTART:" + schedule.AirsAt.UTC().Format("20060102T150405Z"))
+		writeLine("DTEND:" + schedule.AirsAt.Add(length).UTC().Format("20060102T150405Z"))
+		writeLine("SUMMARY:" + escapeICalText(summary))
+		writeLine("END:VEVENT")
+	}
+
+	writeLine("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// escapeICalText escapes a TEXT value as required by RFC 5545
+func escapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
+
+// foldICalLine splits lines longer than 75 octets without breaking UTF-8 sequences
+func foldICalLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
+
+// weekStart returns midnight of the Monday that starts the given ISO week in loc
+func weekStart(week string, now time.Time, loc *time.Location) (time.Time, error) {
+	if week == "" {
+		local := now.In(loc)
+		offset := (int(local.Weekday()) + 6) % 7
+		return time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, loc), nil
+	}
+
+	var year, number int
+	if _, err := fmt.Sscanf(week, "%d-W%d", &year, &number); err != nil || number < 1 || number > 53 {
+		return time.Time{}, errors.New("week must look like 2024-W15")
+	}
+
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	start := time.Date(year, time.January, 4-offset+(number-1)*7, 0, 0, 0, 0, loc)
+	// Only some years have a week 53
+	if isoYear, isoWeek := start.ISOWeek(); isoYear != year || isoWeek != number {
+		return time.Time{}, fmt.Errorf("%d has no week %d", year, number)
+	}
+	return start, nil
+}
+
+// weekdays lists the valid values of Content.BroadcastDay
+var weekdays = map[string]bool{
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true,
+	"friday": true, "saturday": true, "sunday": true,
+}
+
+// validateBroadcast checks the airing fields of a content
+func validateBroadcast(content *models.Content) error {
+	if !content.AiringStatus.IsValid() {
+		return fmt.Errorf("invalid airing status: %s", content.AiringStatus)
+	}
+	content.BroadcastDay = strings.ToLower(content.BroadcastDay)
+	if content.BroadcastDay != "" && !weekdays[content.BroadcastDay] {
+		return fmt.Errorf("invalid broadcast day: %s", content.BroadcastDay)
+	}
+	if content.BroadcastTime != "" {
+		if _, err := time.Parse("15:04", content.BroadcastTime); err != nil {
+			return errors.New("broadcast time must be HH:MM")
+		}
+	}
+	if content.BroadcastTimezone != "" {
+		if _, err := time.LoadLocation(content.BroadcastTimezone); err != nil {
+			return fmt.Errorf("invalid broadcast timezone: %s", content.BroadcastTimezone)
+		}
+	}
+	if content.EpisodeCount != nil && *content.EpisodeCount < 0 {
+		return errors.New("episode count cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeekStart(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		week string
+		now  time.Time
+		loc  *time.Location
+		want time.Time
+	}{
+		{name: "ISO week", week: "2024-W15", loc: time.UTC, want: time.Date(2024, time.April, 8, 0, 0, 0, 0, time.UTC)},
+		{name: "week 1 starting in the year before", week: "2025-W01", loc: time.UTC, want: time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC)},
+		{name: "week 53", week: "2020-W53", loc: time.UTC, want: time.Date(2020, time.December, 28, 0, 0, 0, 0, time.UTC)},
+		{name: "in the caller's timezone", week: "2024-W15", loc: tokyo, want: time.Date(2024, time.April, 8, 0, 0, 0, 0, tokyo)},
+		{name: "current week on a Sunday", now: time.Date(2024, time.April, 14, 23, 0, 0, 0, time.UTC), loc: time.UTC, want: time.Date(2024, time.April, 8, 0, 0, 0, 0, time.UTC)},
+		{name: "current week on a Monday", now: time.Date(2024, time.April, 8, 0, 0, 0, 0, time.UTC), loc: time.UTC, want: time.Date(2024, time.April, 8, 0, 0, 0, 0, time.UTC)},
+		// Sunday night in UTC is already Monday in Tokyo
+		{name: "current week across timezones", now: time.Date(2024, time.April, 14, 20, 0, 0, 0, time.UTC), loc: tokyo, want: time.Date(2024, time.April, 15, 0, 0, 0, 0, tokyo)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := weekStart(tt.week, tt.now, tt.loc)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+			assert.Equal(t, time.Monday, got.Weekday())
+		})
+	}
+
+	for _, week := range []string{"2024-15", "W15", "2024-W0", "2024-W54", "2021-W53"} {
+		_, err := weekStart(week, time.Now(), time.UTC)
+		assert.Error(t, err, week)
+	}
+}
+
+func TestFoldICalLine(t *testing.T) {
+	short := "SUMMARY:" + strings.Repeat("a", 67)
+	assert.Equal(t, short, foldICalLine(short))
+
+	long := "SUMMARY:" + strings.Repeat("a", 200)
+	folded := foldICalLine(long)
+	lines := strings.Split(folded, "\r\n")
+	require.Len(t, lines, 3)
+	assert.Len(t, lines[0], 75)
+	for _, line := range lines[1:] {
+		assert.True(t, strings.HasPrefix(line, " "), "continuation lines start with a space")
+		assert.LessOrEqual(t, len(line), 75)
+	}
+	assert.Equal(t, long, strings.ReplaceAll(folded, "\r\n ", ""))
+
+	// Multi-byte characters are never split across lines
+	wide := "SUMMARY:" + strings.Repeat("進撃の巨人", 20)
+	folded = foldICalLine(wide)
+	for _, line := range strings.Split(folded, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, strings.ToValidUTF8(line, "?") == line, "line %q is valid UTF-8", line)
+	}
+	assert.Equal(t, wide, strings.ReplaceAll(folded, "\r\n ", ""))
+}
@@ -2,7 +2,9 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/username/anime-streaming/internal/models"
 	"github.com/username/anime-streaming/internal/repository"
@@ -10,13 +12,33 @@ import (
 
 // SeasonService handles business logic for seasons
 type SeasonService struct {
-	seasonRepo *repository.SeasonRepository
+	seasonRepo  *repository.SeasonRepository
+	contentRepo *repository.ContentRepository
+	slugService *SlugService
 }
 
+// SeasonChartDay holds the titles of a season that air on one day of the week
+type SeasonChartDay struct {
+	Weekday  string           `json:"weekday"`
+	Contents []models.Content `json:"contents"`
+}
+
+// SeasonChart holds the titles of a season grouped by airing weekday
+type SeasonChart struct {
+	Season      *models.Season   `json:"season"`
+	Days        []SeasonChartDay `json:"days"`
+	Unscheduled []models.Content `json:"unscheduled"` // titles without a broadcast day
+}
+
+// chartWeekdays lists the days of a season chart in order
+var chartWeekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
 // NewSeasonService creates a new SeasonService
-func NewSeasonService(seasonRepo *repository.SeasonRepository) *SeasonService {
+func NewSeasonService(seasonRepo *repository.SeasonRepository, contentRepo *repository.ContentRepository, slugService *SlugService) *SeasonService {
 	return &SeasonService{
-		seasonRepo: seasonRepo,
+		seasonRepo:  seasonRepo,
+		contentRepo: contentRepo,
+		slugService: slugService,
 	}
 }
 
@@ -24,14 +46,8 @@ func NewSeasonService(seasonRepo *repository.SeasonRepository) *SeasonService {
 func (s *SeasonService) CreateSeason(season *models.Season) error {
 	log.Printf("Creating new season: %s %d", season.Name, season.Year)
 
-	// Validate season name
-	if !isValidSeasonName(season.Name) {
-		return errors.New("invalid season name. Must be Winter, Spring, Summer, or Fall")
-	}
-
-	// Validate status
-	if !isValidStatus(season.Status) {
-		return errors.New("invalid status. Must be Coming Soon, Active, or Ended")
+	if err := applyCalendar(season, time.Now()); err != nil {
+		return err
 	}
 
 	// Check if season already exists
@@ -40,6 +56,11 @@ func (s *SeasonService) CreateSeason(season *models.Season) error {
 		return errors.New("season already exists")
 	}
 
+	season.Slug, _, err = s.slugService.Pick(models.SlugSeason, 0, season.Slug, fmt.Sprintf("%s %d", season.Name, season.Year))
+	if err != nil {
+		return err
+	}
+
 	return s.seasonRepo.Create(season)
 }
 
@@ -55,14 +76,8 @@ func (s *SeasonService) ListSeasons() ([]models.Season, error) {
 
 // UpdateSeason updates an existing season
 func (s *SeasonService) UpdateSeason(season *models.Season) error {
-	// Validate season name
-	if !isValidSeasonName(season.Name) {
-		return errors.New("invalid season name. Must be Winter, Spring, Summer, or Fall")
-	}
-
-	// Validate status
-	if !isValidStatus(season.Status) {
-		return errors.New("invalid status. Must be Coming Soon, Active, or Ended")
+	if err := applyCalendar(season, time.Now()); err != nil {
+		return err
 	}
 
 	// Check if season exists
@@ -79,7 +94,17 @@ func (s *SeasonService) UpdateSeason(season *models.Season) error {
 		}
 	}
 
-	return s.seasonRepo.Update(season)
+	slug, previous, err := s.slugService.Pick(models.SlugSeason, season.ID, season.Slug, fmt.Sprintf("%s %d", season.Name, season.Year))
+	if err != nil {
+		return err
+	}
+	season.Slug = slug
+
+	if err := s.seasonRepo.Update(season); err != nil {
+		return err
+	}
+	s.slugService.Moved(models.SlugSeason, season.ID, previous, slug)
+	return nil
 }
 
 // DeleteSeason deletes a season
@@ -87,27 +112,135 @@ func (s *SeasonService) DeleteSeason(id uint) error {
 	return s.seasonRepo.Delete(id)
 }
 
-// GetCurrentSeason gets the current active season
+// GetCurrentSeason gets the season airing today
 func (s *SeasonService) GetCurrentSeason() (*models.Season, error) {
-	return s.seasonRepo.GetCurrentSeason()
+	return s.seasonRepo.GetCurrentSeason(time.Now().UTC())
+}
+
+// GetChart groups the titles of a season the viewer may see by airing weekday
+func (s *SeasonService) GetChart(viewer *models.Viewer, id uint) (*SeasonChart, error) {
+	season, err := s.seasonRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("season not found")
+	}
+	contents, err := s.contentRepo.ListBySeason(viewer, id, "Category", "Genres", "Titles", "Descriptions")
+	if err != nil {
+		return nil, err
+	}
+
+	chart := &SeasonChart{
+		Season:      season,
+		Days:        make([]SeasonChartDay, len(chartWeekdays)),
+		Unscheduled: []models.Content{},
+	}
+	index := make(map[string]int, len(chartWeekdays))
+	for i, weekday := range chartWeekdays {
+		chart.Days[i] = SeasonChartDay{Weekday: weekday, Contents: []models.Content{}}
+		index[weekday] = i
+	}
+	for _, content := range contents {
+		if i, ok := index[content.BroadcastDay]; ok {
+			chart.Days[i].Contents = append(chart.Days[i].Contents, content)
+		} else {
+			chart.Unscheduled = append(chart.Unscheduled, content)
+		}
+	}
+	return chart, nil
 }
 
-// Helper functions
-func isValidSeasonName(name string) bool {
-	validNames := map[string]bool{
-		"Winter": true,
-		"Spring": true,
-		"Summer": true,
-		"Fall":   true,
+// SyncLifecycle makes sure the current and next seasons exist and moves every
+// season through Coming Soon, Active and Ended according to its dates
+func (s *SeasonService) SyncLifecycle(now time.Time) error {
+	now = now.UTC()
+	name, year := models.SeasonAt(now)
+	nextName, nextYear := models.NextSeason(name, year)
+	for _, upcoming := range []models.Season{{Name: name, Year: year}, {Name: nextName, Year: nextYear}} {
+		// A season an admin moved to the trash is not brought back
+		exists, err := s.seasonRepo.ExistsWithDeleted(upcoming.Name, upcoming.Year)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if err := s.CreateSeason(&upcoming); err != nil {
+			return fmt.Errorf("failed to create season %s %d: %v", upcoming.Name, upcoming.Year, err)
+		}
+		log.Printf("Created season %s %d", upcoming.Name, upcoming.Year)
+	}
+
+	seasons, err := s.seasonRepo.List()
+	if err != nil {
+		return err
+	}
+	for i := range seasons {
+		season := &seasons[i]
+		previous := season.Status
+		hadDates := season.StartDate != nil && season.EndDate != nil
+		if !hadDates {
+			// Seasons created before dates existed get the calendar ones
+			start, end, ok := models.SeasonBounds(season.Name, season.Year)
+			if !ok {
+				continue
+			}
+			season.StartDate, season.EndDate = &start, &end
+		}
+		season.Status = season.StatusAt(now)
+		if hadDates && season.Status == previous {
+			continue
+		}
+		if err := s.seasonRepo.UpdateLifecycle(season); err != nil {
+			return err
+		}
+		if season.Status != previous {
+			log.Printf("Season %s %d is now %s", season.Name, season.Year, season.Status)
+		}
 	}
-	return validNames[name]
+	return nil
 }
 
-func isValidStatus(status string) bool {
-	validStatuses := map[string]bool{
-		"Coming Soon": true,
-		"Active":      true,
-		"Ended":       true,
+// StartScheduler periodically creates upcoming seasons and updates season statuses
+func (s *SeasonService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.SyncLifecycle(time.Now()); err != nil {
+				log.Printf("Season lifecycle sync failed: %v", err)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+// dateOnly drops the time of day, keeping the calendar date
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// applyCalendar validates the name of a season, fills in the calendar dates
+// when none are given and derives the status from the dates
+func applyCalendar(season *models.Season, now time.Time) error {
+	start, end, ok := models.SeasonBounds(season.Name, season.Year)
+	if !ok {
+		return errors.New("invalid season name. Must be Winter, Spring, Summer, or Fall")
+	}
+
+	switch {
+	case season.StartDate == nil && season.EndDate == nil:
+		season.StartDate, season.EndDate = &start, &end
+	case season.StartDate == nil || season.EndDate == nil:
+		return errors.New("start_date and end_date must be set together")
+	default:
+		startDay, endDay := dateOnly(*season.StartDate), dateOnly(*season.EndDate)
+		if endDay.Before(startDay) {
+			return errors.New("end_date must not be before start_date")
+		}
+		season.StartDate, season.EndDate = &startDay, &endDay
 	}
-	return validStatuses[status]
+
+	season.Status = season.StatusAt(now.UTC())
+	return nil
 }
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+	"gorm.io/gorm"
+)
+
+// maxSlugLength keeps slugs readable; collision suffixes may add a few characters
+const maxSlugLength = 80
+
+// transliterations spells common non-ASCII letters with ASCII so that titles
+// like "Shingeki no Kyojin: Kōhen" or "Врата Штейна" still get readable slugs
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i", 'į': "i",
+	'ł': "l", 'ľ': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss", 'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ы': "y", 'э': "e", 'ю': "yu", 'я': "ya",
+	'&': "and", '@': "at", '+': "plus",
+}
+
+// SlugService generates slugs and resolves slugs back to records
+type SlugService struct {
+	slugRepo *repository.SlugRepository
+}
+
+// NewSlugService creates a new SlugService
+func NewSlugService(slugRepo *repository.SlugRepository) *SlugService {
+	return &SlugService{
+		slugRepo: slugRepo,
+	}
+}
+
+// slugify turns a title into lowercase ASCII words joined by dashes
+func slugify(title string) string {
+	var b strings.Builder
+	pendingDash := false
+	write := func(s string) {
+		if pendingDash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingDash = false
+		b.WriteString(s)
+	}
+
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			write(string(r))
+		case r == '\'' || r == '’':
+			// "Hell's Paradise" reads better as hells-paradise than hell-s-paradise
+		default:
+			if t, ok := transliterations[r]; ok {
+				// Symbols become words of their own: "Fate & Zero" -> fate-and-zero
+				symbol := r == '&' || r == '@' || r == '+'
+				pendingDash = pendingDash || symbol
+				write(t)
+				pendingDash = symbol
+				continue
+			}
+			pendingDash = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+		if i := strings.LastIndexByte(slug, '-'); i > maxSlugLength/2 {
+			slug = slug[:i]
+		}
+		slug = strings.TrimRight(slug, "-")
+	}
+	return slug
+}
+
+// slugBase slugifies a title into the slug to start from. A slug that is all
+// digits would be taken for an ID by Resolve, so titles like "86" or "1917"
+// get the entity name appended: 86-content.
+func slugBase(entity models.SlugEntity, title string) string {
+	base := slugify(title)
+	if base == "" {
+		return string(entity)
+	}
+	if isDigits(base) {
+		return base + "-" + string(entity)
+	}
+	return base
+}
+
+// isDigits reports whether s is made of ASCII digits only
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// hasBase reports whether slug is base or base with a collision suffix
+func hasBase(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// Pick chooses the slug for a record being created (id 0) or updated. An
+// explicitly requested slug wins; otherwise it follows the title. The previous
+// slug is returned so the caller can record a redirect once the save succeeds.
+func (s *SlugService) Pick(entity models.SlugEntity, id uint, requested, title string) (slug, previous string, err error) {
+	if id != 0 {
+		if previous, err = s.slugRepo.FindSlugByID(entity, id); err != nil {
+			return "", "", err
+		}
+	}
+	if requested != "" && requested == previous && !isDigits(previous) {
+		return previous, previous, nil
+	}
+
+	base := slugBase(entity, requested)
+	if requested == "" {
+		base = slugBase(entity, title)
+		// Keep the current slug (even with a suffix) while the title still produces it
+		if previous != "" && hasBase(previous, base) {
+			return previous, previous, nil
+		}
+	}
+
+	for n := 1; ; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken, err := s.slugRepo.IsTaken(entity, candidate, id)
+		if err != nil {
+			return "", "", err
+		}
+		if !taken {
+			return candidate, previous, nil
+		}
+	}
+}
+
+// Moved keeps the previous slug of a saved record as a redirect to it. An
+// all-digit slug from before they were avoided resolves as an ID, so it is
+// not worth keeping.
+func (s *SlugService) Moved(entity models.SlugEntity, id uint, previous, slug string) {
+	if previous == "" || previous == slug || isDigits(previous) {
+		return
+	}
+	if err := s.slugRepo.Move(entity, id, previous, slug); err != nil {
+		log.Printf("Failed to record slug redirect %s -> %s for %s %d: %v", previous, slug, entity, id, err)
+	}
+}
+
+// Resolve maps an ID or slug to a record ID. For a former slug it also returns
+// the current one so callers can redirect.
+func (s *SlugService) Resolve(entity models.SlugEntity, value string) (id uint, current string, err error) {
+	if numeric, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return uint(numeric), "", nil
+	}
+
+	id, err = s.slugRepo.FindIDBySlug(entity, value)
+	if err == nil {
+		return id, "", nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, "", err
+	}
+
+	redirect, err := s.slugRepo.FindRedirect(entity, value)
+	if err != nil {
+		return 0, "", err
+	}
+	current, err = s.slugRepo.FindSlugByID(entity, redirect.EntityID)
+	if err != nil {
+		return 0, "", err
+	}
+	return redirect.EntityID, current, nil
+}
+
+// Backfill gives a slug to every record created before slugs existed
+func (s *SlugService) Backfill() error {
+	for _, entity := range []models.SlugEntity{models.SlugContent, models.SlugGenre, models.SlugCategory, models.SlugSeason} {
+		sources, err := s.slugRepo.ListMissing(entity)
+		if err != nil {
+			return err
+		}
+		for _, source := range sources {
+			slug, _, err := s.Pick(entity, source.ID, "", source.Source)
+			if err != nil {
+				return err
+			}
+			if err := s.slugRepo.SetSlug(entity, source.ID, slug); err != nil {
+				return err
+			}
+		}
+		if len(sources) > 0 {
+			log.Printf("Generated slugs for %d %s records", len(sources), entity)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/username/anime-streaming/internal/models"
+)
+
+func TestSlugBase(t *testing.T) {
+	tests := []struct {
+		name   string
+		entity models.SlugEntity
+		title  string
+		want   string
+	}{
+		{name: "title", entity: models.SlugContent, title: "Hell's Paradise", want: "hells-paradise"},
+		{name: "title with digits", entity: models.SlugContent, title: "Mob Psycho 100", want: "mob-psycho-100"},
+		{name: "all-digit title", entity: models.SlugContent, title: "86", want: "86-content"},
+		{name: "all-digit title with punctuation", entity: models.SlugContent, title: "2012!", want: "2012-content"},
+		{name: "all-digit season", entity: models.SlugSeason, title: " 1917 ", want: "1917-season"},
+		{name: "nothing to slugify", entity: models.SlugGenre, title: "!!!", want: "genre"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, slugBase(tt.entity, tt.title))
+		})
+	}
+}
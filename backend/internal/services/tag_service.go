@@ -0,0 +1,246 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+	"gorm.io/gorm"
+)
+
+// TagService handles business logic for tags and tag suggestions
+type TagService struct {
+	tagRepo     *repository.TagRepository
+	contentRepo *repository.ContentRepository
+}
+
+// NewTagService creates a new TagService
+func NewTagService(tagRepo *repository.TagRepository, contentRepo *repository.ContentRepository) *TagService {
+	return &TagService{
+		tagRepo:     tagRepo,
+		contentRepo: contentRepo,
+	}
+}
+
+// CreateTag creates a new tag; names are unique regardless of case
+func (s *TagService) CreateTag(tag *models.Tag) error {
+	if err := s.prepareTag(tag); err != nil {
+		return err
+	}
+	if _, err := s.tagRepo.FindByName(tag.Name); err == nil {
+		return errors.New("tag already exists")
+	}
+	return s.tagRepo.Create(tag)
+}
+
+// GetTag retrieves a tag by ID
+func (s *TagService) GetTag(id uint) (*models.Tag, error) {
+	return s.tagRepo.FindByID(id)
+}
+
+// ListTags lists tags, optionally within a category
+func (s *TagService) ListTags(category models.TagCategory) ([]models.Tag, error) {
+	if category != "" && !category.IsValid() {
+		return nil, fmt.Errorf("invalid tag category: %s", category)
+	}
+	return s.tagRepo.List(category)
+}
+
+// UpdateTag updates a tag
+func (s *TagService) UpdateTag(tag *models.Tag) error {
+	if err := s.prepareTag(tag); err != nil {
+		return err
+	}
+	if existing, err := s.tagRepo.FindByName(tag.Name); err == nil && existing.ID != tag.ID {
+		return errors.New("tag already exists")
+	}
+	return s.tagRepo.Update(tag)
+}
+
+// DeleteTag deletes a tag and removes it from every title
+func (s *TagService) DeleteTag(id uint) error {
+	if _, err := s.tagRepo.FindByID(id); err != nil {
+		return errors.New("tag not found")
+	}
+	return s.tagRepo.Delete(id)
+}
+
+// ListContentTags lists the tags of a title the viewer may see
+func (s *TagService) ListContentTags(viewer *models.Viewer, contentID uint) ([]models.ContentTag, error) {
+	if _, err := s.contentRepo.FindVisibleByID(viewer, contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+	return s.tagRepo.ListByContentID(contentID)
+}
+
+// SetContentTag links a tag to a title with the given weight, or updates an existing link
+func (s *TagService) SetContentTag(contentID, tagID uint, weight int, isSpoiler bool) (*models.ContentTag, error) {
+	if _, err := s.contentRepo.FindByID(contentID); err != nil {
+		return nil, errors.New("content not found")
+	}
+	tag, err := s.tagRepo.FindByID(tagID)
+	if err != nil {
+		return nil, errors.New("tag not found")
+	}
+	if weight, err = normalizeTagWeight(weight); err != nil {
+		return nil, err
+	}
+
+	link := &models.ContentTag{
+		ContentID: contentID,
+		TagID:     tagID,
+		Weight:    weight,
+		IsSpoiler: isSpoiler,
+	}
+	if err := s.tagRepo.SetContentTag(link); err != nil {
+		return nil, fmt.Errorf("failed to tag content: %v", err)
+	}
+	link.Tag = tag
+	return link, nil
+}
+
+// RemoveContentTag unlinks a tag from a title
+func (s *TagService) RemoveContentTag(contentID, tagID uint) error {
+	removed, err := s.tagRepo.RemoveContentTag(contentID, tagID)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return errors.New("tag not found on content")
+	}
+	return nil
+}
+
+// SuggestTag records a user's suggestion for a title. A suggestion naming an
+// existing tag is tied to it; otherwise the tag is created on approval.
+func (s *TagService) SuggestTag(viewer *models.Viewer, userID uint, suggestion *models.TagSuggestion) error {
+	if _, err := s.contentRepo.FindVisibleByID(viewer, suggestion.ContentID); err != nil {
+		return errors.New("content not found")
+	}
+
+	suggestion.Name = models.NormalizeTagName(suggestion.Name)
+	if suggestion.Name == "" {
+		return errors.New("tag name is required")
+	}
+	if suggestion.Category == "" {
+		suggestion.Category = models.TagCategoryOther
+	} else if !suggestion.Category.IsValid() {
+		return fmt.Errorf("invalid tag category: %s", suggestion.Category)
+	}
+
+	tag, err := s.tagRepo.FindByName(suggestion.Name)
+	if err == nil {
+		if _, err := s.tagRepo.FindContentTag(suggestion.ContentID, tag.ID); err == nil {
+			return errors.New("content already has this tag")
+		}
+		suggestion.TagID = &tag.ID
+		suggestion.Name = tag.Name
+		suggestion.Category = tag.Category
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	pending, err := s.tagRepo.HasPendingSuggestion(suggestion.ContentID, userID, suggestion.Name)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return errors.New("you already suggested this tag")
+	}
+
+	suggestion.ID = 0
+	suggestion.UserID = userID
+	suggestion.Status = models.TagSuggestionPending
+	suggestion.ReviewedByID = nil
+	suggestion.ReviewedAt = nil
+	return s.tagRepo.CreateSuggestion(suggestion)
+}
+
+// ListSuggestions lists suggestions for moderation
+func (s *TagService) ListSuggestions(status models.TagSuggestionStatus, page, pageSize int) ([]models.TagSuggestion, int64, error) {
+	return s.tagRepo.ListSuggestions(status, page, pageSize)
+}
+
+// ApproveSuggestion applies a pending suggestion. Weight defaults to the middle
+// of the scale; the spoiler flag of the suggestion is kept unless overridden.
+func (s *TagService) ApproveSuggestion(id, reviewerID uint, weight int, isSpoiler *bool) (*models.TagSuggestion, error) {
+	suggestion, err := s.pendingSuggestion(id)
+	if err != nil {
+		return nil, err
+	}
+	if weight, err = normalizeTagWeight(weight); err != nil {
+		return nil, err
+	}
+
+	var tag *models.Tag
+	if suggestion.TagID != nil {
+		tag, err = s.tagRepo.FindByID(*suggestion.TagID)
+	} else {
+		// The tag may have been created since the suggestion was made
+		tag, err = s.tagRepo.FindByName(suggestion.Name)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tag = &models.Tag{Name: suggestion.Name, Category: suggestion.Category}
+	} else if err != nil {
+		return nil, err
+	}
+
+	link := &models.ContentTag{Weight: weight, IsSpoiler: suggestion.IsSpoiler}
+	if isSpoiler != nil {
+		link.IsSpoiler = *isSpoiler
+	}
+	if err := s.tagRepo.Approve(suggestion, tag, link, reviewerID); err != nil {
+		return nil, fmt.Errorf("failed to approve suggestion: %v", err)
+	}
+	suggestion.Tag = tag
+	return suggestion, nil
+}
+
+// RejectSuggestion turns down a pending suggestion
+func (s *TagService) RejectSuggestion(id, reviewerID uint) (*models.TagSuggestion, error) {
+	suggestion, err := s.pendingSuggestion(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tagRepo.Reject(suggestion, reviewerID); err != nil {
+		return nil, err
+	}
+	return suggestion, nil
+}
+
+func (s *TagService) pendingSuggestion(id uint) (*models.TagSuggestion, error) {
+	suggestion, err := s.tagRepo.FindSuggestion(id)
+	if err != nil {
+		return nil, errors.New("suggestion not found")
+	}
+	if suggestion.Status != models.TagSuggestionPending {
+		return nil, fmt.Errorf("suggestion is already %s", suggestion.Status)
+	}
+	return suggestion, nil
+}
+
+// prepareTag normalizes the name and checks the category of a tag
+func (s *TagService) prepareTag(tag *models.Tag) error {
+	tag.Name = models.NormalizeTagName(tag.Name)
+	if tag.Name == "" {
+		return errors.New("tag name is required")
+	}
+	if tag.Category == "" {
+		tag.Category = models.TagCategoryOther
+	} else if !tag.Category.IsValid() {
+		return fmt.Errorf("invalid tag category: %s", tag.Category)
+	}
+	return nil
+}
+
+// normalizeTagWeight applies the default weight and checks the bounds
+func normalizeTagWeight(weight int) (int, error) {
+	if weight == 0 {
+		return models.TagWeightDefault, nil
+	}
+	if weight < models.TagWeightMin || weight > models.TagWeightMax {
+		return 0, fmt.Errorf("tag weight must be between %d and %d", models.TagWeightMin, models.TagWeightMax)
+	}
+	return weight, nil
+}
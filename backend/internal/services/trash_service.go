@@ -0,0 +1,247 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+// Kinds of records the trash holds
+const (
+	TrashContents = "contents"
+	TrashEpisodes = "episodes"
+	TrashGenres   = "genres"
+	TrashSeasons  = "seasons"
+)
+
+// TrashService lists, restores and purges soft-deleted records
+type TrashService struct {
+	trashRepo       *repository.TrashRepository
+	revisionService *RevisionService
+	mediaPath       string
+	retention       time.Duration
+}
+
+// TrashItem is a soft-deleted record as shown in the trash bin
+type TrashItem struct {
+	Type      string    `json:"type"`
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	ContentID *uint     `json:"content_id,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// NewTrashService creates a new TrashService
+func NewTrashService(trashRepo *repository.TrashRepository, revisionService *RevisionService, mediaPath string, retention time.Duration) *TrashService {
+	return &TrashService{
+		trashRepo:       trashRepo,
+		revisionService: revisionService,
+		mediaPath:       mediaPath,
+		retention:       retention,
+	}
+}
+
+// List lists a page of soft-deleted records of one kind, most recently deleted first
+func (s *TrashService) List(kind string, page, pageSize int) ([]TrashItem, int64, error) {
+	items := []TrashItem{}
+	switch kind {
+	case TrashContents:
+		contents, total, err := s.trashRepo.ListDeletedContents(page, pageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, content := range contents {
+			items = append(items, s.item(kind, content.ID, content.Title, nil, content.DeletedAt.Time))
+		}
+		return items, total, nil
+	case TrashEpisodes:
+		episodes, total, err := s.trashRepo.ListDeletedEpisodes(page, pageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, episode := range episodes {
+			contentID := episode.ContentID
+			name := fmt.Sprintf("S%02dE%02d %s", episode.SeasonNumber, episode.EpisodeNumber, episode.Title)
+			items = append(items, s.item(kind, episode.ID, name, &contentID, episode.DeletedAt.Time))
+		}
+		return items, total, nil
+	case TrashGenres:
+		genres, total, err := s.trashRepo.ListDeletedGenres(page, pageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, genre := range genres {
+			items = append(items, s.item(kind, genre.ID, genre.Name, nil, genre.DeletedAt.Time))
+		}
+		return items, total, nil
+	case TrashSeasons:
+		seasons, total, err := s.trashRepo.ListDeletedSeasons(page, pageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, season := range seasons {
+			name := fmt.Sprintf("%s %d", season.Name, season.Year)
+			items = append(items, s.item(kind, season.ID, name, nil, season.DeletedAt.Time))
+		}
+		return items, total, nil
+	}
+	return nil, 0, fmt.Errorf("invalid trash type: %s", kind)
+}
+
+func (s *TrashService) item(kind string, id uint, name string, contentID *uint, deletedAt time.Time) TrashItem {
+	return TrashItem{
+		Type:      kind,
+		ID:        id,
+		Name:      name,
+		ContentID: contentID,
+		DeletedAt: deletedAt,
+		PurgeAt:   deletedAt.Add(s.retention),
+	}
+}
+
+// Restore brings a record back from the trash together with the children
+// deleted along with it. Restored contents and episodes get a revision.
+func (s *TrashService) Restore(userID uint, kind string, id uint) error {
+	switch kind {
+	case TrashContents:
+		return s.revisionService.Write(userID, func(w *RevisionWriter) error {
+			if err := w.Trash.RestoreContent(id); err != nil {
+				return err
+			}
+			return w.RecordContent(id, models.RevisionRestore)
+		})
+	case TrashEpisodes:
+		return s.revisionService.Write(userID, func(w *RevisionWriter) error {
+			if err := w.Trash.RestoreEpisode(id); err != nil {
+				return err
+			}
+			return w.RecordEpisode(id, models.RevisionRestore)
+		})
+	case TrashGenres:
+		return s.trashRepo.RestoreGenre(id)
+	case TrashSeasons:
+		return s.trashRepo.RestoreSeason(id)
+	}
+	return fmt.Errorf("invalid trash type: %s", kind)
+}
+
+// Purge permanently deletes a record from the trash and removes its media files
+func (s *TrashService) Purge(kind string, id uint) error {
+	var media []string
+	var err error
+
+	switch kind {
+	case TrashContents:
+		if media, err = s.trashRepo.FindContentMedia(id); err != nil {
+			return err
+		}
+		err = s.trashRepo.PurgeContent(id)
+	case TrashEpisodes:
+		if media, err = s.trashRepo.FindEpisodeMedia(id); err != nil {
+			return err
+		}
+		err = s.trashRepo.PurgeEpisode(id)
+	case TrashGenres:
+		err = s.trashRepo.PurgeGenre(id)
+	case TrashSeasons:
+		err = s.trashRepo.PurgeSeason(id)
+	default:
+		return fmt.Errorf("invalid trash type: %s", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Files are only removed once the rows are gone for good
+	for _, path := range media {
+		s.removeMedia(path)
+	}
+	return nil
+}
+
+// PurgeExpired purges every record that has been in the trash longer than the retention period
+func (s *TrashService) PurgeExpired() (int, error) {
+	cutoff := time.Now().Add(-s.retention)
+	kinds := []struct {
+		kind  string
+		model interface{}
+	}{
+		// Contents first: purging them also takes their deleted episodes
+		{TrashContents, &models.Content{}},
+		{TrashEpisodes, &models.Episode{}},
+		{TrashGenres, &models.Genre{}},
+		{TrashSeasons, &models.Season{}},
+	}
+
+	purged := 0
+	for _, k := range kinds {
+		ids, err := s.trashRepo.ListExpiredIDs(k.model, cutoff)
+		if err != nil {
+			return purged, err
+		}
+		for _, id := range ids {
+			if err := s.Purge(k.kind, id); err != nil {
+				log.Printf("Failed to purge %s %d: %v", k.kind, id, err)
+				continue
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// StartScheduler periodically purges records past the trash retention period
+func (s *TrashService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if purged, err := s.PurgeExpired(); err != nil {
+				log.Printf("Trash purge failed: %v", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d records past the trash retention period", purged)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+// removeMedia deletes a stored media file. Original videos also take their
+// transcoded renditions with them.
+func (s *TrashService) removeMedia(stored string) {
+	// Paths are stored with either separator and sometimes with the media/ prefix
+	rel := strings.TrimPrefix(strings.ReplaceAll(stored, "\\", "/"), "media/")
+	rel = filepath.Clean(filepath.FromSlash(rel))
+	if stored == "" || strings.Contains(stored, "://") || filepath.IsAbs(rel) || strings.HasPrefix(rel, "..") {
+		return
+	}
+
+	s.removeFile(filepath.Join(s.mediaPath, rel))
+
+	if filepath.Dir(rel) == filepath.Join("videos", "original") {
+		name := strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))
+		for _, quality := range VideoQualities {
+			dir := filepath.Join(s.mediaPath, "videos", "transcoded", quality.Name)
+			s.removeFile(filepath.Join(dir, name+".m3u8"))
+			segments, _ := filepath.Glob(filepath.Join(dir, name+"_*.ts"))
+			for _, segment := range segments {
+				s.removeFile(segment)
+			}
+		}
+	}
+}
+
+func (s *TrashService) removeFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to remove media file %s: %v", path, err)
+	}
+}
@@ -81,6 +81,11 @@ func (s *UserService) Login(email, password string) (string, error) {
 		return "", errors.New("invalid credentials")
 	}
 
+	// Service accounts authenticate with API keys only
+	if user.IsServiceAccount {
+		return "", errors.New("invalid credentials")
+	}
+
 	// Check password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
 		return "", errors.New("invalid credentials")
@@ -93,13 +98,30 @@ func (s *UserService) Login(email, password string) (string, error) {
 		return "", err
 	}
 
-	// Generate JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	return s.GenerateToken(user)
+}
+
+// GenerateToken issues a signed JWT for the given user
+func (s *UserService) GenerateToken(user *models.User) (string, error) {
+	return s.generateToken(user, nil)
+}
+
+// GenerateProfileToken issues a signed JWT scoped to one of the user's viewer profiles
+func (s *UserService) GenerateProfileToken(user *models.User, profileID uint) (string, error) {
+	return s.generateToken(user, &profileID)
+}
+
+func (s *UserService) generateToken(user *models.User, profileID *uint) (string, error) {
+	claims := jwt.MapClaims{
 		"user_id": user.ID,
 		"role":    user.Role,
 		"exp":     time.Now().Add(time.Hour * 24).Unix(), // 24 hour expiry
-	})
+	}
+	if profileID != nil {
+		claims["profile_id"] = *profileID
+	}
 
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(s.jwtSecret))
 }
 
@@ -145,8 +167,15 @@ func (s *UserService) ChangePassword(userID uint, currentPassword, newPassword s
 	return s.userRepo.Update(user)
 }
 
-// ValidateToken validates a JWT token and returns the user ID and role
-func (s *UserService) ValidateToken(tokenString string) (uint, models.Role, error) {
+// TokenClaims holds the identity carried by a validated JWT
+type TokenClaims struct {
+	UserID    uint
+	Role      models.Role
+	ProfileID *uint
+}
+
+// ValidateToken validates a JWT token and returns the user ID, role and selected profile
+func (s *UserService) ValidateToken(tokenString string) (*TokenClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -155,7 +184,7 @@ func (s *UserService) ValidateToken(tokenString string) (uint, models.Role, erro
 	})
 
 	if err != nil {
-		return 0, "", err
+		return nil, err
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
@@ -168,13 +197,19 @@ func (s *UserService) ValidateToken(tokenString string) (uint, models.Role, erro
 
 		// Validate role
 		if role != models.RoleAdmin && role != models.RoleUser {
-			return 0, "", fmt.Errorf("invalid role in token")
+			return nil, fmt.Errorf("invalid role in token")
+		}
+
+		result := &TokenClaims{UserID: userID, Role: role}
+		if profileID, ok := claims["profile_id"].(float64); ok {
+			id := uint(profileID)
+			result.ProfileID = &id
 		}
 
-		return userID, role, nil
+		return result, nil
 	}
 
-	return 0, "", fmt.Errorf("invalid token")
+	return nil, fmt.Errorf("invalid token")
 }
 
 // GetUserByEmail retrieves a user by email
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/username/anime-streaming/internal/models"
+	"github.com/username/anime-streaming/internal/repository"
+)
+
+// Retention of the view rollups; trending never looks further back than 30 days
+const (
+	hourlyViewRetention = 8 * 24 * time.Hour
+	dailyViewRetention  = 400 * 24 * time.Hour
+)
+
+// trendingPreload lists the content relations loaded for trending titles
+var trendingPreload = []string{"Category", "Genres", "Titles", "Descriptions"}
+
+// ViewService records playback views and ranks titles by them
+type ViewService struct {
+	viewRepo    *repository.ViewRepository
+	dedupWindow time.Duration
+}
+
+// NewViewService creates a new ViewService
+func NewViewService(viewRepo *repository.ViewRepository, dedupWindow time.Duration) *ViewService {
+	return &ViewService{
+		viewRepo:    viewRepo,
+		dedupWindow: dedupWindow,
+	}
+}
+
+// RecordView stores a view of a title or one of its episodes. Repeated views
+// by the same viewer within the dedup window are counted once.
+func (s *ViewService) RecordView(viewerKey string, contentID uint, episodeID *uint, source models.ViewSource) {
+	now := time.Now().UTC()
+	event := &models.ViewEvent{
+		ContentID: contentID,
+		ViewerKey: viewerKey,
+		Bucket:    now.Truncate(s.dedupWindow),
+		Source:    source,
+		CreatedAt: now,
+	}
+	if episodeID != nil {
+		event.EpisodeID = *episodeID
+	}
+
+	if _, err := s.viewRepo.Record(event); err != nil {
+		log.Printf("Failed to record view of content %d: %v", contentID, err)
+	}
+}
+
+// Trending ranks the titles the viewer may see by their views in the window
+func (s *ViewService) Trending(viewer *models.Viewer, window models.TrendingWindow, limit int) ([]models.TrendingContent, error) {
+	duration := window.Duration()
+	if duration == 0 {
+		return nil, fmt.Errorf("invalid window: %s", window)
+	}
+
+	now := time.Now().UTC()
+	// The hourly counters are only kept for a little over a week
+	if duration <= 7*24*time.Hour {
+		return s.viewRepo.ListTrending(viewer, now.Add(-duration).Truncate(time.Hour), false, limit, trendingPreload...)
+	}
+	since := now.Add(-duration).Truncate(24 * time.Hour)
+	return s.viewRepo.ListTrending(viewer, since, true, limit, trendingPreload...)
+}
+
+// RollUp counts the new views and prunes data the rankings no longer need
+func (s *ViewService) RollUp() error {
+	rolled, err := s.viewRepo.RollUp()
+	if err != nil {
+		return err
+	}
+	if rolled > 0 {
+		log.Printf("Rolled up %d view events", rolled)
+	}
+
+	now := time.Now().UTC()
+	// Events are kept until their dedup window is over so a view cannot be counted twice
+	return s.viewRepo.Prune(now.Add(-2*s.dedupWindow), now.Add(-hourlyViewRetention), now.Add(-dailyViewRetention))
+}
+
+// StartScheduler periodically rolls up view events into the counters
+func (s *ViewService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.RollUp(); err != nil {
+				log.Printf("View rollup failed: %v", err)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
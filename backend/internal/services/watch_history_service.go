@@ -30,9 +30,9 @@ func NewWatchHistoryService(
 }
 
 // UpdateProgress updates the watch progress for a movie or episode
-func (s *WatchHistoryService) UpdateProgress(userID, contentID uint, episodeID *uint, progress int) error {
+func (s *WatchHistoryService) UpdateProgress(userID uint, profileID *uint, contentID uint, episodeID *uint, progress int) error {
 	// Verify content exists
-	content, err := s.contentRepo.FindByID(contentID)
+	content, err := s.contentRepo.FindByID(contentID, "Category")
 	if err != nil {
 		return err
 	}
@@ -47,37 +47,43 @@ func (s *WatchHistoryService) UpdateProgress(userID, contentID uint, episodeID *
 			return err
 		}
 
-		// Mark as completed if progress is near the end (e.g., 90% or more)
-		completed := float64(progress)/float64(episode.Duration) >= 0.9
-		return s.watchHistoryRepo.UpdateProgress(userID, contentID, episodeID, progress, completed)
+		// Mark as completed once the credits start, or near the end (e.g., 90% or more)
+		// for episodes without a credits marker
+		var completed bool
+		if credits, err := s.episodeRepo.FindChapter(episode.ID, models.ChapterCredits); err == nil {
+			completed = float64(progress) >= credits.Start
+		} else {
+			completed = float64(progress)/float64(episode.Duration) >= 0.9
+		}
+		return s.watchHistoryRepo.UpdateProgress(userID, profileID, contentID, episodeID, progress, completed)
 	}
 
 	// For movies
-	if !s.contentTypeHelper.IsMovie(content.Type) {
+	if !s.contentTypeHelper.IsMovie(content) {
 		return errors.New("content must be a movie type")
 	}
 
 	// Mark as completed if progress is near the end (e.g., 90% or more)
 	completed := float64(progress)/float64(*content.Duration*60) >= 0.9 // content.Duration is in minutes
-	return s.watchHistoryRepo.UpdateProgress(userID, contentID, nil, progress, completed)
+	return s.watchHistoryRepo.UpdateProgress(userID, profileID, contentID, nil, progress, completed)
 }
 
 // GetProgress gets the watch progress for a movie or episode
-func (s *WatchHistoryService) GetProgress(userID, contentID uint, episodeID *uint) (*models.WatchHistory, error) {
+func (s *WatchHistoryService) GetProgress(userID uint, profileID *uint, contentID uint, episodeID *uint) (*models.WatchHistory, error) {
 	if episodeID != nil {
-		return s.watchHistoryRepo.FindByUserAndEpisode(userID, *episodeID)
+		return s.watchHistoryRepo.FindByUserAndEpisode(userID, profileID, *episodeID)
 	}
-	return s.watchHistoryRepo.FindByUserAndContent(userID, contentID)
+	return s.watchHistoryRepo.FindByUserAndContent(userID, profileID, contentID)
 }
 
 // GetUserHistory gets a user's watch history with pagination
-func (s *WatchHistoryService) GetUserHistory(userID uint, page, pageSize int) ([]models.WatchHistory, int64, error) {
-	return s.watchHistoryRepo.GetUserHistory(userID, page, pageSize)
+func (s *WatchHistoryService) GetUserHistory(userID uint, profileID *uint, page, pageSize int) ([]models.WatchHistory, int64, error) {
+	return s.watchHistoryRepo.GetUserHistory(userID, profileID, page, pageSize)
 }
 
 // GetContinueWatching gets content that a user has started but not completed
-func (s *WatchHistoryService) GetContinueWatching(userID uint, limit int) ([]models.WatchHistory, error) {
-	return s.watchHistoryRepo.GetContinueWatching(userID, limit)
+func (s *WatchHistoryService) GetContinueWatching(userID uint, profileID *uint, limit int) ([]models.WatchHistory, error) {
+	return s.watchHistoryRepo.GetContinueWatching(userID, profileID, limit)
 }
 
 // DeleteHistory deletes a watch history record